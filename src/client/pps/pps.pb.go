@@ -164,6 +164,7 @@ type JobInfo struct {
 	CreatedAt    *google_protobuf1.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt" json:"created_at,omitempty"`
 	OutputCommit *pfs.Commit                 `protobuf:"bytes,8,opt,name=output_commit,json=outputCommit" json:"output_commit,omitempty"`
 	State        JobState                    `protobuf:"varint,9,opt,name=state,enum=pachyderm.pps.JobState" json:"state,omitempty"`
+	Restarts     uint64                      `protobuf:"varint,10,opt,name=restarts" json:"restarts,omitempty"`
 }
 
 func (m *JobInfo) Reset()                    { *m = JobInfo{} }
@@ -394,8 +395,16 @@ func (m *InspectJobRequest) GetJob() *Job {
 }
 
 type ListJobRequest struct {
-	Pipeline    *Pipeline     `protobuf:"bytes,1,opt,name=pipeline" json:"pipeline,omitempty"`
-	InputCommit []*pfs.Commit `protobuf:"bytes,2,rep,name=input_commit,json=inputCommit" json:"input_commit,omitempty"`
+	Pipeline          *Pipeline                   `protobuf:"bytes,1,opt,name=pipeline" json:"pipeline,omitempty"`
+	InputCommit       []*pfs.Commit               `protobuf:"bytes,2,rep,name=input_commit,json=inputCommit" json:"input_commit,omitempty"`
+	HasOutput         bool                        `protobuf:"varint,3,opt,name=has_output,json=hasOutput" json:"has_output,omitempty"`
+	Pipelines         []*Pipeline                 `protobuf:"bytes,4,rep,name=pipelines" json:"pipelines,omitempty"`
+	LatestPerPipeline bool                        `protobuf:"varint,5,opt,name=latest_per_pipeline,json=latestPerPipeline" json:"latest_per_pipeline,omitempty"`
+	SinceToken        string                      `protobuf:"bytes,6,opt,name=since_token,json=sinceToken" json:"since_token,omitempty"`
+	ExcludePipelines  []*Pipeline                 `protobuf:"bytes,7,rep,name=exclude_pipelines,json=excludePipelines" json:"exclude_pipelines,omitempty"`
+	State             []JobState                  `protobuf:"varint,8,rep,name=state,enum=pachyderm.pps.JobState" json:"state,omitempty"`
+	CreatedAfter      *google_protobuf1.Timestamp `protobuf:"bytes,9,opt,name=created_after,json=createdAfter" json:"created_after,omitempty"`
+	PipelineVersion   uint64                      `protobuf:"varint,10,opt,name=pipeline_version,json=pipelineVersion" json:"pipeline_version,omitempty"`
 }
 
 func (m *ListJobRequest) Reset()                    { *m = ListJobRequest{} }
@@ -417,6 +426,27 @@ func (m *ListJobRequest) GetInputCommit() []*pfs.Commit {
 	return nil
 }
 
+func (m *ListJobRequest) GetPipelines() []*Pipeline {
+	if m != nil {
+		return m.Pipelines
+	}
+	return nil
+}
+
+func (m *ListJobRequest) GetExcludePipelines() []*Pipeline {
+	if m != nil {
+		return m.ExcludePipelines
+	}
+	return nil
+}
+
+func (m *ListJobRequest) GetCreatedAfter() *google_protobuf1.Timestamp {
+	if m != nil {
+		return m.CreatedAfter
+	}
+	return nil
+}
+
 type GetLogsRequest struct {
 	Job *Job `protobuf:"bytes,1,opt,name=job" json:"job,omitempty"`
 }
@@ -860,69 +890,79 @@ var _API_serviceDesc = grpc.ServiceDesc{
 }
 
 var fileDescriptor0 = []byte{
-	// 1023 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xcc, 0x56, 0xef, 0x6e, 0x1a, 0x47,
-	0x10, 0x37, 0x1c, 0xff, 0x6e, 0xc0, 0x94, 0x6c, 0x43, 0x82, 0x70, 0x12, 0x5b, 0xdb, 0x54, 0x8a,
-	0x2c, 0x15, 0xa7, 0x4e, 0x15, 0xa9, 0x1f, 0x2a, 0xd5, 0x50, 0x92, 0x92, 0x12, 0xdb, 0x5d, 0xec,
-	0x56, 0x8a, 0xd4, 0xa2, 0x03, 0x16, 0xe7, 0x52, 0x8e, 0xbb, 0x72, 0x8b, 0x2a, 0x3f, 0x4b, 0xdf,
-	0xa4, 0x1f, 0xfa, 0x24, 0x7d, 0x8c, 0x3e, 0x40, 0x67, 0xf7, 0xee, 0xf0, 0xdd, 0x02, 0x96, 0x4d,
-	0xfd, 0x21, 0x1f, 0x90, 0x76, 0x67, 0x66, 0x67, 0x67, 0x7e, 0xf3, 0xfb, 0xed, 0x01, 0xf7, 0x87,
-	0x13, 0x9b, 0x4f, 0xc5, 0x81, 0xe7, 0xf9, 0xf2, 0xd7, 0xf0, 0x66, 0xae, 0x70, 0xc9, 0xb6, 0x67,
-	0x0d, 0xdf, 0x5f, 0x8e, 0xf8, 0xcc, 0x69, 0xa0, 0xb1, 0xbe, 0x73, 0xe1, 0xba, 0x17, 0x13, 0x7e,
-	0xa0, 0x9c, 0x83, 0xf9, 0xf8, 0x80, 0x3b, 0x9e, 0xb8, 0x0c, 0x62, 0xeb, 0xbb, 0xba, 0x53, 0xd8,
-	0x0e, 0xf7, 0x85, 0xe5, 0x78, 0x61, 0xc0, 0x13, 0x3d, 0xe0, 0x8f, 0x99, 0xe5, 0x79, 0x7c, 0x16,
-	0x5e, 0x56, 0x5f, 0x94, 0x30, 0xf6, 0xe5, 0x2f, 0xb0, 0xd2, 0x0e, 0x98, 0x67, 0x33, 0x6b, 0xea,
-	0x8f, 0xdd, 0x99, 0x43, 0xee, 0x43, 0xd6, 0x76, 0xac, 0x0b, 0x5e, 0x4b, 0xed, 0xa5, 0x9e, 0x99,
-	0x2c, 0xd8, 0x90, 0x0a, 0x18, 0x43, 0x67, 0x54, 0x4b, 0xef, 0x19, 0x68, 0x93, 0x4b, 0x19, 0xe7,
-	0x8b, 0x91, 0x3d, 0xad, 0x19, 0xca, 0x16, 0x6c, 0x68, 0x15, 0x8c, 0x37, 0xee, 0x80, 0x94, 0x21,
-	0x6d, 0x8f, 0xc2, 0x0c, 0xb8, 0xa2, 0x03, 0xc8, 0xbd, 0xe5, 0xe2, 0xbd, 0x3b, 0x22, 0x2f, 0xc1,
-	0xf4, 0xac, 0x99, 0xb0, 0x85, 0xed, 0x4e, 0x55, 0x40, 0xf9, 0xb0, 0xd6, 0x48, 0x40, 0xd0, 0x38,
-	0x8d, 0xfc, 0xec, 0x2a, 0x94, 0xec, 0x41, 0xd1, 0x9e, 0x0e, 0x67, 0xdc, 0xc1, 0xf2, 0xad, 0x09,
-	0x16, 0x92, 0x7a, 0x56, 0x60, 0x71, 0x13, 0xfd, 0x15, 0x0a, 0x78, 0x75, 0x67, 0xea, 0xcd, 0x05,
-	0xf9, 0x0c, 0x72, 0x43, 0xd7, 0x71, 0x6c, 0xa1, 0xae, 0x28, 0x1e, 0x16, 0x1b, 0xb2, 0xdb, 0x96,
-	0x32, 0xb1, 0xd0, 0x45, 0xbe, 0x80, 0x9c, 0xa3, 0x8a, 0x52, 0xd9, 0x8a, 0x87, 0x55, 0xad, 0x8e,
-	0xa0, 0x62, 0x16, 0x06, 0xd1, 0xbf, 0x0d, 0xc8, 0xab, 0x0b, 0xc6, 0x2e, 0x79, 0x0a, 0xc6, 0x07,
-	0x77, 0x10, 0x26, 0x27, 0xda, 0x39, 0x0c, 0x62, 0xd2, 0x2d, 0x7b, 0x15, 0x11, 0xae, 0xe1, 0x1d,
-	0x7a, 0xaf, 0x0b, 0xdc, 0xd9, 0x55, 0x28, 0x79, 0x01, 0x05, 0xcf, 0xf6, 0xf8, 0xc4, 0x9e, 0x72,
-	0x44, 0x57, 0x1e, 0x7b, 0xa8, 0x43, 0x14, 0xba, 0xd9, 0x22, 0x50, 0x02, 0x84, 0x68, 0x59, 0x93,
-	0x09, 0x6e, 0x7d, 0xa7, 0x96, 0xc1, 0x73, 0x19, 0x16, 0x37, 0x91, 0x03, 0xc8, 0xd9, 0x12, 0x1d,
-	0xbf, 0x96, 0xc5, 0x91, 0x2d, 0x27, 0x8d, 0xd0, 0x63, 0x61, 0x18, 0xf9, 0x12, 0x00, 0xcf, 0x23,
-	0xba, 0x7d, 0xd9, 0x6c, 0x6e, 0x6d, 0xb3, 0x66, 0x10, 0x25, 0x07, 0xff, 0x35, 0x00, 0x4e, 0xc4,
-	0x12, 0x7c, 0xd4, 0xb7, 0x44, 0x2d, 0xaf, 0x8e, 0xd4, 0x1b, 0x01, 0x2b, 0x1b, 0x11, 0x2b, 0x1b,
-	0x67, 0x11, 0x6d, 0x99, 0x19, 0x46, 0x1f, 0x09, 0xf2, 0x1c, 0xb6, 0xdd, 0xb9, 0xc0, 0x8b, 0xfb,
-	0xe1, 0xe8, 0x0a, 0xcb, 0xa3, 0x2b, 0x05, 0x11, 0xad, 0x68, 0x80, 0xc8, 0x3a, 0x3c, 0x5d, 0x33,
-	0x15, 0x8f, 0x56, 0xf4, 0xd3, 0x93, 0x6e, 0x16, 0x44, 0xd1, 0x6f, 0x42, 0x82, 0x8c, 0x5d, 0xd9,
-	0x5a, 0x01, 0x7b, 0xea, 0xdb, 0xb8, 0xc1, 0x29, 0x4a, 0x34, 0x1e, 0xac, 0x42, 0x63, 0xec, 0xb2,
-	0xfc, 0x87, 0x60, 0x41, 0x9f, 0x40, 0x21, 0x82, 0x9d, 0x10, 0xc8, 0x4c, 0x2d, 0x27, 0xd2, 0x88,
-	0x5a, 0xd3, 0x5f, 0x60, 0x3b, 0xf2, 0x07, 0x24, 0x7c, 0x0c, 0x99, 0x19, 0xf7, 0xdc, 0x90, 0x25,
-	0xa6, 0xea, 0x83, 0xa1, 0x81, 0x29, 0xf3, 0x6d, 0xe9, 0xf7, 0x57, 0x1a, 0x4a, 0x57, 0xf9, 0x91,
-	0x83, 0x71, 0x96, 0xa4, 0x6e, 0xca, 0x92, 0x4d, 0x29, 0xa9, 0xb1, 0xcb, 0x58, 0x66, 0xd7, 0x57,
-	0x0b, 0x76, 0x65, 0x14, 0x9e, 0x8f, 0xd6, 0x14, 0x93, 0xa4, 0xd8, 0x3e, 0x14, 0xc3, 0xa1, 0x2b,
-	0xa8, 0xb2, 0x3a, 0x54, 0x10, 0x78, 0xe5, 0x5a, 0xe3, 0x56, 0xee, 0x16, 0xdc, 0xa2, 0x3f, 0xc6,
-	0x67, 0x23, 0xe7, 0xff, 0x2d, 0x6c, 0x47, 0x98, 0xc4, 0x49, 0xb0, 0xb3, 0xb6, 0x68, 0x64, 0x42,
-	0xc9, 0x8b, 0xed, 0xe8, 0x9f, 0x69, 0xa8, 0xb4, 0xd4, 0x05, 0x52, 0x02, 0xfc, 0xf7, 0x39, 0xde,
-	0x9a, 0x84, 0x37, 0xb5, 0x99, 0xe2, 0xd3, 0x1b, 0x2a, 0xde, 0xb8, 0x4e, 0xf1, 0x99, 0x4d, 0x14,
-	0x9f, 0xbd, 0x89, 0xe2, 0xf1, 0x3b, 0x80, 0x2d, 0x0c, 0xb9, 0x1a, 0x48, 0x81, 0x05, 0x1b, 0xfa,
-	0x0e, 0xee, 0x75, 0xa6, 0xbe, 0xc7, 0x87, 0x22, 0x86, 0xce, 0xcd, 0x5e, 0xcd, 0x5d, 0x28, 0x0e,
-	0x26, 0xee, 0xf0, 0xb7, 0x7e, 0xa0, 0xed, 0xe0, 0xa5, 0x07, 0x65, 0x52, 0x72, 0xa6, 0x73, 0x28,
-	0x77, 0x6d, 0x3f, 0x9e, 0x78, 0x23, 0x29, 0x34, 0xa0, 0xa4, 0xba, 0x8e, 0x9e, 0x9b, 0xb4, 0x82,
-	0x28, 0xf1, 0xdc, 0x14, 0x55, 0x40, 0xb0, 0xa1, 0x2f, 0xa1, 0xfc, 0x9a, 0x8b, 0xae, 0x7b, 0xe1,
-	0xdf, 0xaa, 0x1f, 0xfa, 0x4f, 0x0a, 0xaa, 0x01, 0x51, 0x16, 0x45, 0xfc, 0x9f, 0xb2, 0x3f, 0x32,
-	0x05, 0xd3, 0xb7, 0xf0, 0x20, 0x9c, 0xf4, 0x5d, 0xb4, 0x87, 0x7f, 0x20, 0x3e, 0x95, 0xc3, 0xd5,
-	0x72, 0xd1, 0x2e, 0x54, 0xbf, 0xe3, 0x13, 0x7e, 0x37, 0x18, 0xee, 0x9f, 0xa8, 0x2f, 0x81, 0x62,
-	0x13, 0xa9, 0xc2, 0xbd, 0x37, 0x27, 0xcd, 0x7e, 0xef, 0xec, 0xe8, 0xac, 0xdd, 0x67, 0xe7, 0xc7,
-	0xc7, 0x9d, 0xe3, 0xd7, 0x95, 0xad, 0xa4, 0xf9, 0xd5, 0x51, 0xa7, 0x7b, 0xce, 0xda, 0x95, 0x54,
-	0xd2, 0xdc, 0x3b, 0x6f, 0xb5, 0xda, 0xbd, 0x5e, 0x25, 0xbd, 0xbf, 0x0f, 0xe6, 0xe2, 0x5f, 0x0b,
-	0x31, 0x21, 0xdb, 0xec, 0x9e, 0xb4, 0x7e, 0xc0, 0x2c, 0x05, 0xc8, 0xbc, 0xea, 0x74, 0xe5, 0x41,
-	0x5c, 0xb1, 0xf6, 0xe9, 0x49, 0x25, 0x7d, 0xf8, 0x6f, 0x06, 0x8c, 0xa3, 0xd3, 0x0e, 0x69, 0x82,
-	0xb9, 0x78, 0x3f, 0xc8, 0xae, 0x56, 0xb4, 0xfe, 0xb2, 0xd4, 0x57, 0xd0, 0x8b, 0x6e, 0x91, 0xef,
-	0x01, 0xae, 0x64, 0x46, 0xf6, 0xb4, 0x98, 0x25, 0x05, 0xd6, 0xd7, 0x7c, 0xe4, 0x30, 0x53, 0x0b,
-	0xf2, 0xa1, 0xa8, 0xc8, 0x63, 0x2d, 0x28, 0x29, 0xb6, 0xfa, 0xc3, 0xd5, 0x39, 0x7c, 0x4c, 0xd2,
-	0x81, 0x7c, 0x28, 0x91, 0xa5, 0x24, 0x49, 0xe9, 0xd4, 0x77, 0x96, 0xde, 0xed, 0xe6, 0xa5, 0xe0,
-	0xfe, 0x4f, 0xd6, 0x64, 0xce, 0xe9, 0xd6, 0xf3, 0x14, 0x39, 0x85, 0x72, 0x52, 0x34, 0xe4, 0xe9,
-	0x4a, 0x88, 0x34, 0x3e, 0x60, 0x87, 0x7a, 0xe2, 0xb6, 0xfc, 0x03, 0x8d, 0xc5, 0xfd, 0x0c, 0x9f,
-	0x68, 0x44, 0x25, 0x9f, 0xaf, 0x06, 0x4c, 0xcf, 0x79, 0xdd, 0x57, 0x01, 0x13, 0x33, 0x28, 0xc5,
-	0x29, 0x4b, 0xe8, 0x0a, 0xfc, 0xf4, 0x94, 0x8f, 0xae, 0x49, 0x29, 0x91, 0xc4, 0xf6, 0x93, 0x7c,
-	0x5f, 0x6a, 0x7f, 0xa5, 0x1c, 0xd6, 0xb7, 0xdf, 0xcc, 0xbe, 0x33, 0xf0, 0xd8, 0x20, 0xa7, 0x1c,
-	0x2f, 0xfe, 0x0b, 0x00, 0x00, 0xff, 0xff, 0x06, 0x89, 0x8e, 0x14, 0x8d, 0x0c, 0x00, 0x00,
+	// 1181 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x57, 0xdb, 0x6e, 0xdb, 0x46,
+	0x13, 0x96, 0x44, 0x1d, 0xc8, 0x91, 0xac, 0xc8, 0x9b, 0x28, 0x21, 0x94, 0x93, 0xb0, 0x7f, 0x7e,
+	0xc0, 0x35, 0x50, 0x39, 0x75, 0xda, 0x00, 0xbd, 0x28, 0x5a, 0x5b, 0x71, 0x52, 0xb9, 0x4a, 0xac,
+	0xae, 0x9d, 0x14, 0x08, 0xd0, 0x12, 0x94, 0xb4, 0xb2, 0x99, 0x90, 0xdc, 0x2d, 0xb9, 0x6a, 0xeb,
+	0x67, 0xe9, 0x9b, 0xf4, 0x51, 0x8a, 0x3e, 0x46, 0x6f, 0x0b, 0x14, 0xbb, 0x3c, 0x48, 0xa2, 0x24,
+	0xd7, 0x76, 0x73, 0xd1, 0x0b, 0x01, 0xdc, 0x99, 0x6f, 0x67, 0x67, 0xbe, 0xfd, 0x66, 0x48, 0xc1,
+	0xad, 0x91, 0xeb, 0x50, 0x5f, 0xec, 0x70, 0x1e, 0xca, 0x5f, 0x87, 0x07, 0x4c, 0x30, 0xb4, 0xc1,
+	0xed, 0xd1, 0xd9, 0xf9, 0x98, 0x06, 0x5e, 0x87, 0xf3, 0xb0, 0x75, 0xf7, 0x94, 0xb1, 0x53, 0x97,
+	0xee, 0x28, 0xe7, 0x70, 0x3a, 0xd9, 0xa1, 0x1e, 0x17, 0xe7, 0x11, 0xb6, 0xf5, 0x30, 0xeb, 0x14,
+	0x8e, 0x47, 0x43, 0x61, 0x7b, 0x3c, 0x06, 0x3c, 0xc8, 0x02, 0x7e, 0x0e, 0x6c, 0xce, 0x69, 0x10,
+	0x1f, 0xd6, 0x4a, 0x53, 0x98, 0x84, 0xf2, 0x17, 0x59, 0x71, 0x0f, 0x8c, 0x93, 0xc0, 0xf6, 0xc3,
+	0x09, 0x0b, 0x3c, 0x74, 0x0b, 0x4a, 0x8e, 0x67, 0x9f, 0x52, 0x33, 0xdf, 0xce, 0x6f, 0x19, 0x24,
+	0x5a, 0xa0, 0x06, 0x68, 0x23, 0x6f, 0x6c, 0x16, 0xda, 0xda, 0x96, 0x41, 0xe4, 0xa3, 0xc4, 0x85,
+	0x62, 0xec, 0xf8, 0xa6, 0xa6, 0x6c, 0xd1, 0x02, 0x37, 0x41, 0x3b, 0x64, 0x43, 0x54, 0x87, 0x82,
+	0x33, 0x8e, 0x23, 0x14, 0x9c, 0x31, 0x1e, 0x42, 0xf9, 0x25, 0x15, 0x67, 0x6c, 0x8c, 0x9e, 0x82,
+	0xc1, 0xed, 0x40, 0x38, 0xc2, 0x61, 0xbe, 0x02, 0xd4, 0x77, 0xcd, 0xce, 0x02, 0x05, 0x9d, 0x41,
+	0xe2, 0x27, 0x33, 0x28, 0x6a, 0x43, 0xd5, 0xf1, 0x47, 0x01, 0xf5, 0xa8, 0x2f, 0x6c, 0xd7, 0x2c,
+	0xb4, 0xf3, 0x5b, 0x3a, 0x99, 0x37, 0xe1, 0x1f, 0x40, 0x3f, 0x64, 0xc3, 0x9e, 0xcf, 0xa7, 0x02,
+	0xfd, 0x0f, 0xca, 0x23, 0xe6, 0x79, 0x8e, 0x50, 0x47, 0x54, 0x77, 0xab, 0x1d, 0x59, 0x6d, 0x57,
+	0x99, 0x48, 0xec, 0x42, 0x1f, 0x43, 0xd9, 0x53, 0x49, 0xa9, 0x68, 0xd5, 0xdd, 0x66, 0x26, 0x8f,
+	0x28, 0x63, 0x12, 0x83, 0xf0, 0xef, 0x1a, 0x54, 0xd4, 0x01, 0x13, 0x86, 0x1e, 0x81, 0xf6, 0x8e,
+	0x0d, 0xe3, 0xe0, 0x28, 0xb3, 0xef, 0x90, 0x0d, 0x89, 0x74, 0xcb, 0x5a, 0x45, 0xc2, 0x6b, 0x7c,
+	0x46, 0xb6, 0xd6, 0x94, 0x77, 0x32, 0x83, 0xa2, 0x27, 0xa0, 0x73, 0x87, 0x53, 0xd7, 0xf1, 0xa9,
+	0xa9, 0xa9, 0x6d, 0x77, 0xb2, 0x14, 0xc5, 0x6e, 0x92, 0x02, 0x25, 0x41, 0xdc, 0x0e, 0x6c, 0xd7,
+	0xa5, 0xae, 0x13, 0x7a, 0x66, 0xb1, 0x9d, 0xdf, 0x2a, 0x92, 0x79, 0x13, 0xda, 0x81, 0xb2, 0x23,
+	0xd9, 0x09, 0xcd, 0x52, 0x5b, 0x5b, 0x11, 0x34, 0x61, 0x8f, 0xc4, 0x30, 0xf4, 0x09, 0x00, 0xb7,
+	0x03, 0xea, 0x0b, 0x4b, 0x16, 0x5b, 0x5e, 0x5b, 0xac, 0x11, 0xa1, 0xe4, 0xc5, 0x7f, 0x0e, 0x30,
+	0x0a, 0xa8, 0x2d, 0xe8, 0xd8, 0xb2, 0x85, 0x59, 0x51, 0x5b, 0x5a, 0x9d, 0x48, 0x95, 0x9d, 0x44,
+	0x95, 0x9d, 0x93, 0x44, 0xb6, 0xc4, 0x88, 0xd1, 0x7b, 0x02, 0x3d, 0x86, 0x0d, 0x36, 0x15, 0x7c,
+	0x2a, 0xac, 0xf8, 0xea, 0xf4, 0xe5, 0xab, 0xab, 0x45, 0x88, 0x6e, 0x72, 0x81, 0xa5, 0x50, 0xd8,
+	0x82, 0x9a, 0x86, 0xd2, 0xd1, 0x8a, 0x7a, 0x8e, 0xa5, 0x9b, 0x44, 0x28, 0xd4, 0x02, 0x3d, 0x90,
+	0xc7, 0x06, 0x22, 0x34, 0x41, 0xd1, 0x93, 0xae, 0xf1, 0x17, 0xb1, 0x78, 0x26, 0x4c, 0x96, 0xad,
+	0xbf, 0x63, 0x43, 0xcb, 0xf1, 0x27, 0xcc, 0xcc, 0x2b, 0xa6, 0x6e, 0xaf, 0x62, 0x6a, 0xc2, 0x48,
+	0xe5, 0x5d, 0xf4, 0x80, 0x1f, 0x80, 0x9e, 0x5c, 0x09, 0x42, 0x50, 0xf4, 0x6d, 0x2f, 0xe9, 0x1f,
+	0xf5, 0x8c, 0xbf, 0x87, 0x8d, 0xc4, 0x1f, 0x09, 0xf4, 0x3e, 0x14, 0x03, 0xca, 0x59, 0xac, 0x20,
+	0x43, 0xd5, 0x48, 0x28, 0x67, 0x44, 0x99, 0xaf, 0x2a, 0xcd, 0xdf, 0x0a, 0x50, 0x9b, 0xc5, 0x9f,
+	0xb0, 0x05, 0x05, 0xe5, 0x2f, 0xab, 0xa0, 0xeb, 0xca, 0x35, 0xa3, 0x3c, 0x6d, 0x59, 0x79, 0x9f,
+	0xa6, 0xca, 0x2b, 0x2a, 0x3e, 0xef, 0xad, 0x49, 0x66, 0x51, 0x7e, 0xdb, 0x50, 0x8d, 0x05, 0xa1,
+	0xa8, 0x2a, 0x65, 0xa9, 0x82, 0xc8, 0x2b, 0x9f, 0x33, 0xba, 0x2b, 0x5f, 0x41, 0x77, 0xf8, 0xdb,
+	0xf9, 0xbb, 0x91, 0xf7, 0xff, 0x15, 0x6c, 0x24, 0x9c, 0xcc, 0x8b, 0xe0, 0xee, 0xda, 0xa4, 0x27,
+	0x8c, 0xd4, 0xf8, 0xdc, 0x0a, 0xff, 0x5a, 0x80, 0x46, 0x57, 0x1d, 0x20, 0xdb, 0x83, 0xfe, 0x38,
+	0xa5, 0xa1, 0x58, 0xa4, 0x37, 0x7f, 0xbd, 0x69, 0x50, 0xb8, 0xe6, 0x34, 0xd0, 0x2e, 0x9a, 0x06,
+	0xc5, 0xeb, 0x4c, 0x83, 0xd2, 0x65, 0xa6, 0xc1, 0x2d, 0x28, 0x4d, 0x58, 0x30, 0xa2, 0xea, 0x42,
+	0x74, 0x12, 0x2d, 0xf0, 0x5b, 0xd8, 0xec, 0xf9, 0x21, 0xa7, 0x23, 0x31, 0xc7, 0xce, 0xe5, 0x26,
+	0xea, 0x43, 0xa8, 0x0e, 0x5d, 0x36, 0x7a, 0x6f, 0x45, 0x7d, 0x1f, 0xbd, 0x05, 0x40, 0x99, 0x54,
+	0xab, 0xe3, 0xbf, 0x34, 0xa8, 0xf7, 0x9d, 0x70, 0x3e, 0xf2, 0xb5, 0x7a, 0xa1, 0x03, 0x35, 0xc7,
+	0x9f, 0x9b, 0x45, 0x85, 0xb6, 0x96, 0x9d, 0x45, 0x55, 0x05, 0x88, 0x16, 0xe8, 0x3e, 0xc0, 0x99,
+	0x1d, 0x5a, 0x91, 0x22, 0x15, 0xdd, 0x3a, 0x31, 0xce, 0xec, 0xf0, 0x48, 0x19, 0xd0, 0x67, 0x60,
+	0x24, 0xa1, 0xd7, 0xf1, 0x9d, 0x26, 0x31, 0x43, 0xa2, 0x0e, 0xdc, 0x74, 0x6d, 0x41, 0x43, 0x61,
+	0x71, 0x1a, 0x58, 0x69, 0x15, 0x25, 0x15, 0x7e, 0x33, 0x72, 0x0d, 0x68, 0x90, 0x6c, 0x95, 0xf4,
+	0x84, 0x8e, 0x3f, 0xa2, 0x96, 0x60, 0xef, 0xa9, 0xaf, 0x58, 0x37, 0x08, 0x28, 0xd3, 0x89, 0xb4,
+	0xa0, 0x67, 0xb0, 0x49, 0x7f, 0x19, 0xb9, 0xd3, 0x31, 0xb5, 0x66, 0xf9, 0x54, 0x2e, 0xce, 0xa7,
+	0x11, 0xef, 0x18, 0xa4, 0x69, 0xa5, 0x73, 0x57, 0x6f, 0x6b, 0x97, 0x98, 0xbb, 0x5f, 0xc2, 0x46,
+	0xda, 0x9b, 0x13, 0x41, 0x03, 0xd3, 0xf8, 0xc7, 0xf6, 0xac, 0x25, 0xed, 0x29, 0xf1, 0xe8, 0x23,
+	0x68, 0xa4, 0x0d, 0xf9, 0x13, 0x0d, 0x42, 0xf9, 0xe9, 0x10, 0x0d, 0xf0, 0x1b, 0x89, 0xfd, 0x4d,
+	0x64, 0xc6, 0x4f, 0xa1, 0xfe, 0x82, 0x8a, 0x3e, 0x3b, 0x0d, 0xaf, 0x24, 0x2c, 0xfc, 0x47, 0x1e,
+	0x9a, 0x51, 0xc7, 0xa6, 0x75, 0xff, 0x1b, 0xf9, 0xfc, 0xc7, 0x46, 0x29, 0x7e, 0x09, 0xb7, 0xe3,
+	0x96, 0xfb, 0x10, 0xe5, 0xe1, 0x26, 0xdc, 0x94, 0x4d, 0x96, 0x89, 0x85, 0xfb, 0xd0, 0x7c, 0x46,
+	0x5d, 0xfa, 0x61, 0x38, 0xdc, 0x3e, 0x02, 0x3d, 0x51, 0x12, 0x6a, 0xc2, 0xe6, 0xe1, 0xd1, 0xbe,
+	0x75, 0x7c, 0xb2, 0x77, 0x72, 0x60, 0x91, 0xd7, 0xaf, 0x5e, 0xf5, 0x5e, 0xbd, 0x68, 0xe4, 0x16,
+	0xcd, 0xcf, 0xf7, 0x7a, 0xfd, 0xd7, 0xe4, 0xa0, 0x91, 0x5f, 0x34, 0x1f, 0xbf, 0xee, 0x76, 0x0f,
+	0x8e, 0x8f, 0x1b, 0x85, 0xed, 0x6d, 0x30, 0xd2, 0x4f, 0x4b, 0x64, 0x40, 0x69, 0xbf, 0x7f, 0xd4,
+	0xfd, 0xa6, 0x91, 0x43, 0x3a, 0x14, 0x9f, 0xf7, 0xfa, 0x72, 0xa3, 0x0e, 0x45, 0x72, 0x30, 0x38,
+	0x6a, 0x14, 0x76, 0xff, 0x2c, 0x82, 0xb6, 0x37, 0xe8, 0xa1, 0x7d, 0x30, 0xd2, 0x41, 0x8e, 0x1e,
+	0x66, 0x92, 0xce, 0x8e, 0xf8, 0xd6, 0x0a, 0x79, 0xe1, 0x1c, 0xfa, 0x1a, 0x60, 0x36, 0xef, 0x50,
+	0x3b, 0x83, 0x59, 0x1a, 0x85, 0xad, 0x35, 0x5f, 0x1b, 0x38, 0x87, 0xba, 0x50, 0x89, 0x87, 0x1b,
+	0xba, 0x9f, 0x01, 0x2d, 0x0e, 0xbd, 0xd6, 0x9d, 0xd5, 0x31, 0x42, 0x9c, 0x43, 0x3d, 0xa8, 0xc4,
+	0x2d, 0xb2, 0x14, 0x64, 0xb1, 0x75, 0x5a, 0x77, 0x97, 0x3a, 0x74, 0xff, 0x5c, 0xd0, 0xf0, 0x8d,
+	0xed, 0x4e, 0x29, 0xce, 0x3d, 0xce, 0xa3, 0x01, 0xd4, 0x17, 0x9b, 0x06, 0x3d, 0x5a, 0x49, 0x51,
+	0x46, 0x0f, 0xad, 0xdb, 0x4b, 0x81, 0x0f, 0xe4, 0xbf, 0x1c, 0x9c, 0x43, 0xdf, 0xc1, 0x8d, 0x8c,
+	0x50, 0xd1, 0xff, 0x57, 0x13, 0x96, 0x8d, 0x79, 0xd1, 0xeb, 0x19, 0xe7, 0x10, 0x81, 0xda, 0xbc,
+	0x64, 0x11, 0x5e, 0xc1, 0x5f, 0x36, 0xe4, 0xbd, 0x0b, 0x42, 0x4a, 0x26, 0x07, 0x50, 0x5f, 0xd4,
+	0xfb, 0x52, 0xf9, 0x2b, 0xdb, 0x61, 0x7d, 0xf9, 0xfb, 0xa5, 0xb7, 0x1a, 0xe7, 0xe1, 0xb0, 0xac,
+	0x1c, 0x4f, 0xfe, 0x1e, 0x00, 0xf7, 0xeb, 0x8e, 0x26, 0x32, 0x0e, 0x00, 0x00,
 }
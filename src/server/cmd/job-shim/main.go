@@ -58,6 +58,7 @@ func do(appEnvObj interface{}) error {
 					"/pfs",
 					nil,
 					response.CommitMounts,
+					&fuse.Options{FinishCommit: true},
 					ready,
 				); err != nil {
 					errorAndExit(err.Error())
@@ -1,8 +1,14 @@
 package main
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/pachyderm/pachyderm/src/client"
 	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
@@ -26,6 +32,7 @@ import (
 	"go.pedge.io/env"
 	"go.pedge.io/lion/proto"
 	"go.pedge.io/proto/server"
+	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 	kube "k8s.io/kubernetes/pkg/client/unversioned"
 )
@@ -44,6 +51,18 @@ type appEnv struct {
 	StorageBackend  string `env:"STORAGE_BACKEND,default="`
 	DatabaseAddress string `env:"RETHINK_PORT_28015_TCP_ADDR,required"`
 	DatabaseName    string `env:"DATABASE_NAME,default=pachyderm"`
+	TablePrefix     string `env:"TABLE_PREFIX,default="`
+	DatabaseTLSCA   string `env:"RETHINK_TLS_CA,default="`
+	DatabaseTLSCert string `env:"RETHINK_TLS_CERT,default="`
+	DatabaseTLSKey  string `env:"RETHINK_TLS_KEY,default="`
+	DatabaseAuthKey string `env:"RETHINK_AUTH_KEY,default="`
+	AllowImport     bool   `env:"PERSIST_ALLOW_IMPORT,default=false"`
+	IdempotentJobs  bool   `env:"PERSIST_IDEMPOTENT_CREATE,default=false"`
+	QueryTimeoutMS  uint64 `env:"PERSIST_QUERY_TIMEOUT_MS,default=0"`
+	SubscribeBuffer int    `env:"PERSIST_SUBSCRIBE_BUFFER_SIZE,default=0"`
+	RetentionHours  uint64 `env:"PERSIST_JOB_RETENTION_HOURS,default=0"`
+	PurgeMinutes    uint64 `env:"PERSIST_PURGE_INTERVAL_MINUTES,default=0"`
+	DrainSeconds    uint64 `env:"PERSIST_DRAIN_TIMEOUT_SECONDS,default=10"`
 	KubeAddress     string `env:"KUBERNETES_PORT_443_TCP_ADDR,required"`
 	EtcdAddress     string `env:"ETCD_PORT_2379_TCP_ADDR,required"`
 	Namespace       string `env:"NAMESPACE,default=default"`
@@ -62,7 +81,11 @@ func do(appEnvObj interface{}) error {
 		if err := setClusterID(etcdClient); err != nil {
 			return err
 		}
-		if err := persist_server.InitDBs(fmt.Sprintf("%s:28015", appEnv.DatabaseAddress), appEnv.DatabaseName); err != nil {
+		persistOpts, err := persistOptions(appEnv)
+		if err != nil {
+			return err
+		}
+		if err := persist_server.InitDBs(fmt.Sprintf("%s:28015", appEnv.DatabaseAddress), appEnv.DatabaseName, persistOpts); err != nil {
 			return err
 		}
 		return nil
@@ -165,6 +188,19 @@ func do(appEnvObj interface{}) error {
 	if err != nil {
 		return err
 	}
+	// kubernetes sends SIGTERM before it kills the pod, drain the persist
+	// server's subscriptions so that they end cleanly first
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(appEnv.DrainSeconds)*time.Second)
+		if err := rethinkAPIServer.Drain(ctx); err != nil {
+			protolion.Printf("Error from rethinkAPIServer.Drain: %s", err.Error())
+		}
+		cancel()
+		os.Exit(0)
+	}()
 	return protoserver.Serve(
 		func(s *grpc.Server) {
 			pfsclient.RegisterAPIServer(s, apiServer)
@@ -172,7 +208,7 @@ func do(appEnvObj interface{}) error {
 			pfsclient.RegisterBlockAPIServer(s, blockAPIServer)
 			ppsclient.RegisterAPIServer(s, ppsAPIServer)
 			ppsserver.RegisterInternalJobAPIServer(s, ppsAPIServer)
-			persist.RegisterAPIServer(s, rethinkAPIServer)
+			persist.RegisterAPIServer(s, persist_server.NewGRPCAPIServer(rethinkAPIServer))
 		},
 		protoserver.ServeOptions{
 			Version: version.Version,
@@ -218,9 +254,57 @@ func getKubeClient(env *appEnv) (*kube.Client, error) {
 	return kube.New(config)
 }
 
-func getRethinkAPIServer(env *appEnv) (persist.APIServer, error) {
-	if err := persist_server.CheckDBs(fmt.Sprintf("%s:28015", env.DatabaseAddress), env.DatabaseName); err != nil {
+func getRethinkAPIServer(env *appEnv) (persist_server.APIServer, error) {
+	persistOpts, err := persistOptions(env)
+	if err != nil {
+		return nil, err
+	}
+	if err := persist_server.CheckDBs(fmt.Sprintf("%s:28015", env.DatabaseAddress), env.DatabaseName, persistOpts); err != nil {
 		return nil, err
 	}
-	return persist_server.NewRethinkAPIServer(fmt.Sprintf("%s:28015", env.DatabaseAddress), env.DatabaseName)
+	return persist_server.NewRethinkAPIServer(fmt.Sprintf("%s:28015", env.DatabaseAddress), env.DatabaseName, persistOpts)
+}
+
+func persistOptions(env *appEnv) (*persist_server.Options, error) {
+	tlsConfig, err := rethinkTLSConfig(env)
+	if err != nil {
+		return nil, err
+	}
+	return &persist_server.Options{
+		TablePrefix:         env.TablePrefix,
+		TLSConfig:           tlsConfig,
+		AuthKey:             env.DatabaseAuthKey,
+		AllowImport:         env.AllowImport,
+		IdempotentCreate:    env.IdempotentJobs,
+		QueryTimeout:        time.Duration(env.QueryTimeoutMS) * time.Millisecond,
+		SubscribeBufferSize: env.SubscribeBuffer,
+		NumShards:           env.NumShards,
+		JobRetention:        time.Duration(env.RetentionHours) * time.Hour,
+		PurgeInterval:       time.Duration(env.PurgeMinutes) * time.Minute,
+	}, nil
+}
+
+// rethinkTLSConfig returns nil, meaning plaintext, unless a CA cert is
+// configured. The client cert and key are optional.
+func rethinkTLSConfig(env *appEnv) (*tls.Config, error) {
+	if env.DatabaseTLSCA == "" {
+		return nil, nil
+	}
+	ca, err := ioutil.ReadFile(env.DatabaseTLSCA)
+	if err != nil {
+		return nil, err
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(ca) {
+		return nil, fmt.Errorf("no certificates found in %s", env.DatabaseTLSCA)
+	}
+	tlsConfig := &tls.Config{RootCAs: roots}
+	if env.DatabaseTLSCert != "" {
+		cert, err := tls.LoadX509KeyPair(env.DatabaseTLSCert, env.DatabaseTLSKey)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
 }
@@ -2,10 +2,13 @@ package cmds
 
 import (
 	"fmt"
+	"net"
+	"net/http"
 	"os"
 	"path"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/pachyderm/pachyderm/src/client"
 	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
@@ -13,7 +16,9 @@ import (
 	"github.com/pachyderm/pachyderm/src/server/pfs/pretty"
 	"github.com/pachyderm/pachyderm/src/server/pkg/cmd"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/cobra"
+	"go.pedge.io/lion"
 	"go.pedge.io/pkg/cobra"
 )
 
@@ -447,6 +452,26 @@ Files can be read from finished commits with get-file.`,
 		}),
 	}
 
+	var readTimeout time.Duration
+	var maxRetries int
+	var fileShards []int
+	var fromCommits []string
+	var verifyWrites bool
+	var commitNames bool
+	var latestCommit bool
+	var maxChunkSize int
+	var dryRun bool
+	var logLevel string
+	var fileMode uint32
+	var dirMode uint32
+	var watchCommits bool
+	var dirSizes bool
+	var commitPaths bool
+	var forceReadOnly bool
+	var metricsAddress string
+	var singleCommit bool
+	var decompressExtensions []string
+	var finishCommits bool
 	mount := &cobra.Command{
 		Use:   "mount path/to/mount/point",
 		Short: "Mount pfs locally.",
@@ -458,7 +483,49 @@ Files can be read from finished commits with get-file.`,
 			}
 			mounter := fuse.NewMounter(address, client.PfsAPIClient)
 			mountPoint := args[0]
-			err = mounter.Mount(mountPoint, shard(), nil, nil)
+			opts := &fuse.Options{
+				ReadTimeout:          readTimeout,
+				MaxRetries:           maxRetries,
+				VerifyWrites:         verifyWrites,
+				CommitNames:          commitNames,
+				LatestCommit:         latestCommit,
+				SingleCommit:         singleCommit,
+				MaxChunkSize:         maxChunkSize,
+				DryRun:               dryRun,
+				FileMode:             os.FileMode(fileMode),
+				DirMode:              os.FileMode(dirMode),
+				WatchCommits:         watchCommits,
+				DirSizes:             dirSizes,
+				CommitPaths:          commitPaths,
+				ForceReadOnly:        forceReadOnly,
+				DecompressExtensions: decompressExtensions,
+				FinishCommit:         finishCommits,
+			}
+			opts.LogLevel, err = lion.NameToLevel(strings.ToUpper(logLevel))
+			if err != nil {
+				return err
+			}
+			opts.FromCommits, err = parseFromCommits(fromCommits)
+			if err != nil {
+				return err
+			}
+			for _, fileShard := range fileShards {
+				shard := shard()
+				shard.FileNumber = uint64(fileShard)
+				opts.Shards = append(opts.Shards, shard)
+			}
+			if metricsAddress != "" {
+				// listen before mounting so a bad address fails the mount
+				listener, err := net.Listen("tcp", metricsAddress)
+				if err != nil {
+					return err
+				}
+				defer listener.Close()
+				opts.Metrics = fuse.NewMetrics()
+				prometheus.MustRegister(opts.Metrics)
+				go http.Serve(listener, prometheus.Handler())
+			}
+			err = mounter.Mount(mountPoint, shard(), nil, opts, nil)
 			if err != nil {
 				return err
 			}
@@ -466,6 +533,26 @@ Files can be read from finished commits with get-file.`,
 		}),
 	}
 	addShardFlags(mount)
+	mount.Flags().DurationVar(&readTimeout, "read-timeout", 0, "timeout for each remote call made to serve a read, 0 means no timeout")
+	mount.Flags().IntVar(&maxRetries, "max-retries", 3, "how many times to retry a read from pfs that fails because pachd is unavailable or timed out")
+	mount.Flags().IntSliceVar(&fileShards, "file-shards", nil, "file shards to merge into a single view, overrides --file-shard")
+	mount.Flags().StringSliceVar(&fromCommits, "from-commit", nil, "repo=commit, only show files in repo that have changed since commit, may be repeated")
+	mount.Flags().BoolVar(&verifyWrites, "verify-writes", false, "check that each file's bytes landed in pfs when it's flushed")
+	mount.Flags().BoolVar(&dryRun, "dry-run", false, "keep writes in the mount instead of sending them to pfs, for previewing a pipeline's output")
+	mount.Flags().IntVar(&maxChunkSize, "max-chunk-size", 0, "the most bytes sent to or read from pfs in one message, 0 means 1MB")
+	mount.Flags().BoolVar(&latestCommit, "latest-commit", false, "show each repo's most recently finished commit at the top of the repo")
+	mount.Flags().BoolVar(&singleCommit, "single-commit", false, "show the files of repos with a single finished commit at the top of the repo")
+	mount.Flags().BoolVar(&commitNames, "commit-names", false, "list commits as branch@start-time rather than by ID")
+	mount.Flags().Uint32Var(&fileMode, "file-mode", 0, "permission bits of files in open commits, e.g. 0640, files in finished commits don't get write bits, 0 means 0666")
+	mount.Flags().Uint32Var(&dirMode, "dir-mode", 0, "permission bits of directories in open commits, e.g. 0750, 0 means 0775")
+	mount.Flags().BoolVar(&watchCommits, "watch-commits", false, "make open commits read only in the mount as soon as they're finished")
+	mount.Flags().BoolVar(&dirSizes, "dir-sizes", false, "report the total size of the files under directories in finished commits when they're stat'd")
+	mount.Flags().BoolVar(&commitPaths, "commit-paths", false, "always read repo/commit-id/path from that commit, even with --latest-commit")
+	mount.Flags().BoolVar(&forceReadOnly, "read-only", false, "make open commits read only too, nothing can be written or removed through the mount")
+	mount.Flags().StringSliceVar(&decompressExtensions, "decompress", nil, "file extension, e.g. .gz, of gzipped files to read decompressed from finished commits, they can only be read in order, may be repeated")
+	mount.Flags().BoolVar(&finishCommits, "finish-commit", false, "finish a commit when .finish at its root is written to, once its open files are flushed")
+	mount.Flags().StringVar(&metricsAddress, "metrics-address", "", "serve prometheus metrics for the mount's operations at this address, e.g. :9090")
+	mount.Flags().StringVar(&logLevel, "log-level", "debug", "lowest level the mount logs at, per-operation logs are only built at debug")
 
 	var result []*cobra.Command
 	result = append(result, repo)
@@ -490,6 +577,18 @@ Files can be read from finished commits with get-file.`,
 	return result
 }
 
+func parseFromCommits(args []string) (map[string]string, error) {
+	result := make(map[string]string)
+	for _, arg := range args {
+		split := strings.SplitN(arg, "=", 2)
+		if len(split) != 2 || split[0] == "" || split[1] == "" {
+			return nil, fmt.Errorf("invalid --from-commit %q, expected repo=commit", arg)
+		}
+		result[split[0]] = split[1]
+	}
+	return result, nil
+}
+
 func parseCommitMounts(args []string) []*fuse.CommitMount {
 	var result []*fuse.CommitMount
 	for _, arg := range args {
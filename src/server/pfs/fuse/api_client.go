@@ -0,0 +1,244 @@
+package fuse
+
+import (
+	"io"
+	"time"
+
+	"bazil.org/fuse"
+	"github.com/cenkalti/backoff"
+	"github.com/pachyderm/pachyderm/src/client"
+	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
+	"go.pedge.io/proto/stream"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// The methods in this file mirror the ones on client.APIClient but take the
+// context of the fuse request and bound it by the mount's ReadTimeout. Calls
+// that fail transiently are retried up to the mount's MaxRetries times.
+
+// retryInitialInterval is the first wait between retries, later waits back
+// off exponentially.
+const retryInitialInterval = 100 * time.Millisecond
+
+func (f *filesystem) inspectRepo(ctx context.Context, repoName string) (*pfsclient.RepoInfo, error) {
+	var repoInfo *pfsclient.RepoInfo
+	if err := f.retry(ctx, func(ctx context.Context) error {
+		var err error
+		repoInfo, err = f.apiClient.PfsAPIClient.InspectRepo(
+			ctx,
+			&pfsclient.InspectRepoRequest{
+				Repo: client.NewRepo(repoName),
+			},
+		)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return repoInfo, nil
+}
+
+func (f *filesystem) inspectCommit(ctx context.Context, repoName string, commitID string) (*pfsclient.CommitInfo, error) {
+	var commitInfo *pfsclient.CommitInfo
+	if err := f.retry(ctx, func(ctx context.Context) error {
+		var err error
+		commitInfo, err = f.apiClient.PfsAPIClient.InspectCommit(
+			ctx,
+			&pfsclient.InspectCommitRequest{
+				Commit: client.NewCommit(repoName, commitID),
+			},
+		)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return commitInfo, nil
+}
+
+func (f *filesystem) inspectFile(ctx context.Context, repoName string, commitID string, path string, fromCommitID string, shard *pfsclient.Shard) (*pfsclient.FileInfo, error) {
+	var fileInfo *pfsclient.FileInfo
+	if err := f.retry(ctx, func(ctx context.Context) error {
+		var err error
+		fileInfo, err = f.apiClient.PfsAPIClient.InspectFile(
+			ctx,
+			&pfsclient.InspectFileRequest{
+				File:       client.NewFile(repoName, commitID, path),
+				Shard:      shard,
+				FromCommit: newFromCommit(repoName, fromCommitID),
+			},
+		)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return fileInfo, nil
+}
+
+// inspectFileUnsafe is like inspectFile, but for files in open commits.
+func (f *filesystem) inspectFileUnsafe(ctx context.Context, file *pfsclient.File) (*pfsclient.FileInfo, error) {
+	var fileInfo *pfsclient.FileInfo
+	if err := f.retry(ctx, func(ctx context.Context) error {
+		var err error
+		fileInfo, err = f.apiClient.PfsAPIClient.InspectFile(
+			ctx,
+			&pfsclient.InspectFileRequest{
+				File:   file,
+				Unsafe: true,
+			},
+		)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return fileInfo, nil
+}
+
+func (f *filesystem) listFile(ctx context.Context, repoName string, commitID string, path string, fromCommitID string, shard *pfsclient.Shard, recurse bool) ([]*pfsclient.FileInfo, error) {
+	var fileInfos *pfsclient.FileInfos
+	if err := f.retry(ctx, func(ctx context.Context) error {
+		var err error
+		fileInfos, err = f.apiClient.PfsAPIClient.ListFile(
+			ctx,
+			&pfsclient.ListFileRequest{
+				File:       client.NewFile(repoName, commitID, path),
+				Shard:      shard,
+				FromCommit: newFromCommit(repoName, fromCommitID),
+				Recurse:    recurse,
+			},
+		)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return fileInfos.FileInfo, nil
+}
+
+func (f *filesystem) listRepo(ctx context.Context) ([]*pfsclient.RepoInfo, error) {
+	var repoInfos *pfsclient.RepoInfos
+	if err := f.retry(ctx, func(ctx context.Context) error {
+		var err error
+		repoInfos, err = f.apiClient.PfsAPIClient.ListRepo(
+			ctx,
+			&pfsclient.ListRepoRequest{},
+		)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return repoInfos.RepoInfo, nil
+}
+
+func (f *filesystem) listCommit(ctx context.Context, repoName string, commitType pfsclient.CommitType) ([]*pfsclient.CommitInfo, error) {
+	var commitInfos *pfsclient.CommitInfos
+	if err := f.retry(ctx, func(ctx context.Context) error {
+		var err error
+		commitInfos, err = f.apiClient.PfsAPIClient.ListCommit(
+			ctx,
+			&pfsclient.ListCommitRequest{
+				Repo:       []*pfsclient.Repo{client.NewRepo(repoName)},
+				CommitType: commitType,
+			},
+		)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return commitInfos.CommitInfo, nil
+}
+
+func (f *filesystem) getFile(ctx context.Context, repoName string, commitID string, path string, offset int64, size int64, fromCommitID string, shard *pfsclient.Shard, writer io.Writer) error {
+	w := &countingWriter{w: writer}
+	return f.retry(ctx, func(ctx context.Context) error {
+		getFileClient, err := f.apiClient.PfsAPIClient.GetFile(
+			ctx,
+			&pfsclient.GetFileRequest{
+				File:        client.NewFile(repoName, commitID, path),
+				Shard:       shard,
+				OffsetBytes: offset,
+				SizeBytes:   size,
+				FromCommit:  newFromCommit(repoName, fromCommitID),
+			},
+		)
+		if err != nil {
+			return err
+		}
+		if err := protostream.WriteFromStreamingBytesClient(getFileClient, w); err != nil {
+			if transient(err) && w.n > 0 {
+				// we can't take back what we've already written
+				return fuse.EIO
+			}
+			return err
+		}
+		return nil
+	})
+}
+
+// retry calls do, each call with its own ReadTimeout, until it succeeds, it
+// fails with an error that isn't transient or MaxRetries retries have
+// failed. Transient errors become EIO.
+func (f *filesystem) retry(ctx context.Context, do func(ctx context.Context) error) error {
+	b := backoff.NewExponentialBackOff()
+	b.InitialInterval = retryInitialInterval
+	b.MaxElapsedTime = 0
+	b.Reset()
+	for retries := 0; ; retries++ {
+		err := f.withTimeoutDo(ctx, do)
+		if !transient(err) {
+			return err
+		}
+		if retries >= f.opts.MaxRetries {
+			return fuse.EIO
+		}
+		select {
+		case <-time.After(b.NextBackOff()):
+		case <-ctx.Done():
+			return fuse.EIO
+		}
+	}
+}
+
+func (f *filesystem) withTimeoutDo(ctx context.Context, do func(ctx context.Context) error) error {
+	ctx, cancel := f.withTimeout(ctx)
+	defer cancel()
+	return do(ctx)
+}
+
+func (f *filesystem) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if f.opts.ReadTimeout == 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, f.opts.ReadTimeout)
+}
+
+// transient reports whether err might not happen again, e.g. because a pachd
+// was restarting.
+func transient(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch grpc.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += n
+	return n, err
+}
+
+func newFromCommit(repoName string, fromCommitID string) *pfsclient.Commit {
+	if fromCommitID != "" {
+		return client.NewCommit(repoName, fromCommitID)
+	}
+	return nil
+}
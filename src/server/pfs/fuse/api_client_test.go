@@ -0,0 +1,101 @@
+package fuse
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"bazil.org/fuse"
+	"github.com/pachyderm/pachyderm/src/client"
+	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func TestRetry(t *testing.T) {
+	// failTwice fails with Unavailable the first two times it's called
+	var calls int
+	failTwice := func(ctx context.Context) error {
+		calls++
+		if calls <= 2 {
+			return grpc.Errorf(codes.Unavailable, "pachd is restarting")
+		}
+		return nil
+	}
+
+	fs := newFilesystem(nil, nil, nil, &Options{MaxRetries: 2})
+	require.NoError(t, fs.retry(context.Background(), failTwice))
+	require.Equal(t, 3, calls)
+
+	calls = 0
+	fs = newFilesystem(nil, nil, nil, &Options{MaxRetries: 1})
+	require.Equal(t, fuse.EIO, fs.retry(context.Background(), failTwice))
+	require.Equal(t, 2, calls)
+
+	calls = 0
+	errNotFound := errors.New("not found")
+	require.Equal(t, errNotFound, fs.retry(context.Background(), func(ctx context.Context) error {
+		calls++
+		return errNotFound
+	}))
+	require.Equal(t, 1, calls)
+}
+
+// listClient is a pfs client that lists a fixed set of commits, whatever the
+// context.
+type listClient struct {
+	pfsclient.APIClient
+	commitInfos []*pfsclient.CommitInfo
+}
+
+func (c *listClient) ListCommit(ctx context.Context, request *pfsclient.ListCommitRequest, opts ...grpc.CallOption) (*pfsclient.CommitInfos, error) {
+	return &pfsclient.CommitInfos{CommitInfo: c.commitInfos}, nil
+}
+
+func TestReadCommitsCancelled(t *testing.T) {
+	apiClient := &listClient{}
+	for i := 0; i < 10; i++ {
+		apiClient.commitInfos = append(apiClient.commitInfos, &pfsclient.CommitInfo{
+			Commit: client.NewCommit("repo", fmt.Sprintf("commit%d", i)),
+		})
+	}
+	fs := newFilesystem(apiClient, nil, nil, nil)
+	d := &directory{fs: fs, Node: Node{File: client.NewFile("repo", "", "")}}
+	dirents, err := d.readCommits(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 10, len(dirents))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = d.readCommits(ctx)
+	require.Equal(t, errInterrupted, err)
+}
+
+// hungClient is a pfs client whose inspects wait until they're cancelled,
+// like a pachd that's stopped answering.
+type hungClient struct {
+	pfsclient.APIClient
+}
+
+func (c *hungClient) InspectRepo(ctx context.Context, request *pfsclient.InspectRepoRequest, opts ...grpc.CallOption) (*pfsclient.RepoInfo, error) {
+	<-ctx.Done()
+	return nil, grpc.Errorf(codes.DeadlineExceeded, "%v", ctx.Err())
+}
+
+func (c *hungClient) InspectCommit(ctx context.Context, request *pfsclient.InspectCommitRequest, opts ...grpc.CallOption) (*pfsclient.CommitInfo, error) {
+	<-ctx.Done()
+	return nil, grpc.Errorf(codes.DeadlineExceeded, "%v", ctx.Err())
+}
+
+func TestLookUpTimeout(t *testing.T) {
+	fs := newFilesystem(&hungClient{}, nil, nil, &Options{ReadTimeout: time.Millisecond})
+	root := &directory{fs: fs, Node: Node{File: client.NewFile("", "", "")}}
+	_, err := root.Lookup(context.Background(), "repo")
+	require.Equal(t, fuse.EIO, err)
+	repo := &directory{fs: fs, Node: Node{File: client.NewFile("repo", "", "")}}
+	_, err = repo.Lookup(context.Background(), "commit")
+	require.Equal(t, fuse.EIO, err)
+}
@@ -0,0 +1,109 @@
+package fuse
+
+import (
+	"io"
+	"sync"
+
+	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
+)
+
+// appendWriter is shared by the handles that have a file open with O_APPEND.
+// pfs appends each PutFileWriter's bytes to the file, so handles with
+// writers of their own would clobber each other: the offsets the kernel
+// gives them come from a size none of them wrote on their own, and their
+// bytes land in whatever order the writers close. Appends through an
+// appendWriter go through one writer, in the order the mount sees them.
+//
+// This only orders appends within a mount, appends from other mounts or
+// other pfs clients are ordered by pfs.
+type appendWriter struct {
+	file *pfsclient.File
+	// refs is the number of handles using the appendWriter, guarded by the
+	// filesystem's handlesLock.
+	refs int
+	w    io.WriteCloser
+	// size is the number of bytes appended through the mount while the
+	// file has been open for appending.
+	size int64
+	// err is the first error from writing to pfs, the shared writer is gone
+	// so every append and close returns it.
+	err  error
+	lock sync.Mutex
+}
+
+// appendWriter returns file's appendWriter, creating it if this is the first
+// handle to append to file. Each call must be matched by a call to
+// releaseAppendWriter.
+func (f *filesystem) appendWriter(file *pfsclient.File) *appendWriter {
+	f.handlesLock.Lock()
+	defer f.handlesLock.Unlock()
+	a, ok := f.appends[key(file)]
+	if !ok {
+		a = &appendWriter{file: file}
+		f.appends[key(file)] = a
+	}
+	a.refs++
+	return a
+}
+
+func (f *filesystem) releaseAppendWriter(a *appendWriter) {
+	f.handlesLock.Lock()
+	defer f.handlesLock.Unlock()
+	a.refs--
+	if a.refs == 0 {
+		delete(f.appends, key(a.file))
+	}
+}
+
+// append writes data after everything appended before it, and returns the
+// end of the write and how many bytes were written.
+func (a *appendWriter) append(f *filesystem, data []byte) (int64, int, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.err != nil {
+		return 0, 0, a.err
+	}
+	if a.w == nil {
+		w, err := f.putFileWriter(a.file)
+		if err != nil {
+			return 0, 0, err
+		}
+		a.w = w
+	}
+	written, err := writeChunks(a.w, data, f.maxChunkSize())
+	a.size += int64(written)
+	if err != nil {
+		a.err = err
+		a.w.Close()
+		a.w = nil
+		return a.size, written, err
+	}
+	return a.size, written, nil
+}
+
+// close closes the shared writer so that everything appended so far is put,
+// the next append opens a new one. closed is false if there was no writer.
+func (a *appendWriter) close() (closed bool, retErr error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.err != nil {
+		return false, a.err
+	}
+	if a.w == nil {
+		return false, nil
+	}
+	w := a.w
+	a.w = nil
+	if err := w.Close(); err != nil {
+		a.err = err
+		return true, err
+	}
+	return true, nil
+}
+
+// open returns true if some appends haven't been put yet.
+func (a *appendWriter) open() bool {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	return a.w != nil
+}
@@ -0,0 +1,56 @@
+package fuse
+
+import (
+	"testing"
+	"time"
+
+	"bazil.org/fuse"
+	"github.com/pachyderm/pachyderm/src/client"
+	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"go.pedge.io/proto/time"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// inspectClient is a pfs client that inspects every file as fileInfo.
+type inspectClient struct {
+	pfsclient.APIClient
+	fileInfo *pfsclient.FileInfo
+}
+
+func (c *inspectClient) InspectFile(ctx context.Context, request *pfsclient.InspectFileRequest, opts ...grpc.CallOption) (*pfsclient.FileInfo, error) {
+	return c.fileInfo, nil
+}
+
+func TestAttrTimes(t *testing.T) {
+	finished := time.Unix(1000, 0)
+	fs := newFilesystem(&inspectClient{fileInfo: &pfsclient.FileInfo{
+		SizeBytes: 3,
+		Modified:  prototime.TimeToTimestamp(finished),
+	}}, nil, nil, nil)
+	f := &file{
+		directory: directory{
+			fs:   fs,
+			Node: Node{File: client.NewFile("repo", "commit", "file")},
+		},
+		mode: 0444,
+	}
+	a := &fuse.Attr{}
+	require.NoError(t, f.Attr(context.Background(), a))
+	require.True(t, finished.Equal(a.Mtime))
+	require.True(t, finished.Equal(a.Ctime))
+	require.True(t, finished.Equal(a.Crtime))
+
+	d := &directory{
+		fs: fs,
+		Node: Node{
+			File:     client.NewFile("repo", "commit", ""),
+			Modified: prototime.TimeToTimestamp(finished),
+		},
+	}
+	a = &fuse.Attr{}
+	require.NoError(t, d.Attr(context.Background(), a))
+	require.True(t, finished.Equal(a.Ctime))
+	require.True(t, finished.Equal(a.Crtime))
+}
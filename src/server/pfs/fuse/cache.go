@@ -0,0 +1,77 @@
+package fuse
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// cache is a map whose entries expire ttl after they're set. Expired entries
+// are dropped lazily, on get and when the map grows past cacheSweepSize.
+type cache struct {
+	ttl     time.Duration
+	entries map[string]cacheEntry
+	lock    sync.Mutex
+}
+
+type cacheEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+const cacheSweepSize = 1024
+
+func newCache(ttl time.Duration) *cache {
+	return &cache{
+		ttl:     ttl,
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+func (c *cache) get(key string) (interface{}, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (c *cache) set(key string, value interface{}) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	now := time.Now()
+	if len(c.entries) >= cacheSweepSize {
+		for key, entry := range c.entries {
+			if now.After(entry.expires) {
+				delete(c.entries, key)
+			}
+		}
+	}
+	c.entries[key] = cacheEntry{
+		value:   value,
+		expires: now.Add(c.ttl),
+	}
+}
+
+func (c *cache) delete(key string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.entries, key)
+}
+
+// deletePrefix deletes every entry whose key starts with prefix.
+func (c *cache) deletePrefix(prefix string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for key := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.entries, key)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package fuse
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+func TestCache(t *testing.T) {
+	c := newCache(time.Hour)
+	_, ok := c.get("foo")
+	require.False(t, ok)
+	c.set("foo", 1)
+	value, ok := c.get("foo")
+	require.True(t, ok)
+	require.Equal(t, 1, value)
+	c.delete("foo")
+	_, ok = c.get("foo")
+	require.False(t, ok)
+}
+
+func TestCacheExpires(t *testing.T) {
+	c := newCache(time.Millisecond)
+	c.set("foo", 1)
+	time.Sleep(2 * time.Millisecond)
+	_, ok := c.get("foo")
+	require.False(t, ok)
+	for i := 0; i < 2*cacheSweepSize; i++ {
+		c.set(string(rune(i)), i)
+		time.Sleep(time.Microsecond)
+	}
+	require.True(t, len(c.entries) <= cacheSweepSize+1)
+}
+
+func TestCachedInspectFile(t *testing.T) {
+	// No client, a cache miss would panic.
+	f := newFilesystem(nil, nil, nil, nil)
+	file := &pfsclient.File{Commit: &pfsclient.Commit{Repo: &pfsclient.Repo{Name: "repo"}, ID: "commit"}, Path: "file"}
+	fileInfo := &pfsclient.FileInfo{File: file, SizeBytes: 3}
+	f.fileInfos.set(aliasKey(file, ""), fileInfo)
+	result, err := f.cachedInspectFile(context.Background(), file, "", "", nil)
+	require.NoError(t, err)
+	require.Equal(t, fileInfo, result)
+}
+
+func TestFileInfoCacheAliases(t *testing.T) {
+	apiClient := &countingListClient{}
+	f := newFilesystem(apiClient, nil, nil, nil)
+	// aliases of a commit can list different files, so they cache apart
+	for _, alias := range []string{"in", "prev"} {
+		d := &directory{fs: f, Node: Node{File: client.NewFile("repo", "commit", ""), RepoAlias: alias}}
+		_, err := d.readFiles(context.Background())
+		require.NoError(t, err)
+	}
+	require.Equal(t, 2, apiClient.calls)
+	_, ok := f.fileInfos.get(aliasKey(client.NewFile("repo", "commit", "file"), "in"))
+	require.True(t, ok)
+	_, ok = f.fileInfos.get(aliasKey(client.NewFile("repo", "commit", "file"), "prev"))
+	require.True(t, ok)
+	_, ok = f.fileInfos.get(aliasKey(client.NewFile("repo", "commit", "file"), "other"))
+	require.False(t, ok)
+}
+
+func TestInvalidateCache(t *testing.T) {
+	f := newFilesystem(nil, nil, nil, nil)
+	files := []*pfsclient.File{
+		client.NewFile("foo", "commit1", "file"),
+		client.NewFile("foo", "commit2", "file"),
+		client.NewFile("foobar", "commit1", "file"),
+	}
+	cached := func() []bool {
+		var result []bool
+		for _, file := range files {
+			_, ok := f.misses.get(aliasKey(file, ""))
+			result = append(result, ok)
+		}
+		return result
+	}
+	for _, file := range files {
+		f.misses.set(aliasKey(file, ""), struct{}{})
+	}
+	f.invalidateCache("foo", "commit1")
+	require.Equal(t, []bool{false, true, true}, cached())
+	f.invalidateCache("foo", "")
+	require.Equal(t, []bool{false, false, true}, cached())
+	f.invalidateCache("", "")
+	require.Equal(t, []bool{false, false, false}, cached())
+}
+
+// countingListClient is a pfs client that lists one file in every directory
+// and counts the calls.
+type countingListClient struct {
+	pfsclient.APIClient
+	calls int
+}
+
+func (c *countingListClient) ListFile(ctx context.Context, request *pfsclient.ListFileRequest, opts ...grpc.CallOption) (*pfsclient.FileInfos, error) {
+	c.calls++
+	return &pfsclient.FileInfos{FileInfo: []*pfsclient.FileInfo{{
+		File:     client.NewFile(request.File.Commit.Repo.Name, request.File.Commit.ID, "file"),
+		FileType: pfsclient.FileType_FILE_TYPE_REGULAR,
+	}}}, nil
+}
+
+func TestListingCache(t *testing.T) {
+	apiClient := &countingListClient{}
+	f := newFilesystem(apiClient, nil, nil, nil)
+	d := &directory{fs: f, Node: Node{File: client.NewFile("repo", "commit", "")}}
+	for i := 0; i < 3; i++ {
+		dirents, err := d.readFiles(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 1, len(dirents))
+		require.Equal(t, "file", dirents[0].Name)
+	}
+	require.Equal(t, 1, apiClient.calls)
+	f.invalidateCache("repo", "commit")
+	_, err := d.readFiles(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, apiClient.calls)
+
+	// open commits are listed every time
+	d.Write = true
+	for i := 0; i < 2; i++ {
+		_, err := d.readFiles(context.Background())
+		require.NoError(t, err)
+	}
+	require.Equal(t, 4, apiClient.calls)
+}
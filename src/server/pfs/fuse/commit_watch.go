@@ -0,0 +1,153 @@
+package fuse
+
+import (
+	"time"
+
+	"bazil.org/fuse/fs"
+	"github.com/pachyderm/pachyderm/src/client"
+	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
+	"go.pedge.io/lion/proto"
+)
+
+// commitWatchRetryInterval is how long a watcher waits before listing again
+// when a child of its commit finished first, the child would keep coming back
+// from a blocking ListCommit until the commit itself finishes.
+const commitWatchRetryInterval = time.Second
+
+// commitWatch is the watcher for one open commit, see watchCommit.
+type commitWatch struct {
+	// entries are the lookups that returned the commit's directory, they're
+	// invalidated in the kernel once the commit finishes.
+	entries []commitEntry
+}
+
+type commitEntry struct {
+	parent fs.Node
+	name   string
+	node   fs.Node
+}
+
+// watchCommit makes node, the directory of an open commit that was looked up
+// as name in parent, read only once its commit finishes. It does nothing
+// unless Options.WatchCommits is set.
+func (f *filesystem) watchCommit(parent fs.Node, name string, node *directory) {
+	if !f.opts.WatchCommits || node.File.Commit.ID == "" {
+		return
+	}
+	commit := client.NewCommit(node.File.Commit.Repo.Name, node.File.Commit.ID)
+	entry := commitEntry{parent: parent, name: name, node: node}
+	f.watchesLock.Lock()
+	defer f.watchesLock.Unlock()
+	if watch, ok := f.watches[commitKey(commit)]; ok {
+		watch.entries = append(watch.entries, entry)
+		return
+	}
+	f.watches[commitKey(commit)] = &commitWatch{entries: []commitEntry{entry}}
+	go f.watch(commit)
+}
+
+// watch waits for commit to finish, then marks it finished and tells the
+// kernel to drop what it has cached about its directory.
+func (f *filesystem) watch(commit *pfsclient.Commit) {
+	err := f.waitForFinish(commit)
+	f.watchesLock.Lock()
+	watch := f.watches[commitKey(commit)]
+	delete(f.watches, commitKey(commit))
+	if err == nil {
+		f.finished[commitKey(commit)] = true
+	}
+	f.watchesLock.Unlock()
+	if err != nil {
+		if f.ctx.Err() == nil {
+			// the next lookup of the commit starts a new watcher
+			protolion.Error(&CommitWatch{commit, err.Error()})
+		}
+		return
+	}
+	f.invalidateCache(commit.Repo.Name, commit.ID)
+	if f.server != nil {
+		for _, entry := range watch.entries {
+			// These fail with ErrNotCached if the kernel has already
+			// forgotten the node, which is what we want anyway.
+			f.server.InvalidateNodeAttr(entry.node)
+			f.server.InvalidateEntry(entry.parent, entry.name)
+		}
+	}
+	protolion.Info(&CommitWatch{Commit: commit})
+}
+
+// Only commits finishing are watched, files showing up in an open commit
+// aren't notified. pfs has no feed of the files put in an open commit, the
+// blocking ListCommit above only returns commits once they finish, and even
+// with one the kernel wouldn't pass it on: invalidating an entry doesn't
+// raise inotify events, and the vendored fuse library answers FUSE_POLL with
+// ENOSYS, which the kernel takes to mean always ready. Open commits'
+// directories aren't cached in the kernel or by readFiles, so a new listing
+// does show new files, tools just have to list rather than wait.
+
+// waitForFinish blocks until commit is finished or cancelled. Blocking
+// ListCommit returns finished commits that come after from, so it's used as
+// a changefeed: commits that finished on other branches are added to from so
+// that they aren't returned again.
+func (f *filesystem) waitForFinish(commit *pfsclient.Commit) error {
+	commitInfo, err := f.apiClient.PfsAPIClient.InspectCommit(
+		f.ctx,
+		&pfsclient.InspectCommitRequest{Commit: commit},
+	)
+	if err != nil {
+		return err
+	}
+	if commitInfo.CommitType == pfsclient.CommitType_COMMIT_TYPE_READ {
+		return nil
+	}
+	var from []*pfsclient.Commit
+	if commitInfo.ParentCommit != nil {
+		from = append(from, commitInfo.ParentCommit)
+	}
+	for {
+		commitInfos, err := f.apiClient.PfsAPIClient.ListCommit(
+			f.ctx,
+			&pfsclient.ListCommitRequest{
+				Repo:       []*pfsclient.Repo{commit.Repo},
+				CommitType: pfsclient.CommitType_COMMIT_TYPE_READ,
+				FromCommit: from,
+				All:        true,
+				Block:      true,
+			},
+		)
+		if err != nil {
+			return err
+		}
+		var child bool
+		for _, commitInfo := range commitInfos.CommitInfo {
+			if commitInfo.Commit.ID == commit.ID {
+				return nil
+			}
+			if commitInfo.ParentCommit != nil && commitInfo.ParentCommit.ID == commit.ID {
+				// Listing stops at commits in from, so adding a child
+				// would hide the commit we're waiting for.
+				child = true
+				continue
+			}
+			from = append(from, commitInfo.Commit)
+		}
+		if child {
+			select {
+			case <-f.ctx.Done():
+				return f.ctx.Err()
+			case <-time.After(commitWatchRetryInterval):
+			}
+		}
+	}
+}
+
+// commitFinished returns true if a watcher has seen commit finish.
+func (f *filesystem) commitFinished(commit *pfsclient.Commit) bool {
+	f.watchesLock.Lock()
+	defer f.watchesLock.Unlock()
+	return f.finished[commitKey(commit)]
+}
+
+func commitKey(commit *pfsclient.Commit) string {
+	return commit.Repo.Name + "/" + commit.ID
+}
@@ -0,0 +1,131 @@
+package fuse
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"syscall"
+
+	"bazil.org/fuse"
+	"golang.org/x/net/context"
+)
+
+// decompressor serves reads of a gzipped file in a finished commit as the
+// file's decompressed bytes. gzip can only be decompressed from the start,
+// so the handle is opened non-seekable and reads have to come in order, a
+// read anywhere but where the last one ended returns ESPIPE. A read at 0
+// starts over.
+type decompressor struct {
+	// fetch writes size bytes of the compressed file, from offset on, to w.
+	fetch func(ctx context.Context, offset int64, size int64, w io.Writer) error
+	// compressedSize is the size of the file in pfs.
+	compressedSize int64
+	chunkSize      int64
+	// ctx is the context of the read being served, fetch is only called
+	// while one is.
+	ctx context.Context
+	gz  *gzip.Reader
+	// offset is the number of decompressed bytes read so far.
+	offset int64
+	source compressedReader
+}
+
+// compressedReader reads the compressed file a chunk at a time.
+type compressedReader struct {
+	d      *decompressor
+	offset int64
+	buffer bytes.Buffer
+}
+
+// decompresses returns true if reads of filePath are decompressed, see
+// Options.DecompressExtensions.
+func (f *filesystem) decompresses(filePath string) bool {
+	for _, extension := range f.opts.DecompressExtensions {
+		if strings.HasSuffix(filePath, extension) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *file) newDecompressor() *decompressor {
+	return &decompressor{
+		fetch: func(ctx context.Context, offset int64, size int64, w io.Writer) error {
+			return f.fs.getFile(
+				ctx,
+				f.File.Commit.Repo.Name,
+				f.File.Commit.ID,
+				f.File.Path,
+				offset,
+				size,
+				f.fs.getFromCommitID(f.getRepoOrAliasName()),
+				f.shard(f.File.Path),
+				w,
+			)
+		},
+		compressedSize: f.size,
+		chunkSize:      int64(f.fs.maxChunkSize()),
+	}
+}
+
+// read returns up to size decompressed bytes from offset, fewer only at the
+// end of the file.
+func (d *decompressor) read(ctx context.Context, offset int64, size int) ([]byte, error) {
+	if offset == 0 && d.offset != 0 {
+		d.gz = nil
+		d.offset = 0
+	}
+	if offset != d.offset {
+		return nil, fuse.Errno(syscall.ESPIPE)
+	}
+	d.ctx = ctx
+	defer func() { d.ctx = nil }()
+	if d.gz == nil {
+		d.source = compressedReader{d: d}
+		gz, err := gzip.NewReader(&d.source)
+		if err == io.EOF {
+			// an empty file decompresses to nothing
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		d.gz = gz
+	}
+	data := make([]byte, size)
+	n := 0
+	for n < size {
+		m, err := d.gz.Read(data[n:])
+		n += m
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			d.offset += int64(n)
+			return nil, err
+		}
+	}
+	d.offset += int64(n)
+	return data[:n], nil
+}
+
+func (r *compressedReader) Read(p []byte) (int, error) {
+	if r.buffer.Len() == 0 {
+		if r.offset >= r.d.compressedSize {
+			return 0, io.EOF
+		}
+		chunkSize := r.d.chunkSize
+		if chunkSize > r.d.compressedSize-r.offset {
+			chunkSize = r.d.compressedSize - r.offset
+		}
+		if err := r.d.fetch(r.d.ctx, r.offset, chunkSize, &r.buffer); err != nil {
+			return 0, err
+		}
+		if r.buffer.Len() == 0 {
+			return 0, io.EOF
+		}
+		r.offset += int64(r.buffer.Len())
+	}
+	return r.buffer.Read(p)
+}
@@ -0,0 +1,59 @@
+package fuse
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"syscall"
+	"testing"
+
+	"bazil.org/fuse"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"golang.org/x/net/context"
+)
+
+func newTestDecompressor(compressed []byte) *decompressor {
+	return &decompressor{
+		fetch: func(ctx context.Context, offset int64, size int64, w io.Writer) error {
+			_, err := w.Write(compressed[offset : offset+size])
+			return err
+		},
+		compressedSize: int64(len(compressed)),
+		// small chunks so the gzip stream spans several fetches
+		chunkSize: 7,
+	}
+}
+
+func TestDecompressor(t *testing.T) {
+	data := strings.Repeat("foobarbaz", 100)
+	var compressed bytes.Buffer
+	w := gzip.NewWriter(&compressed)
+	_, err := w.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	d := newTestDecompressor(compressed.Bytes())
+
+	var read []byte
+	for {
+		chunk, err := d.read(context.Background(), int64(len(read)), 64)
+		require.NoError(t, err)
+		if len(chunk) == 0 {
+			break
+		}
+		read = append(read, chunk...)
+	}
+	require.Equal(t, data, string(read))
+
+	// reads have to pick up where the last one ended, except at 0
+	_, err = d.read(context.Background(), 10, 64)
+	require.Equal(t, fuse.Errno(syscall.ESPIPE), err)
+	chunk, err := d.read(context.Background(), 0, 9)
+	require.NoError(t, err)
+	require.Equal(t, "foobarbaz", string(chunk))
+
+	d = newTestDecompressor(nil)
+	chunk, err = d.read(context.Background(), 0, 64)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(chunk))
+}
@@ -4,9 +4,10 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"math"
 	"os"
 	"path"
-	"path/filepath"
 	"strings"
 	"sync"
 	"syscall"
@@ -17,6 +18,8 @@ import (
 	"github.com/pachyderm/pachyderm/src/client"
 	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
 	"github.com/pachyderm/pachyderm/src/client/pkg/uuid"
+	pfsserver "github.com/pachyderm/pachyderm/src/server/pfs"
+	"go.pedge.io/lion"
 	"go.pedge.io/lion/proto"
 	"go.pedge.io/proto/time"
 	"golang.org/x/net/context"
@@ -24,38 +27,147 @@ import (
 	"google.golang.org/grpc/codes"
 )
 
+// negativeLookupTTL is how long a failed lookup in a read only commit is
+// remembered before we ask pfs again.
+const negativeLookupTTL = time.Second
+
+// fileInfoTTL is how long the FileInfos listed by ReadDirAll in a read only
+// commit are used to answer the Lookup and Attr calls that follow it.
+const fileInfoTTL = time.Second
+
+// listingTTL is how long readFiles reuses a directory's listing in a read
+// only commit, it's longer than fileInfoTTL so that listing the same
+// directory over and over, as tab completion does, only asks pfs once.
+const listingTTL = 5 * time.Second
+
+// dirSizeTTL is how long a directory's size is cached for Options.DirSizes,
+// it's longer than fileInfoTTL because each one is a recursive listing and
+// read only commits don't change.
+const dirSizeTTL = time.Minute
+
+// modeTTL is how long the modes readOnlyMode sniffs are cached, like
+// dirSizeTTL it's long because files in read only commits don't change.
+const modeTTL = time.Minute
+
+// finishFileName is the control file at the root of writable commits, any
+// write to it flushes every open handle in the commit, see handle.finish.
+const finishFileName = ".finish"
+
+const shebang = "#!"
+
+// defaultMaxChunkSize is used when Options.MaxChunkSize isn't set, it's well
+// under gRPC's default 4MB message limit.
+const defaultMaxChunkSize = 1024 * 1024
+
+// accessWrite is W_OK in an access(2) mask, syscall doesn't define it.
+const accessWrite = 0x2
+
+// errInterrupted is returned by listings whose request was cancelled, the
+// kernel has stopped waiting for them so we stop building them.
+var errInterrupted = fuse.Errno(syscall.EINTR)
+
+const (
+	commitNameSeparator  = "@"
+	commitNameTimeFormat = "2006-01-02T15:04:05.000000"
+)
+
 type filesystem struct {
 	apiClient client.APIClient
 	Filesystem
-	inodes   map[string]uint64
+	opts Options
+	// inodes are the inode numbers of paths the kernel holds nodes for,
+	// keyed by key(file), guarded by lock.
+	inodes    map[string]*inodeRef
+	nextInode uint64
+	// misses caches recent lookups in read only commits that got NotFound,
+	// keyed by aliasKey.
+	misses *cache
+	// fileInfos caches the regular files listed by readFiles in read only
+	// commits, keyed by aliasKey.
+	fileInfos *cache
+	// dirSizes caches the sizes of directories in read only commits for
+	// Options.DirSizes, keyed by key(file).
+	dirSizes *cache
+	// modes caches the modes of files in read only commits, keyed by
+	// aliasKey, so that each is only sniffed once rather than once per
+	// node, see readOnlyMode.
+	modes *cache
+	// listings caches the FileInfos readFiles lists in read only commits,
+	// keyed by listingKey. Writable commits aren't cached, so there's
+	// nothing to invalidate when a directory is written to.
+	listings *cache
+	// preview has the writes of a DryRun mount.
+	preview  *preview
 	lock     sync.RWMutex
 	handleID string
+	// handles are all the open handles in the filesystem, so that writes to
+	// the control file can flush them.
+	handles map[*handle]bool
+	// appends are the appendWriters of files open with O_APPEND, keyed by
+	// key(file), guarded by handlesLock.
+	appends     map[string]*appendWriter
+	handlesLock sync.Mutex
+	// watches are the running commit watchers and finished the commits
+	// they've seen finish, both keyed by commitKey and guarded by
+	// watchesLock, see watchCommit.
+	watches     map[string]*commitWatch
+	finished    map[string]bool
+	watchesLock sync.Mutex
+	// server is used to invalidate kernel caches, it's nil in tests that
+	// don't mount.
+	server *fs.Server
+	// ctx is cancelled once the filesystem is unmounted, which stops the
+	// commit watchers.
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
 func newFilesystem(
 	pfsAPIClient pfsclient.APIClient,
 	shard *pfsclient.Shard,
 	commitMounts []*CommitMount,
+	opts *Options,
 ) *filesystem {
+	if opts == nil {
+		opts = &Options{}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
 	return &filesystem{
 		apiClient: client.APIClient{PfsAPIClient: pfsAPIClient},
 		Filesystem: Filesystem{
 			shard,
 			commitMounts,
 		},
-		inodes:   make(map[string]uint64),
-		lock:     sync.RWMutex{},
-		handleID: uuid.NewWithoutDashes(),
+		opts:   *opts,
+		inodes: make(map[string]*inodeRef),
+		// 0 tells the fuse library to make up a number
+		nextInode: 1,
+		misses:    newCache(negativeLookupTTL),
+		fileInfos: newCache(fileInfoTTL),
+		dirSizes:  newCache(dirSizeTTL),
+		modes:     newCache(modeTTL),
+		listings:  newCache(listingTTL),
+		preview:   newPreview(),
+		lock:      sync.RWMutex{},
+		handleID:  uuid.NewWithoutDashes(),
+		handles:   make(map[*handle]bool),
+		appends:   make(map[string]*appendWriter),
+		watches:   make(map[string]*commitWatch),
+		finished:  make(map[string]bool),
+		ctx:       ctx,
+		cancel:    cancel,
 	}
 }
 
 func (f *filesystem) Root() (result fs.Node, retErr error) {
-	defer func() {
-		protolion.Debug(&Root{&f.Filesystem, getNode(result), errorToString(retErr)})
-	}()
+	if f.debug() {
+		defer func() {
+			protolion.Debug(&Root{&f.Filesystem, getNode(result), errorToString(retErr)})
+		}()
+	}
 	return &directory{
-		f,
-		Node{
+		fs: f,
+		Node: Node{
 			File: &pfsclient.File{
 				Commit: &pfsclient.Commit{
 					Repo: &pfsclient.Repo{},
@@ -68,28 +180,107 @@ func (f *filesystem) Root() (result fs.Node, retErr error) {
 type directory struct {
 	fs *filesystem
 	Node
+	// parent is the directory d was looked up in, nil for the root.
+	parent *directory
+	// inodeRef is held once hasInode is set, see inode. Both are guarded
+	// by fs.lock.
+	inodeRef *inodeRef
+	hasInode bool
+}
+
+type inodeRef struct {
+	inode uint64
+	refs  int
+	// key is what the ref is in inodes under. A node's File can change
+	// after it's taken a ref, see ReadDirAll, so Forget can't use it.
+	key string
+}
+
+// writable returns true if d is in an open commit. Write is set when d is
+// looked up and goes stale if the commit is finished, in which case it's only
+// corrected for commits that have been watched, see watchCommit.
+func (d *directory) writable() bool {
+	return d.Write && !d.fs.commitFinished(d.File.Commit)
 }
 
 func (d *directory) Attr(ctx context.Context, a *fuse.Attr) (retErr error) {
-	defer func() {
-		protolion.Debug(&DirectoryAttr{&d.Node, &Attr{uint32(a.Mode)}, errorToString(retErr)})
-	}()
+	if d.fs.debug() {
+		defer func() {
+			protolion.Debug(&DirectoryAttr{&d.Node, &Attr{uint32(a.Mode)}, errorToString(retErr)})
+		}()
+	}
 
 	a.Valid = time.Nanosecond
-	if d.Write {
-		a.Mode = os.ModeDir | 0775
-	} else {
-		a.Mode = os.ModeDir | 0555
-	}
-	a.Inode = d.fs.inode(d.File)
+	a.Mode = os.ModeDir | d.fs.dirMode(d.writable())
+	a.Inode = d.inode()
 	a.Mtime = prototime.TimestampToTime(d.Modified)
+	// commits can't be changed once they're finished, so the time one
+	// finished is also when its contents were created, see file.Attr
+	a.Ctime = a.Mtime
+	a.Crtime = a.Mtime
+	if d.fs.opts.DirSizes && d.File.Commit.ID != "" && !d.writable() {
+		size, err := d.totalSize(ctx)
+		if err != nil {
+			return err
+		}
+		a.Size = size
+	}
 	return nil
 }
 
+// totalSize returns the size of the files under d, it's only asked for when
+// d is stat'd so that ReadDirAll can stay a shallow listing.
+func (d *directory) totalSize(ctx context.Context) (uint64, error) {
+	if value, ok := d.fs.dirSizes.get(key(d.File)); ok {
+		return value.(uint64), nil
+	}
+	shards := d.Shards
+	if len(shards) == 0 {
+		shards = []*pfsclient.Shard{d.Shard}
+	}
+	var size uint64
+	for _, shard := range shards {
+		// children's sizes are only recursive when we ask for it
+		fileInfos, err := d.fs.listFile(
+			ctx,
+			d.File.Commit.Repo.Name,
+			d.File.Commit.ID,
+			d.File.Path,
+			d.fs.getFromCommitID(d.getRepoOrAliasName()),
+			shard,
+			true,
+		)
+		if err != nil {
+			return 0, err
+		}
+		for _, fileInfo := range fileInfos {
+			size += fileInfo.SizeBytes
+		}
+	}
+	d.fs.dirSizes.set(key(d.File), size)
+	return size, nil
+}
+
 func (d *directory) Lookup(ctx context.Context, name string) (result fs.Node, retErr error) {
-	defer func() {
-		protolion.Debug(&DirectoryLookup{&d.Node, name, getNode(result), errorToString(retErr)})
-	}()
+	defer func(start time.Time) { d.fs.opts.Metrics.observe(opLookup, start, 0, retErr) }(time.Now())
+	if d.fs.debug() {
+		defer func() {
+			protolion.Debug(&DirectoryLookup{&d.Node, name, getNode(result), errorToString(retErr)})
+		}()
+	}
+	// the kernel resolves these itself, but they're looked up when it
+	// reconnects a path, e.g. for NFS exports, and they'd otherwise be
+	// taken for a repo, commit or file
+	switch name {
+	case ".":
+		return d, nil
+	case "..":
+		if d.parent == nil {
+			// the root is its own parent
+			return d, nil
+		}
+		return d.parent, nil
+	}
 	if d.File.Commit.Repo.Name == "" {
 		return d.lookUpRepo(ctx, name)
 	}
@@ -100,21 +291,26 @@ func (d *directory) Lookup(ctx context.Context, name string) (result fs.Node, re
 }
 
 func (d *directory) ReadDirAll(ctx context.Context) (result []fuse.Dirent, retErr error) {
-	defer func() {
-		var dirents []*Dirent
-		for _, dirent := range result {
-			dirents = append(dirents, &Dirent{dirent.Inode, dirent.Name})
-		}
-		protolion.Debug(&DirectoryReadDirAll{&d.Node, dirents, errorToString(retErr)})
-	}()
+	defer func(start time.Time) { d.fs.opts.Metrics.observe(opReadDirAll, start, 0, retErr) }(time.Now())
+	if d.fs.debug() {
+		defer func() {
+			var dirents []*Dirent
+			for _, dirent := range result {
+				dirents = append(dirents, &Dirent{dirent.Inode, dirent.Name})
+			}
+			protolion.Debug(&DirectoryReadDirAll{&d.Node, dirents, errorToString(retErr)})
+		}()
+	}
 	if d.File.Commit.Repo.Name == "" {
 		return d.readRepos(ctx)
 	}
 	if d.File.Commit.ID == "" {
 		commitMount := d.fs.getCommitMount(d.getRepoOrAliasName())
-		if commitMount != nil && commitMount.Commit.ID != "" {
+		if commitMount != nil && commitMount.Commit.ID != "" && !d.fs.opts.CommitPaths {
 			d.File.Commit.ID = commitMount.Commit.ID
+			d.File.Path = commitMount.Path
 			d.Shard = commitMount.Shard
+			d.Shards = commitMount.Shards
 			return d.readFiles(ctx)
 		}
 		return d.readCommits(ctx)
@@ -122,119 +318,411 @@ func (d *directory) ReadDirAll(ctx context.Context) (result []fuse.Dirent, retEr
 	return d.readFiles(ctx)
 }
 
+// Access answers access(2) the way the operations that follow it will, reads
+// are allowed everywhere but writes only in open commits.
+func (d *directory) Access(ctx context.Context, request *fuse.AccessRequest) (retErr error) {
+	if d.fs.debug() {
+		defer func() {
+			protolion.Debug(&NodeAccess{&d.Node, request.Mask, errorToString(retErr)})
+		}()
+	}
+	if request.Mask&accessWrite != 0 && !d.writable() {
+		return fuse.Errno(syscall.EACCES)
+	}
+	return nil
+}
+
+func (d *directory) Open(ctx context.Context, request *fuse.OpenRequest, response *fuse.OpenResponse) (_ fs.Handle, retErr error) {
+	if d.fs.debug() {
+		defer func() {
+			protolion.Debug(&DirectoryOpen{&d.Node, errorToString(retErr)})
+		}()
+	}
+	if !request.Dir {
+		return nil, fuse.Errno(syscall.EISDIR)
+	}
+	return d, nil
+}
+
 func (d *directory) Create(ctx context.Context, request *fuse.CreateRequest, response *fuse.CreateResponse) (result fs.Node, _ fs.Handle, retErr error) {
-	defer func() {
-		protolion.Debug(&DirectoryCreate{&d.Node, getNode(result), errorToString(retErr)})
-	}()
+	if d.fs.debug() {
+		defer func() {
+			protolion.Debug(&DirectoryCreate{&d.Node, getNode(result), errorToString(retErr)})
+		}()
+	}
 	if d.File.Commit.ID == "" {
+		// files can only be created in a commit
 		return nil, 0, fuse.EPERM
 	}
+	if !d.writable() {
+		// finished commits, and open ones with ForceReadOnly, are read
+		// only, EROFS lets tools tell that apart from a permission problem
+		return nil, 0, fuse.Errno(syscall.EROFS)
+	}
 	directory := d.copy()
 	directory.File.Path = path.Join(directory.File.Path, request.Name)
+	if request.Flags&fuse.OpenExclusive != 0 {
+		exists, err := d.fs.fileExists(ctx, directory.File)
+		if err != nil {
+			return nil, 0, err
+		}
+		if exists {
+			return nil, 0, fuse.EEXIST
+		}
+	}
+	d.fs.misses.delete(aliasKey(directory.File, directory.RepoAlias))
 	localResult := &file{
 		directory: *directory,
 		size:      0,
 		local:     true,
 	}
-	response.Flags |= fuse.OpenDirectIO | fuse.OpenNonSeekable
-	handle := localResult.newHandle()
-	return localResult, handle, nil
+	// Files created here are always being written, so we leave them
+	// seekable to allow random writes, see handle.Write.
+	response.Flags |= fuse.OpenDirectIO
+	if request.Flags&fuse.OpenAppend != 0 {
+		return localResult, localResult.newAppendHandle(), nil
+	}
+	return localResult, localResult.newHandle(), nil
+}
+
+// Mknod creates an empty regular file, for programs that use mknod(2) rather
+// than open(2) with O_CREAT. Other node types have no equivalent in pfs.
+func (d *directory) Mknod(ctx context.Context, request *fuse.MknodRequest) (result fs.Node, retErr error) {
+	if d.fs.debug() {
+		defer func() {
+			protolion.Debug(&DirectoryMknod{&d.Node, getNode(result), errorToString(retErr)})
+		}()
+	}
+	if d.File.Commit.ID == "" || request.Mode&os.ModeType != 0 {
+		return nil, fuse.EPERM
+	}
+	if !d.writable() {
+		return nil, fuse.Errno(syscall.EROFS)
+	}
+	directory := d.copy()
+	directory.File.Path = path.Join(directory.File.Path, request.Name)
+	d.fs.misses.delete(aliasKey(directory.File, directory.RepoAlias))
+	// Unlike Create there's no handle to write through later, so the
+	// empty file is put now to make it visible to lookups.
+	w, err := d.fs.putFileWriter(directory.File)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return &file{
+		directory: *directory,
+		size:      0,
+		local:     true,
+	}, nil
 }
 
 func (d *directory) Mkdir(ctx context.Context, request *fuse.MkdirRequest) (result fs.Node, retErr error) {
-	defer func() {
-		protolion.Debug(&DirectoryMkdir{&d.Node, getNode(result), errorToString(retErr)})
-	}()
+	if d.fs.debug() {
+		defer func() {
+			protolion.Debug(&DirectoryMkdir{&d.Node, getNode(result), errorToString(retErr)})
+		}()
+	}
 	if d.File.Commit.ID == "" {
 		return nil, fuse.EPERM
 	}
-	if err := d.fs.apiClient.MakeDirectory(d.File.Commit.Repo.Name, d.File.Commit.ID, path.Join(d.File.Path, request.Name)); err != nil {
-		return nil, err
+	if !d.writable() {
+		return nil, fuse.Errno(syscall.EROFS)
 	}
 	localResult := d.copy()
 	localResult.File.Path = path.Join(localResult.File.Path, request.Name)
+	if d.fs.opts.DryRun {
+		d.fs.preview.mkdir(localResult.File)
+		return localResult, nil
+	}
+	if err := d.fs.apiClient.MakeDirectory(d.File.Commit.Repo.Name, d.File.Commit.ID, path.Join(d.File.Path, request.Name)); err != nil {
+		return nil, err
+	}
 	return localResult, nil
 }
 
+// Link always fails with EPERM. Files in pfs belong to a single path, and
+// EPERM is what tools like cp and tar expect before they fall back to
+// copying.
+func (d *directory) Link(ctx context.Context, request *fuse.LinkRequest, old fs.Node) (fs.Node, error) {
+	return nil, fuse.EPERM
+}
+
+// There's no Fallocate. The vendored fuse library doesn't decode
+// FUSE_FALLOCATE and answers it with ENOSYS, which the kernel turns into
+// EOPNOTSUPP for that call and every later one. posix_fallocate(3) then falls
+// back to writing the range itself, fallocate(2) callers get EOPNOTSUPP.
+
 func (d *directory) Remove(ctx context.Context, req *fuse.RemoveRequest) (retErr error) {
-	defer func() {
-		protolion.Debug(&FileRemove{&d.Node, errorToString(retErr)})
-	}()
-	return d.fs.apiClient.DeleteFile(d.Node.File.Commit.Repo.Name, d.Node.File.Commit.ID, filepath.Join(d.Node.File.Path, req.Name))
+	if d.fs.debug() {
+		defer func() {
+			protolion.Debug(&FileRemove{&d.Node, errorToString(retErr)})
+		}()
+	}
+	if d.File.Commit.ID == "" {
+		return fuse.EPERM
+	}
+	if !d.writable() {
+		return fuse.Errno(syscall.EROFS)
+	}
+	file := d.copy().File
+	file.Path = path.Join(file.Path, req.Name)
+	return d.fs.deleteFile(file)
 }
 
 type file struct {
 	directory
-	size    int64
-	local   bool
-	handles []*handle
+	// size is the file's size when it was looked up, grown by writes
+	// through its handles, which hold handlesLock to grow it.
+	size int64
+	// finished is set once size is from a FileInfo of the file in a
+	// finished commit, reads are only clamped to it then, see Read. It's
+	// guarded by handlesLock too.
+	finished bool
+	local    bool
+	// handles are the file's open handles, guarded by handlesLock.
+	handles     []*handle
+	handlesLock sync.Mutex
+	// mode is computed on the first Attr in a finished commit, since
+	// those files can't change. It's guarded by fs.lock.
+	mode os.FileMode
 }
 
 func (f *file) Attr(ctx context.Context, a *fuse.Attr) (retErr error) {
-	defer func() {
-		protolion.Debug(&FileAttr{&f.Node, &Attr{uint32(a.Mode)}, errorToString(retErr)})
-	}()
-	if f.directory.Write {
-		// If the file is from an open commit, we just pretend that it's
-		// an empty file.
-		a.Size = 0
+	if f.fs.debug() {
+		defer func() {
+			protolion.Debug(&FileAttr{&f.Node, &Attr{uint32(a.Mode)}, errorToString(retErr)})
+		}()
+	}
+	if f.writable() {
+		// If the file is from an open commit we can't inspect it, so we
+		// report what's been written through the handles we have open and
+		// the current time, tools like make need a sensible mtime.
+		a.Size = f.writtenSize()
+		if f.fs.opts.DryRun {
+			a.Size = uint64(f.fs.preview.size(f.File))
+		}
+		a.Mtime = time.Now()
+		a.Ctime = a.Mtime
+		a.Mode = f.fs.fileMode(true)
 	} else {
-		fileInfo, err := f.fs.apiClient.InspectFile(
-			f.File.Commit.Repo.Name,
-			f.File.Commit.ID,
-			f.File.Path,
+		fileInfo, err := f.fs.cachedInspectFile(
+			ctx,
+			f.File,
+			f.RepoAlias,
 			f.fs.getFromCommitID(f.getRepoOrAliasName()),
-			f.Shard,
+			f.shard(f.File.Path),
 		)
 		if err != nil && !f.local {
 			return err
 		}
 		if fileInfo != nil {
+			f.setFinishedSize(fileInfo)
 			a.Size = fileInfo.SizeBytes
 			a.Mtime = prototime.TimestampToTime(fileInfo.Modified)
+			// Modified is when the commit that last wrote the file
+			// finished, that version of the file was created then and
+			// hasn't changed since. Only OS X passes Crtime on as the
+			// birth time, on Linux stat doesn't have one.
+			a.Ctime = a.Mtime
+			a.Crtime = a.Mtime
 		}
+		mode, err := f.readOnlyMode(ctx, a.Size)
+		if err != nil {
+			return err
+		}
+		a.Mode = mode
 	}
-	a.Mode = 0666
-	a.Inode = f.fs.inode(f.File)
+	a.Inode = f.inode()
 	return nil
 }
 
+// readOnlyMode returns the mode of a file in a finished commit. pfs doesn't
+// store mode bits so we guess: files that start with "#!" are executable.
+// The guess costs a GetFile, so it's kept on f and in fs.modes.
+func (f *file) readOnlyMode(ctx context.Context, size uint64) (os.FileMode, error) {
+	f.fs.lock.RLock()
+	mode := f.mode
+	f.fs.lock.RUnlock()
+	if mode != 0 {
+		return mode, nil
+	}
+	modeKey := aliasKey(f.File, f.RepoAlias)
+	if value, ok := f.fs.modes.get(modeKey); ok {
+		mode = value.(os.FileMode)
+	} else {
+		var err error
+		mode, err = f.sniffMode(ctx, size)
+		if err != nil {
+			return 0, err
+		}
+		f.fs.modes.set(modeKey, mode)
+	}
+	f.fs.lock.Lock()
+	f.mode = mode
+	f.fs.lock.Unlock()
+	return mode, nil
+}
+
+func (f *file) sniffMode(ctx context.Context, size uint64) (os.FileMode, error) {
+	mode := f.fs.fileMode(false)
+	if size < uint64(len(shebang)) {
+		return mode, nil
+	}
+	var buffer bytes.Buffer
+	if err := f.fs.getFile(
+		ctx,
+		f.File.Commit.Repo.Name,
+		f.File.Commit.ID,
+		f.File.Path,
+		0,
+		int64(len(shebang)),
+		f.fs.getFromCommitID(f.getRepoOrAliasName()),
+		f.shard(f.File.Path),
+		&buffer,
+	); err != nil {
+		return 0, err
+	}
+	if buffer.String() == shebang {
+		// executable by whoever can read it
+		return mode | (mode&0444)>>2, nil
+	}
+	return mode, nil
+}
+
 func (f *file) Open(ctx context.Context, request *fuse.OpenRequest, response *fuse.OpenResponse) (_ fs.Handle, retErr error) {
-	defer func() {
-		protolion.Debug(&FileOpen{&f.Node, errorToString(retErr)})
-	}()
-	response.Flags |= fuse.OpenDirectIO | fuse.OpenNonSeekable
+	if f.fs.debug() {
+		defer func() {
+			protolion.Debug(&FileOpen{&f.Node, errorToString(retErr)})
+		}()
+	}
+	if request.Dir {
+		return nil, fuse.Errno(syscall.ENOTDIR)
+	}
+	if !f.writable() && !request.Flags.IsReadOnly() {
+		// the kernel doesn't check the mode bits for us, so with
+		// ForceReadOnly the file would otherwise be written through to its
+		// open commit
+		return nil, fuse.Errno(syscall.EROFS)
+	}
+	if f.writable() {
+		response.Flags |= fuse.OpenDirectIO
+		if request.Flags&fuse.OpenAppend != 0 {
+			return f.newAppendHandle(), nil
+		}
+	} else if f.fs.decompresses(f.File.Path) {
+		// the decompressed size isn't known, direct IO keeps the kernel
+		// from cutting reads off at the compressed size
+		response.Flags |= fuse.OpenDirectIO | fuse.OpenNonSeekable
+		h := f.newHandle()
+		h.decompressor = f.newDecompressor()
+		return h, nil
+	} else {
+		if _, ok := f.finishedSize(); !ok {
+			// f was looked up while its commit was open, so its size
+			// isn't the one to clamp reads to
+			fileInfo, err := f.fs.cachedInspectFile(
+				ctx,
+				f.File,
+				f.RepoAlias,
+				f.fs.getFromCommitID(f.getRepoOrAliasName()),
+				f.shard(f.File.Path),
+			)
+			if err != nil {
+				return nil, err
+			}
+			f.setFinishedSize(fileInfo)
+		}
+		// Files in finished commits don't change, so reads can go through
+		// the page cache, and keep it between opens.
+		response.Flags |= fuse.OpenKeepCache | fuse.OpenNonSeekable
+	}
 	return f.newHandle(), nil
 }
 
+// finishedSize returns f's size if it's known from its finished commit.
+func (f *file) finishedSize() (int64, bool) {
+	f.handlesLock.Lock()
+	defer f.handlesLock.Unlock()
+	return f.size, f.finished
+}
+
+// setFinishedSize records fileInfo, from f's finished commit, as f's size.
+func (f *file) setFinishedSize(fileInfo *pfsclient.FileInfo) {
+	f.handlesLock.Lock()
+	defer f.handlesLock.Unlock()
+	f.size = int64(fileInfo.SizeBytes)
+	f.finished = true
+}
+
 func (f *file) Fsync(ctx context.Context, req *fuse.FsyncRequest) error {
-	for _, h := range f.handles {
-		if h.w != nil {
-			w := h.w
-			h.w = nil
-			if err := w.Close(); err != nil {
-				return err
-			}
+	f.handlesLock.Lock()
+	handles := append([]*handle(nil), f.handles...)
+	f.handlesLock.Unlock()
+	for _, h := range handles {
+		if err := h.checkpoint(); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
-func (f *filesystem) inode(file *pfsclient.File) uint64 {
-	f.lock.RLock()
-	inode, ok := f.inodes[key(file)]
-	f.lock.RUnlock()
-	if ok {
-		return inode
+// inode returns d's inode number. The first call takes a reference on the
+// number for d.File, which Forget gives back, so every node the kernel still
+// holds for a path sees the same number.
+func (d *directory) inode() uint64 {
+	f := d.fs
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if d.hasInode {
+		return d.inodeRef.inode
+	}
+	ref, ok := f.inodes[key(d.File)]
+	if !ok {
+		ref = &inodeRef{inode: f.nextInode, key: key(d.File)}
+		f.nextInode++
+		f.inodes[ref.key] = ref
 	}
+	ref.refs++
+	d.inodeRef = ref
+	d.hasInode = true
+	return ref.inode
+}
+
+// knownInode returns file's inode number if the kernel holds a node for it.
+// Unlike inode it doesn't take a reference, so it can't hand out a number.
+func (f *filesystem) knownInode(file *pfsclient.File) uint64 {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if ref, ok := f.inodes[key(file)]; ok {
+		return ref.inode
+	}
+	return 0
+}
+
+// Forget drops d's reference on its inode number, the number is freed once
+// no node for the path holds one.
+func (d *directory) Forget() {
+	f := d.fs
 	f.lock.Lock()
 	defer f.lock.Unlock()
-	if inode, ok := f.inodes[key(file)]; ok {
-		return inode
+	if !d.hasInode {
+		return
+	}
+	d.hasInode = false
+	d.inodeRef.refs--
+	if d.inodeRef.refs == 0 {
+		delete(f.inodes, d.inodeRef.key)
 	}
-	newInode := uint64(len(f.inodes))
-	f.inodes[key(file)] = newInode
-	return newInode
+}
+
+// writtenSize returns the end of the furthest write through any of f's
+// handles, including ones that have been closed since.
+func (f *file) writtenSize() uint64 {
+	f.handlesLock.Lock()
+	defer f.handlesLock.Unlock()
+	return uint64(f.size)
 }
 
 func (f *file) newHandle() *handle {
@@ -242,97 +730,569 @@ func (f *file) newHandle() *handle {
 		f: f,
 	}
 
+	f.handlesLock.Lock()
 	f.handles = append(f.handles, h)
+	f.handlesLock.Unlock()
+	f.fs.handlesLock.Lock()
+	defer f.fs.handlesLock.Unlock()
+	f.fs.handles[h] = true
 
 	return h
 }
 
+// newAppendHandle is newHandle for files opened with O_APPEND, the handle
+// writes through the file's appendWriter rather than a writer of its own.
+func (f *file) newAppendHandle() *handle {
+	appender := f.fs.appendWriter(f.File)
+	h := f.newHandle()
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.appender = appender
+	return h
+}
+
+// openWriters returns the files with handles that have written something
+// that hasn't been flushed yet.
+func (f *filesystem) openWriters() []*Node {
+	f.handlesLock.Lock()
+	defer f.handlesLock.Unlock()
+	var result []*Node
+	for h := range f.handles {
+		h.lock.Lock()
+		open := h.w != nil || h.spill != nil || (h.appender != nil && h.appender.open())
+		h.lock.Unlock()
+		if open {
+			result = append(result, &h.f.Node)
+		}
+	}
+	return result
+}
+
+func (f *file) removeHandle(h *handle) {
+	f.handlesLock.Lock()
+	defer f.handlesLock.Unlock()
+	for i, other := range f.handles {
+		if other == h {
+			f.handles = append(f.handles[:i], f.handles[i+1:]...)
+			return
+		}
+	}
+}
+
 type handle struct {
 	f       *file
 	w       io.WriteCloser
 	written int
+	// spill is a local temp file holding writes from spillOffset onwards,
+	// it's only used once we've seen a write we can't stream, see Write.
+	spill       *os.File
+	spillOffset int
+	// replace is set once spill holds the whole file, so putSpill has to
+	// delete what's already been put first, see rebaseSpill.
+	replace bool
+	// sent is the data of the last write streamed to pfs, from sentOffset,
+	// it's what a repeated write is checked against, see repeated.
+	sent       []byte
+	sentOffset int
+	// appender is set if the file was opened with O_APPEND, writes then
+	// ignore their offset and go through it, see appendWriter.
+	appender *appendWriter
+	// decompressor is set if reads return the file decompressed, see
+	// Options.DecompressExtensions. It's guarded by lock.
+	decompressor *decompressor
+	// err is the first error from writing to pfs, once it's set every
+	// Write, Flush and Release returns it since bytes have been lost.
+	err  error
+	lock sync.Mutex
 }
 
 func (h *handle) Read(ctx context.Context, request *fuse.ReadRequest, response *fuse.ReadResponse) (retErr error) {
-	defer func() {
-		protolion.Debug(&FileRead{&h.f.Node, errorToString(retErr)})
-	}()
+	defer func(start time.Time) {
+		h.f.fs.opts.Metrics.observe(opRead, start, len(response.Data), retErr)
+	}(time.Now())
+	if h.f.fs.debug() {
+		defer func() {
+			protolion.Debug(&FileRead{&h.f.Node, errorToString(retErr)})
+		}()
+	}
+	shard := h.f.shard(h.f.File.Path)
+	if !pfsserver.FileInShard(shard, h.f.File) {
+		// GetFile filters by shard, reading a file outside of it would
+		// look like an empty file rather than one that isn't mounted.
+		return fuse.Errno(syscall.ENXIO)
+	}
+	if h.decompressor != nil {
+		h.lock.Lock()
+		defer h.lock.Unlock()
+		data, err := h.decompressor.read(ctx, request.Offset, request.Size)
+		if err != nil {
+			return err
+		}
+		response.Data = data
+		return nil
+	}
+	size := int64(request.Size)
+	if h.f.writable() && h.f.fs.opts.DryRun {
+		response.Data = h.f.fs.preview.read(h.f.File, request.Offset, size)
+		return nil
+	}
+	if fileSize, ok := h.f.finishedSize(); ok && !h.f.writable() {
+		// Files in finished commits don't change, so reads are clamped to
+		// the size we looked up, past EOF is a short or empty read like
+		// read(2) rather than whatever GetFile makes of the range.
+		if request.Offset >= fileSize {
+			response.Data = nil
+			return nil
+		}
+		if request.Offset+size > fileSize {
+			size = fileSize - request.Offset
+		}
+	}
 	var buffer bytes.Buffer
-	if err := h.f.fs.apiClient.GetFile(
-		h.f.File.Commit.Repo.Name,
-		h.f.File.Commit.ID,
-		h.f.File.Path,
-		request.Offset,
-		int64(request.Size),
-		h.f.fs.getFromCommitID(h.f.getRepoOrAliasName()),
-		h.f.Shard,
-		&buffer,
-	); err != nil {
-		if grpc.Code(err) == codes.NotFound {
-			// This happens when trying to read from a file in an open
-			// commit. We could catch this at `open(2)` time and never
-			// get here, but Open is currently not a remote operation.
-			//
-			// ENOENT from read(2) is weird, let's call this EINVAL
-			// instead.
-			return fuse.Errno(syscall.EINVAL)
+	chunkSize := int64(h.f.fs.maxChunkSize())
+	for read := int64(0); read < size; read += chunkSize {
+		if chunkSize > size-read {
+			chunkSize = size - read
+		}
+		before := buffer.Len()
+		if err := h.f.fs.getFile(
+			ctx,
+			h.f.File.Commit.Repo.Name,
+			h.f.File.Commit.ID,
+			h.f.File.Path,
+			request.Offset+read,
+			chunkSize,
+			h.f.fs.getFromCommitID(h.f.getRepoOrAliasName()),
+			shard,
+			&buffer,
+		); err != nil {
+			if grpc.Code(err) == codes.NotFound {
+				// This happens when trying to read from a file in an open
+				// commit. We could catch this at `open(2)` time and never
+				// get here, but Open is currently not a remote operation.
+				//
+				// ENOENT from read(2) is weird, let's call this EINVAL
+				// instead.
+				return fuse.Errno(syscall.EINVAL)
+			}
+			return err
+		}
+		if int64(buffer.Len()-before) < chunkSize {
+			// short read, we're at the end of the file
+			break
 		}
-		return err
 	}
 	response.Data = buffer.Bytes()
 	return nil
 }
 
 func (h *handle) Write(ctx context.Context, request *fuse.WriteRequest, response *fuse.WriteResponse) (retErr error) {
-	defer func() {
-		protolion.Debug(&FileWrite{&h.f.Node, errorToString(retErr)})
-	}()
-	if h.w == nil {
-		w, err := h.f.fs.apiClient.PutFileWriter(
-			h.f.File.Commit.Repo.Name, h.f.File.Commit.ID, h.f.File.Path, h.f.fs.handleID)
+	defer func(start time.Time) {
+		h.f.fs.opts.Metrics.observe(opWrite, start, response.Size, retErr)
+	}(time.Now())
+	if h.f.fs.debug() {
+		defer func() {
+			protolion.Debug(&FileWrite{&h.f.Node, errorToString(retErr)})
+		}()
+	}
+	if !h.f.writable() {
+		// the commit finished after the handle was opened
+		return fuse.Errno(syscall.EROFS)
+	}
+	if h.f.isFinishFile() {
+		return h.finish(request, response)
+	}
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.err != nil {
+		return h.err
+	}
+	if err := h.write(request, response); err != nil {
+		return h.fail(err)
+	}
+	return nil
+}
+
+func (h *handle) write(request *fuse.WriteRequest, response *fuse.WriteResponse) error {
+	if h.appender != nil {
+		return h.writeAppend(request, response)
+	}
+	repeated, ok := h.repeated(request, h.written)
+	if h.spill == nil && (int(request.Offset) > h.written || !ok) {
+		// PutFileWriter is append only so we can't stream a write that
+		// leaves a gap or changes bytes we've already sent. From here on
+		// writes go to a local temp file which is put in its entirety
+		// when the handle is flushed. Gaps in the temp file read back as
+		// zeros, so they're put as zeros too.
+		spill, err := ioutil.TempFile("", "pfs-fuse-")
 		if err != nil {
 			return err
 		}
-		h.w = w
+		h.spill = spill
+		h.spillOffset = h.written
+	}
+	if h.spill != nil {
+		return h.writeSpill(request, response)
 	}
-	// repeated is how many bytes in this write have already been sent in
-	// previous call to Write. Why does the OS send us the same data twice in
-	// different calls? Good question, this is a behavior that's only been
-	// observed on osx, not on linux.
-	repeated := h.written - int(request.Offset)
-	if repeated < 0 {
-		return fmt.Errorf("gap in bytes written, (OpenNonSeekable should make this impossible)")
+	w, err := h.writer()
+	if err != nil {
+		return err
 	}
-	written, err := h.w.Write(request.Data[repeated:])
+	written, err := writeChunks(w, request.Data[repeated:], h.f.fs.maxChunkSize())
+	h.written += written
+	h.sent = append(h.sent[:0], request.Data[:repeated+written]...)
+	h.sentOffset = int(request.Offset)
 	if err != nil {
 		return err
 	}
 	response.Size = written + repeated
+	h.grow(request.Offset + int64(written+repeated))
+	return nil
+}
+
+// repeated returns how many bytes at the start of request come before end
+// and so have already been sent in a previous call to Write. Why does the OS
+// send us the same data twice in different calls? Good question, this is a
+// behavior that's only been observed on osx, not on linux. Those bytes are
+// only skipped if they're the ones the last streamed write sent, otherwise
+// the write changes data pfs already has and ok is false.
+func (h *handle) repeated(request *fuse.WriteRequest, end int) (_ int, ok bool) {
+	repeated := end - int(request.Offset)
+	if repeated <= 0 {
+		return 0, true
+	}
+	if repeated > len(request.Data) {
+		repeated = len(request.Data)
+	}
+	start := int(request.Offset) - h.sentOffset
+	if start < 0 || start+repeated > len(h.sent) ||
+		!bytes.Equal(h.sent[start:start+repeated], request.Data[:repeated]) {
+		return 0, false
+	}
+	return repeated, true
+}
+
+// writeChunks writes data to w at most chunkSize bytes at a time, each Write
+// to a PutFileWriter is sent as a single message.
+func writeChunks(w io.Writer, data []byte, chunkSize int) (int, error) {
+	var written int
+	for written < len(data) {
+		chunk := data[written:]
+		if len(chunk) > chunkSize {
+			chunk = chunk[:chunkSize]
+		}
+		n, err := w.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// writeSpill writes request to the temp file, bytes before spillOffset have
+// already been sent so they're treated as repeated just like in Write. A
+// write that changes them makes the temp file hold the whole file, see
+// rebaseSpill.
+func (h *handle) writeSpill(request *fuse.WriteRequest, response *fuse.WriteResponse) error {
+	repeated, ok := h.repeated(request, h.spillOffset)
+	if !ok {
+		if err := h.rebaseSpill(); err != nil {
+			return err
+		}
+		repeated = 0
+	}
+	offset := request.Offset + int64(repeated)
+	written, err := h.spill.WriteAt(request.Data[repeated:], offset-int64(h.spillOffset))
+	if err != nil {
+		return err
+	}
+	response.Size = written + repeated
+	h.grow(offset + int64(written))
+	return nil
+}
+
+// rebaseSpill reads back what's been put of the file in front of the temp
+// file, so that it holds the whole file from offset 0 and writes anywhere in
+// it can be made. pfs only appends, so once it's put the file is deleted and
+// put again in its entirety, see putSpill.
+func (h *handle) rebaseSpill() (retErr error) {
+	if h.w != nil {
+		// what's been streamed has to be in pfs to be read back
+		w := h.w
+		h.w = nil
+		if err := w.Close(); err != nil {
+			return err
+		}
+	}
+	spill, err := ioutil.TempFile("", "pfs-fuse-")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if retErr != nil {
+			spill.Close()
+			os.Remove(spill.Name())
+		}
+	}()
+	if err := h.f.fs.readBack(h.f.File, spill); err != nil {
+		return err
+	}
+	if _, err := h.spill.Seek(0, 0); err != nil {
+		return err
+	}
+	if _, err := spill.Seek(int64(h.spillOffset), 0); err != nil {
+		return err
+	}
+	if _, err := io.Copy(spill, h.spill); err != nil {
+		return err
+	}
+	h.spill.Close()
+	os.Remove(h.spill.Name())
+	h.spill = spill
+	h.spillOffset = 0
+	h.replace = true
+	return nil
+}
+
+// writeAppend appends request's data to the file. The offset is the kernel's
+// idea of where the file ends, which lags behind other handles' appends, so
+// it's ignored.
+func (h *handle) writeAppend(request *fuse.WriteRequest, response *fuse.WriteResponse) error {
+	end, written, err := h.appender.append(h.f.fs, request.Data)
 	h.written += written
-	if h.f.size < request.Offset+int64(written) {
-		h.f.size = request.Offset + int64(written)
+	if err != nil {
+		return err
 	}
+	response.Size = written
+	h.grow(end)
 	return nil
 }
 
+// grow records that h has written up to end, it's what Attr reports while
+// the commit is open. h.lock must be held.
+func (h *handle) grow(end int64) {
+	h.f.handlesLock.Lock()
+	defer h.f.handlesLock.Unlock()
+	if h.f.size < end {
+		h.f.size = end
+	}
+}
+
 func (h *handle) Flush(ctx context.Context, req *fuse.FlushRequest) error {
+	return h.closeWriter()
+}
+
+func (h *handle) Release(ctx context.Context, req *fuse.ReleaseRequest) error {
+	h.f.removeHandle(h)
+	h.f.fs.handlesLock.Lock()
+	delete(h.f.fs.handles, h)
+	h.f.fs.handlesLock.Unlock()
+	// Flush normally gets here first, this catches anything still buffered.
+	err := h.closeWriter()
+	if h.appender != nil {
+		h.f.fs.releaseAppendWriter(h.appender)
+	}
+	return err
+}
+
+// isFinishFile returns true if f is the control file, which is at the root
+// of its commit's mount, under CommitMount.Path if one was given.
+func (f *file) isFinishFile() bool {
+	var root string
+	if commitMount := f.fs.getCommitMount(f.getRepoOrAliasName()); commitMount != nil {
+		root = commitMount.Path
+	}
+	return path.Clean(f.File.Path) == path.Join(root, finishFileName)
+}
+
+// finish handles a write to the control file. The data is discarded, instead
+// every other open handle in the commit is flushed, and if the mount was
+// configured with FinishCommit the commit is then finished. This happens
+// before the write returns so the writer sees any error.
+func (h *handle) finish(request *fuse.WriteRequest, response *fuse.WriteResponse) error {
+	commit := h.f.File.Commit
+	var handles []*handle
+	h.f.fs.handlesLock.Lock()
+	for other := range h.f.fs.handles {
+		if other != h && other.f.File.Commit.Repo.Name == commit.Repo.Name && other.f.File.Commit.ID == commit.ID {
+			handles = append(handles, other)
+		}
+	}
+	h.f.fs.handlesLock.Unlock()
+	for _, other := range handles {
+		if err := other.closeWriter(); err != nil {
+			return err
+		}
+	}
+	if h.f.fs.opts.FinishCommit && !h.f.fs.opts.DryRun {
+		if err := h.f.fs.apiClient.FinishCommit(commit.Repo.Name, commit.ID); err != nil {
+			return err
+		}
+	}
+	response.Size = len(request.Data)
+	return nil
+}
+
+func (h *handle) writer() (io.WriteCloser, error) {
+	if h.w == nil {
+		w, err := h.f.fs.putFileWriter(h.f.File)
+		if err != nil {
+			return nil, err
+		}
+		h.w = w
+	}
+	return h.w, nil
+}
+
+// closeWriter puts any spilled writes and closes the writer, the next Write
+// will open a new one.
+func (h *handle) closeWriter() error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.closeWriterLocked()
+}
+
+// checkpoint is closeWriter for Fsync, the handle keeps writing after the
+// bytes it's already written so it gets a new writer straight away. pfs
+// appends each writer's bytes to the file, so nothing already put is lost.
+func (h *handle) checkpoint() error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	reopen := h.w != nil || h.spill != nil
+	if err := h.closeWriterLocked(); err != nil {
+		return err
+	}
+	if reopen {
+		if _, err := h.writer(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fail records err as the handle's error if it's the first, and returns it.
+func (h *handle) fail(err error) error {
+	if h.err == nil {
+		h.err = err
+	}
+	return err
+}
+
+func (h *handle) closeWriterLocked() error {
+	if h.err != nil {
+		h.abort()
+		return h.err
+	}
+	if err := h.closeWriterUnchecked(); err != nil {
+		return h.fail(err)
+	}
+	return nil
+}
+
+// abort drops a failed handle's writer and temp file, their errors are
+// ignored since the handle has already failed.
+func (h *handle) abort() {
+	if h.spill != nil {
+		h.spill.Close()
+		os.Remove(h.spill.Name())
+		h.spill = nil
+		h.replace = false
+	}
+	if h.w != nil {
+		h.w.Close()
+		h.w = nil
+	}
+}
+
+func (h *handle) closeWriterUnchecked() error {
+	if h.spill != nil {
+		if err := h.putSpill(); err != nil {
+			return err
+		}
+	}
+	if h.appender != nil {
+		// this puts the other handles' appends too, they're already in
+		// order
+		closed, err := h.appender.close()
+		if err != nil {
+			return err
+		}
+		if closed && h.f.fs.opts.VerifyWrites && !h.f.fs.opts.DryRun {
+			return h.verify()
+		}
+	}
 	if h.w != nil {
 		w := h.w
 		h.w = nil
 		if err := w.Close(); err != nil {
 			return err
 		}
+		if h.f.fs.opts.VerifyWrites && !h.f.fs.opts.DryRun {
+			return h.verify()
+		}
 	}
 	return nil
 }
 
-func (h *handle) Release(ctx context.Context, req *fuse.ReleaseRequest) error {
+// verify checks that pfs has at least as many bytes for the file as this
+// handle has written. It can't check for equality because other handles,
+// and earlier commits, may have added to the same file.
+func (h *handle) verify() error {
+	fileInfo, err := h.f.fs.apiClient.InspectFileUnsafe(
+		h.f.File.Commit.Repo.Name, h.f.File.Commit.ID, h.f.File.Path, "", nil)
+	if err != nil {
+		return err
+	}
+	if fileInfo.SizeBytes < uint64(h.written) {
+		return fmt.Errorf("%s has %d bytes in pfs but %d were written", h.f.File.Path, fileInfo.SizeBytes, h.written)
+	}
 	return nil
 }
 
+// putSpill sends the temp file to pfs after whatever has been streamed so
+// far, or in place of the file if it holds all of it. Unwritten regions of
+// the temp file read back as zeros.
+func (h *handle) putSpill() (retErr error) {
+	spill := h.spill
+	h.spill = nil
+	defer func() {
+		if err := spill.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+		if err := os.Remove(spill.Name()); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+	if h.replace {
+		if err := h.f.fs.deleteFile(h.f.File); err != nil {
+			return err
+		}
+		h.replace = false
+	}
+	w, err := h.writer()
+	if err != nil {
+		return err
+	}
+	if _, err := spill.Seek(0, 0); err != nil {
+		return err
+	}
+	written, err := io.Copy(w, spill)
+	if err != nil {
+		return err
+	}
+	h.written = h.spillOffset + int(written)
+	// the spill was sent in one go, there's no last write to repeat
+	h.sent = h.sent[:0]
+	return nil
+}
+
+// copy returns a copy of d that's made into one of its children, so d is its
+// parent.
 func (d *directory) copy() *directory {
 	return &directory{
-		fs: d.fs,
+		fs:     d.fs,
+		parent: d,
 		Node: Node{
 			File: &pfsclient.File{
 				Commit: &pfsclient.Commit{
@@ -343,13 +1303,31 @@ func (d *directory) copy() *directory {
 				},
 				Path: d.File.Path,
 			},
-			Write:     d.Write,
+			Write:     d.writable(),
 			Shard:     d.Shard,
+			Shards:    d.Shards,
 			RepoAlias: d.RepoAlias,
 		},
 	}
 }
 
+// shard returns the shard that filePath should be read from. When the
+// directory merges several shards that's the one filePath hashes to.
+func (d *directory) shard(filePath string) *pfsclient.Shard {
+	if len(d.Shards) == 0 {
+		return d.Shard
+	}
+	file := &pfsclient.File{Path: filePath}
+	for _, shard := range d.Shards {
+		if pfsserver.FileInShard(shard, file) {
+			return shard
+		}
+	}
+	// filePath isn't in any of our shards, asking any one of them will
+	// correctly report that it doesn't exist.
+	return d.Shards[0]
+}
+
 func (d *directory) getRepoOrAliasName() string {
 	if d.RepoAlias != "" {
 		return d.RepoAlias
@@ -362,6 +1340,7 @@ func (f *filesystem) getCommitMount(nameOrAlias string) *CommitMount {
 		return &CommitMount{
 			Commit: client.NewCommit(nameOrAlias, ""),
 			Shard:  f.Shard,
+			Shards: f.opts.Shards,
 		}
 	}
 
@@ -384,9 +1363,12 @@ func (f *filesystem) getCommitMount(nameOrAlias string) *CommitMount {
 
 func (f *filesystem) getFromCommitID(nameOrAlias string) string {
 	commitMount := f.getCommitMount(nameOrAlias)
-	if commitMount == nil || commitMount.FromCommit == nil {
+	if commitMount == nil {
 		return ""
 	}
+	if commitMount.FromCommit == nil {
+		return f.opts.FromCommits[nameOrAlias]
+	}
 	return commitMount.FromCommit.ID
 }
 
@@ -395,7 +1377,7 @@ func (d *directory) lookUpRepo(ctx context.Context, name string) (fs.Node, error
 	if commitMount == nil {
 		return nil, fuse.EPERM
 	}
-	repoInfo, err := d.fs.apiClient.InspectRepo(commitMount.Commit.Repo.Name)
+	repoInfo, err := d.fs.inspectRepo(ctx, commitMount.Commit.Repo.Name)
 	if err != nil {
 		return nil, err
 	}
@@ -404,21 +1386,45 @@ func (d *directory) lookUpRepo(ctx context.Context, name string) (fs.Node, error
 	}
 	result := d.copy()
 	result.File.Commit.Repo.Name = commitMount.Commit.Repo.Name
-	result.File.Commit.ID = commitMount.Commit.ID
+	// With CommitPaths the commit is always the next part of the path, see
+	// lookUpCommit.
+	if !d.fs.opts.CommitPaths {
+		result.File.Commit.ID = commitMount.Commit.ID
+		if result.File.Commit.ID == "" && d.fs.opts.LatestCommit {
+			commitID, err := d.fs.latestFinishedCommit(ctx, commitMount.Commit.Repo.Name)
+			if err != nil {
+				return nil, err
+			}
+			result.File.Commit.ID = commitID
+		}
+		if result.File.Commit.ID == "" && d.fs.opts.SingleCommit {
+			commitID, err := d.fs.onlyCommit(ctx, commitMount.Commit.Repo.Name)
+			if err != nil {
+				return nil, err
+			}
+			result.File.Commit.ID = commitID
+		}
+	}
+	if result.File.Commit.ID != "" {
+		result.File.Path = commitMount.Path
+	}
 	result.RepoAlias = commitMount.Alias
 	result.Shard = commitMount.Shard
+	result.Shards = commitMount.Shards
 
-	commitInfo, err := d.fs.apiClient.InspectCommit(
+	commitInfo, err := d.fs.inspectCommit(
+		ctx,
 		commitMount.Commit.Repo.Name,
-		commitMount.Commit.ID,
+		result.File.Commit.ID,
 	)
 	if err != nil {
 		return nil, err
 	}
-	if commitInfo.CommitType == pfsclient.CommitType_COMMIT_TYPE_READ {
+	if commitInfo.CommitType == pfsclient.CommitType_COMMIT_TYPE_READ || d.fs.opts.ForceReadOnly {
 		result.Write = false
 	} else {
 		result.Write = true
+		d.fs.watchCommit(d, name, result)
 	}
 	result.Modified = commitInfo.Finished
 
@@ -426,9 +1432,18 @@ func (d *directory) lookUpRepo(ctx context.Context, name string) (fs.Node, error
 }
 
 func (d *directory) lookUpCommit(ctx context.Context, name string) (fs.Node, error) {
-	commitInfo, err := d.fs.apiClient.InspectCommit(
+	commitID := name
+	if d.fs.opts.CommitNames && strings.Contains(name, commitNameSeparator) {
+		var err error
+		commitID, err = d.resolveCommitName(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	commitInfo, err := d.fs.inspectCommit(
+		ctx,
 		d.File.Commit.Repo.Name,
-		name,
+		commitID,
 	)
 	if err != nil {
 		return nil, err
@@ -437,21 +1452,82 @@ func (d *directory) lookUpCommit(ctx context.Context, name string) (fs.Node, err
 		return nil, fuse.ENOENT
 	}
 	result := d.copy()
-	result.File.Commit.ID = name
-	if commitInfo.CommitType == pfsclient.CommitType_COMMIT_TYPE_READ {
+	result.File.Commit.ID = commitID
+	if commitMount := d.fs.getCommitMount(d.getRepoOrAliasName()); commitMount != nil {
+		result.File.Path = commitMount.Path
+	}
+	if commitInfo.CommitType == pfsclient.CommitType_COMMIT_TYPE_READ || d.fs.opts.ForceReadOnly {
 		result.Write = false
 	} else {
 		result.Write = true
+		d.fs.watchCommit(d, name, result)
 	}
 	result.Modified = commitInfo.Finished
 	return result, nil
 }
 
+// latestFinishedCommit returns the ID of the most recently finished commit in
+// repo, or "" if none have finished.
+func (f *filesystem) latestFinishedCommit(ctx context.Context, repo string) (string, error) {
+	commitInfos, err := f.listCommit(ctx, repo, client.CommitTypeRead)
+	if err != nil {
+		return "", err
+	}
+	var latest *pfsclient.CommitInfo
+	for _, commitInfo := range commitInfos {
+		if latest == nil || prototime.TimestampLess(latest.Finished, commitInfo.Finished) {
+			latest = commitInfo
+		}
+	}
+	if latest == nil {
+		return "", nil
+	}
+	return latest.Commit.ID, nil
+}
+
+// onlyCommit returns the ID of repo's commit if it has exactly one and it's
+// finished, or "" otherwise.
+func (f *filesystem) onlyCommit(ctx context.Context, repo string) (string, error) {
+	commitInfos, err := f.listCommit(ctx, repo, client.CommitTypeNone)
+	if err != nil {
+		return "", err
+	}
+	if len(commitInfos) != 1 || commitInfos[0].CommitType != pfsclient.CommitType_COMMIT_TYPE_READ {
+		return "", nil
+	}
+	return commitInfos[0].Commit.ID, nil
+}
+
+// resolveCommitName returns the ID of the commit in d's repo that's listed as
+// name, see commitName.
+func (d *directory) resolveCommitName(ctx context.Context, name string) (string, error) {
+	commitInfos, err := d.fs.listCommit(ctx, d.File.Commit.Repo.Name, client.CommitTypeNone)
+	if err != nil {
+		return "", err
+	}
+	for _, commitInfo := range commitInfos {
+		if commitName(commitInfo) == name {
+			return commitInfo.Commit.ID, nil
+		}
+	}
+	return "", fuse.ENOENT
+}
+
+// commitName is the name commitInfo is listed as when Options.CommitNames is
+// set, its branch and start time. Commits without a branch keep their ID.
+func commitName(commitInfo *pfsclient.CommitInfo) string {
+	if commitInfo.Branch == "" || commitInfo.Started == nil {
+		return commitInfo.Commit.ID
+	}
+	started := prototime.TimestampToTime(commitInfo.Started).UTC()
+	return commitInfo.Branch + commitNameSeparator + started.Format(commitNameTimeFormat)
+}
+
 func (d *directory) lookUpFile(ctx context.Context, name string) (fs.Node, error) {
 	var fileInfo *pfsclient.FileInfo
 	var err error
 
-	if d.Node.Write {
+	if d.writable() {
 		// Basically, if the directory is writable, we are looking up files
 		// from an open commit.  In this case, we want to return an empty file,
 		// because sometimes you want to remove a file but a remove operation
@@ -466,27 +1542,46 @@ func (d *directory) lookUpFile(ctx context.Context, name string) (fs.Node, error
 			SizeBytes: 0,
 		}
 	} else {
-		fileInfo, err = d.fs.apiClient.InspectFile(
-			d.File.Commit.Repo.Name,
-			d.File.Commit.ID,
-			path.Join(d.File.Path, name),
+		missKey := aliasKey(&pfsclient.File{
+			Commit: d.File.Commit,
+			Path:   path.Join(d.File.Path, name),
+		}, d.RepoAlias)
+		if _, ok := d.fs.misses.get(missKey); ok {
+			return nil, fuse.ENOENT
+		}
+		fileInfo, err = d.fs.cachedInspectFile(
+			ctx,
+			&pfsclient.File{
+				Commit: d.File.Commit,
+				Path:   path.Join(d.File.Path, name),
+			},
+			d.RepoAlias,
 			d.fs.getFromCommitID(d.getRepoOrAliasName()),
-			d.Shard,
+			d.shard(path.Join(d.File.Path, name)),
 		)
-		if err != nil {
+		if grpc.Code(err) == codes.NotFound {
+			d.fs.misses.set(missKey, struct{}{})
 			return nil, fuse.ENOENT
 		}
+		if err != nil {
+			// EIO or something else that says nothing about whether the
+			// file is there, so it isn't cached
+			return nil, err
+		}
 	}
 
 	// We want to inherit the metadata other than the path, which should be the
 	// path currently being looked up
 	directory := d.copy()
 	directory.File.Path = fileInfo.File.Path
+	// when the commit that last wrote it finished, for user.pfs.modified
+	directory.Modified = fileInfo.Modified
 	switch fileInfo.FileType {
 	case pfsclient.FileType_FILE_TYPE_REGULAR:
 		return &file{
 			directory: *directory,
 			size:      int64(fileInfo.SizeBytes),
+			finished:  !d.writable(),
 			local:     false,
 		}, nil
 	case pfsclient.FileType_FILE_TYPE_DIR:
@@ -499,11 +1594,14 @@ func (d *directory) lookUpFile(ctx context.Context, name string) (fs.Node, error
 func (d *directory) readRepos(ctx context.Context) ([]fuse.Dirent, error) {
 	var result []fuse.Dirent
 	if len(d.fs.CommitMounts) == 0 {
-		repoInfos, err := d.fs.apiClient.ListRepo(nil)
+		repoInfos, err := d.fs.listRepo(ctx)
 		if err != nil {
 			return nil, err
 		}
 		for _, repoInfo := range repoInfos {
+			if ctx.Err() != nil {
+				return nil, errInterrupted
+			}
 			result = append(result, fuse.Dirent{Name: repoInfo.Repo.Name, Type: fuse.DT_Dir})
 		}
 	} else {
@@ -519,50 +1617,221 @@ func (d *directory) readRepos(ctx context.Context) ([]fuse.Dirent, error) {
 }
 
 func (d *directory) readCommits(ctx context.Context) ([]fuse.Dirent, error) {
-	commitInfos, err := d.fs.apiClient.ListCommit([]string{d.File.Commit.Repo.Name},
-		nil, client.CommitTypeNone, false, false, nil)
+	commitInfos, err := d.fs.listCommit(ctx, d.File.Commit.Repo.Name, client.CommitTypeNone)
 	if err != nil {
 		return nil, err
 	}
-	var result []fuse.Dirent
+	// a repo without commits is an empty directory, not an error
+	result := []fuse.Dirent{}
 	for _, commitInfo := range commitInfos {
-		result = append(result, fuse.Dirent{Name: commitInfo.Commit.ID, Type: fuse.DT_Dir})
+		if ctx.Err() != nil {
+			return nil, errInterrupted
+		}
+		name := commitInfo.Commit.ID
+		if d.fs.opts.CommitNames {
+			name = commitName(commitInfo)
+		}
+		result = append(result, fuse.Dirent{Name: name, Type: fuse.DT_Dir})
 	}
 	return result, nil
 }
 
 func (d *directory) readFiles(ctx context.Context) ([]fuse.Dirent, error) {
-	fileInfos, err := d.fs.apiClient.ListFile(
-		d.File.Commit.Repo.Name,
-		d.File.Commit.ID,
-		d.File.Path,
-		d.fs.getFromCommitID(d.getRepoOrAliasName()),
-		d.Shard,
-		// setting recurse to false for performance reasons
-		// it does however means that we won't know the correct sizes of directories
-		false,
-	)
+	shards := d.Shards
+	if len(shards) == 0 {
+		shards = []*pfsclient.Shard{d.Shard}
+	}
+	fileInfos, err := d.listFiles(ctx, shards)
 	if err != nil {
 		return nil, err
 	}
 	var result []fuse.Dirent
+	// READDIRPLUS would let dirents carry attributes too, but our fuse
+	// library doesn't speak it, so the best we can do is the inode number
+	// and cachedInspectFile for the Attrs that follow.
+	// directories can show up in more than one shard
+	seen := make(map[string]bool)
 	for _, fileInfo := range fileInfos {
+		if ctx.Err() != nil {
+			return nil, errInterrupted
+		}
+		if seen[fileInfo.File.Path] {
+			continue
+		}
+		seen[fileInfo.File.Path] = true
 		shortPath := strings.TrimPrefix(fileInfo.File.Path, d.File.Path)
 		if shortPath[0] == '/' {
 			shortPath = shortPath[1:]
 		}
 		switch fileInfo.FileType {
 		case pfsclient.FileType_FILE_TYPE_REGULAR:
-			result = append(result, fuse.Dirent{Name: shortPath, Type: fuse.DT_File})
+			if !d.writable() {
+				// Keyed the way lookUpFile builds the path.
+				d.fs.fileInfos.set(aliasKey(&pfsclient.File{
+					Commit: d.File.Commit,
+					Path:   path.Join(d.File.Path, shortPath),
+				}, d.RepoAlias), fileInfo)
+			}
+			result = append(result, fuse.Dirent{Name: shortPath, Type: fuse.DT_File, Inode: d.childInode(shortPath)})
 		case pfsclient.FileType_FILE_TYPE_DIR:
-			result = append(result, fuse.Dirent{Name: shortPath, Type: fuse.DT_Dir})
+			result = append(result, fuse.Dirent{Name: shortPath, Type: fuse.DT_Dir, Inode: d.childInode(shortPath)})
 		default:
 			continue
 		}
 	}
+	if d.writable() && d.fs.opts.DryRun {
+		names := make(map[string]bool)
+		for _, dirent := range result {
+			names[dirent.Name] = true
+		}
+		for _, dirent := range d.fs.preview.dirents(d.File) {
+			if !names[dirent.Name] {
+				result = append(result, dirent)
+			}
+		}
+	}
 	return result, nil
 }
 
+// listFiles lists the files in d from each of shards, in a read only commit
+// the listing is cached for listingTTL.
+func (d *directory) listFiles(ctx context.Context, shards []*pfsclient.Shard) ([]*pfsclient.FileInfo, error) {
+	writable := d.writable()
+	listingKey := d.listingKey()
+	if !writable {
+		if value, ok := d.fs.listings.get(listingKey); ok {
+			return value.([]*pfsclient.FileInfo), nil
+		}
+	}
+	var fileInfos []*pfsclient.FileInfo
+	for _, shard := range shards {
+		shardFileInfos, err := d.fs.listFile(
+			ctx,
+			d.File.Commit.Repo.Name,
+			d.File.Commit.ID,
+			d.File.Path,
+			d.fs.getFromCommitID(d.getRepoOrAliasName()),
+			shard,
+			// setting recurse to false for performance reasons
+			// it does however means that we won't know the correct sizes of directories
+			false,
+		)
+		if err != nil {
+			return nil, err
+		}
+		fileInfos = append(fileInfos, shardFileInfos...)
+	}
+	if !writable {
+		d.fs.listings.set(listingKey, fileInfos)
+	}
+	return fileInfos, nil
+}
+
+// listingKey is the key of d's listing in the listings cache.
+func (d *directory) listingKey() string {
+	return aliasKey(d.File, d.RepoAlias)
+}
+
+// fileExists returns true if file is in its commit, which may be open. Only
+// NotFound means it isn't, other errors are EIO, we can't tell either way.
+func (f *filesystem) fileExists(ctx context.Context, file *pfsclient.File) (bool, error) {
+	if f.opts.DryRun {
+		return f.preview.exists(file), nil
+	}
+	_, err := f.inspectFileUnsafe(ctx, file)
+	switch {
+	case err == nil:
+		return true, nil
+	case grpc.Code(err) == codes.NotFound:
+		return false, nil
+	default:
+		return false, fuse.EIO
+	}
+}
+
+// deleteFile deletes file from its open commit, in a dry run from f.preview.
+func (f *filesystem) deleteFile(file *pfsclient.File) error {
+	if f.opts.DryRun {
+		f.preview.remove(file)
+		return nil
+	}
+	return f.apiClient.DeleteFile(file.Commit.Repo.Name, file.Commit.ID, file.Path)
+}
+
+// readBack writes what's been put of file in its open commit to w, in a dry
+// run what's been written to f.preview.
+func (f *filesystem) readBack(file *pfsclient.File, w io.Writer) error {
+	if f.opts.DryRun {
+		_, err := w.Write(f.preview.read(file, 0, math.MaxInt64))
+		return err
+	}
+	return f.apiClient.GetFileUnsafe(file.Commit.Repo.Name, file.Commit.ID, file.Path, 0, 0, "", nil, w)
+}
+
+// putFileWriter returns a writer for file, in a dry run it writes to
+// f.preview instead of pfs.
+func (f *filesystem) putFileWriter(file *pfsclient.File) (io.WriteCloser, error) {
+	if f.opts.DryRun {
+		return f.preview.writer(file), nil
+	}
+	return f.apiClient.PutFileWriter(file.Commit.Repo.Name, file.Commit.ID, file.Path, f.handleID)
+}
+
+func (d *directory) childInode(name string) uint64 {
+	return d.fs.knownInode(&pfsclient.File{
+		Commit: d.File.Commit,
+		Path:   path.Join(d.File.Path, name),
+	})
+}
+
+// fileMode is the permission bits of files in writable commits if write is
+// set, otherwise of read only ones, which lose their write bits.
+func (f *filesystem) fileMode(write bool) os.FileMode {
+	mode := os.FileMode(0666)
+	if f.opts.FileMode != 0 {
+		mode = f.opts.FileMode.Perm()
+	}
+	if !write {
+		mode &^= 0222
+	}
+	return mode
+}
+
+// dirMode is fileMode for directories.
+func (f *filesystem) dirMode(write bool) os.FileMode {
+	mode := os.FileMode(0775)
+	if f.opts.DirMode != 0 {
+		mode = f.opts.DirMode.Perm()
+	}
+	if !write {
+		mode &^= 0222
+	}
+	return mode
+}
+
+// debug reports whether per-operation debug logs should be built and
+// emitted.
+func (f *filesystem) debug() bool {
+	return f.opts.LogLevel <= lion.LevelDebug
+}
+
+func (f *filesystem) maxChunkSize() int {
+	if f.opts.MaxChunkSize > 0 {
+		return f.opts.MaxChunkSize
+	}
+	return defaultMaxChunkSize
+}
+
+// cachedInspectFile returns the FileInfo for file, looked up through alias,
+// from a recent readFiles if there is one, otherwise it inspects it. Only
+// read only commits are cached.
+func (f *filesystem) cachedInspectFile(ctx context.Context, file *pfsclient.File, alias string, fromCommitID string, shard *pfsclient.Shard) (*pfsclient.FileInfo, error) {
+	if value, ok := f.fileInfos.get(aliasKey(file, alias)); ok {
+		return value.(*pfsclient.FileInfo), nil
+	}
+	return f.inspectFile(ctx, file.Commit.Repo.Name, file.Commit.ID, file.Path, fromCommitID, shard)
+}
+
 // TODO this code is duplicate elsewhere, we should put it somehwere.
 func errorToString(err error) string {
 	if err == nil {
@@ -582,6 +1851,33 @@ func getNode(node fs.Node) *Node {
 	}
 }
 
+// invalidateCache drops the cached lookups, FileInfos, modes and listings for
+// commit in repo, for every commit in repo if commit is empty, or for
+// everything if repo is empty too. It's for seeing changes pfs made behind
+// the mount's back, such as a repo being deleted and created again.
+func (f *filesystem) invalidateCache(repo string, commit string) {
+	var prefix string
+	if repo != "" {
+		prefix = repo + "/"
+		if commit != "" {
+			prefix += commit + "/"
+		}
+	}
+	f.misses.deletePrefix(prefix)
+	f.fileInfos.deletePrefix(prefix)
+	f.dirSizes.deletePrefix(prefix)
+	f.modes.deletePrefix(prefix)
+	f.listings.deletePrefix(prefix)
+}
+
 func key(file *pfsclient.File) string {
 	return fmt.Sprintf("%s/%s/%s", file.Commit.Repo.Name, file.Commit.ID, file.Path)
 }
+
+// aliasKey is key(file) plus the alias file was looked up through, for
+// caching what pfs returns for it: aliases of the same repo can be mounted
+// with different shards and from commits and see different files. It still
+// starts with key(file) so invalidateCache drops it.
+func aliasKey(file *pfsclient.File, alias string) string {
+	return key(file) + "@" + alias
+}
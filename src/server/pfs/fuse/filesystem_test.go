@@ -2,7 +2,9 @@ package fuse_test
 
 import (
 	"bytes"
+	"compress/gzip"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"os"
@@ -10,7 +12,9 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"syscall"
 	"testing"
+	"time"
 
 	"bazil.org/fuse/fs/fstestutil"
 	"github.com/pachyderm/pachyderm/src/client"
@@ -180,8 +184,8 @@ func TestCommitFinishedReadDir(t *testing.T) {
 
 		require.NoError(t, fstestutil.CheckDir(filepath.Join(mountpoint, repoName, commit.ID), map[string]fstestutil.FileInfoCheck{
 			greetingName: func(fi os.FileInfo) error {
-				// TODO respect greetingPerm
-				if g, e := fi.Mode(), os.FileMode(0666); g != e {
+				// pfs doesn't store greetingPerm, finished files are read only
+				if g, e := fi.Mode(), os.FileMode(0444); g != e {
 					return fmt.Errorf("wrong mode: %v != %v", g, e)
 				}
 				if g, e := fi.Size(), int64(len(greeting)); g != e {
@@ -194,8 +198,8 @@ func TestCommitFinishedReadDir(t *testing.T) {
 				return nil
 			},
 			scriptName: func(fi os.FileInfo) error {
-				// TODO respect scriptPerm
-				if g, e := fi.Mode(), os.FileMode(0666); g != e {
+				// pfs doesn't store scriptPerm, but scripts are executable
+				if g, e := fi.Mode(), os.FileMode(0555); g != e {
 					return fmt.Errorf("wrong mode: %v != %v", g, e)
 				}
 				if g, e := fi.Size(), int64(len(script)); g != e {
@@ -309,6 +313,113 @@ func TestSpacedWrites(t *testing.T) {
 	})
 }
 
+func TestRandomWrites(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+
+	testFuse(t, func(c client.APIClient, mountpoint string) {
+		repo := "test"
+		require.NoError(t, c.CreateRepo(repo))
+		commit, err := c.StartCommit(repo, "", "")
+		require.NoError(t, err)
+		path := filepath.Join(mountpoint, repo, commit.ID, "file")
+		file, err := os.Create(path)
+		require.NoError(t, err)
+		_, err = file.Write([]byte("foo"))
+		require.NoError(t, err)
+		_, err = file.WriteAt([]byte("baz"), 6)
+		require.NoError(t, err)
+		_, err = file.WriteAt([]byte("bar"), 3)
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+		require.NoError(t, c.FinishCommit(repo, commit.ID))
+		data, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, "foobarbaz", string(data))
+	})
+}
+
+func TestRewriteHeader(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+
+	testFuse(t, func(c client.APIClient, mountpoint string) {
+		repo := "test"
+		require.NoError(t, c.CreateRepo(repo))
+		commit, err := c.StartCommit(repo, "", "")
+		require.NoError(t, err)
+		path := filepath.Join(mountpoint, repo, commit.ID, "file")
+		file, err := os.Create(path)
+		require.NoError(t, err)
+		_, err = file.Write([]byte("size=?\n"))
+		require.NoError(t, err)
+		_, err = file.Write([]byte("body\n"))
+		require.NoError(t, err)
+		_, err = file.WriteAt([]byte("size=5\n"), 0)
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+		require.NoError(t, c.FinishCommit(repo, commit.ID))
+		data, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, "size=5\nbody\n", string(data))
+	})
+}
+
+func TestSparseWrites(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+
+	testFuse(t, func(c client.APIClient, mountpoint string) {
+		repo := "test"
+		require.NoError(t, c.CreateRepo(repo))
+		commit, err := c.StartCommit(repo, "", "")
+		require.NoError(t, err)
+		path := filepath.Join(mountpoint, repo, commit.ID, "file")
+		file, err := os.Create(path)
+		require.NoError(t, err)
+		_, err = file.WriteAt([]byte("foo"), 0)
+		require.NoError(t, err)
+		const gapEnd = 1024 * 1024
+		_, err = file.WriteAt([]byte("bar"), gapEnd)
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+		require.NoError(t, c.FinishCommit(repo, commit.ID))
+		data, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, gapEnd+3, len(data))
+		require.Equal(t, "foo", string(data[:3]))
+		require.Equal(t, make([]byte, gapEnd-3), data[3:gapEnd])
+		require.Equal(t, "bar", string(data[gapEnd:]))
+	})
+}
+
+func TestOpenCommitAttr(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+
+	testFuse(t, func(c client.APIClient, mountpoint string) {
+		repo := "test"
+		require.NoError(t, c.CreateRepo(repo))
+		commit, err := c.StartCommit(repo, "", "")
+		require.NoError(t, err)
+		before := time.Now().Add(-time.Second)
+		file, err := os.Create(filepath.Join(mountpoint, repo, commit.ID, "file"))
+		require.NoError(t, err)
+		_, err = file.Write([]byte("foo"))
+		require.NoError(t, err)
+		fileInfo, err := file.Stat()
+		require.NoError(t, err)
+		require.Equal(t, int64(3), fileInfo.Size())
+		require.True(t, fileInfo.ModTime().After(before))
+		require.NoError(t, file.Close())
+		require.NoError(t, c.FinishCommit(repo, commit.ID))
+	})
+}
+
 func TestMountCachingViaWalk(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipped because of short mode")
@@ -342,51 +453,459 @@ func TestMountCachingViaWalk(t *testing.T) {
 		filesSeen = make([]interface{}, 0)
 		err = filepath.Walk(mountpoint, walkCallback)
 		require.NoError(t, err)
-		require.OneOfEquals(t, "/foo", filesSeen)
-		require.OneOfEquals(t, "/bar", filesSeen)
-
+		require.OneOfEquals(t, "/foo", filesSeen)
+		require.OneOfEquals(t, "/bar", filesSeen)
+
+	})
+}
+
+func TestMountCachingViaShell(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+
+	testFuse(t, func(c client.APIClient, mountpoint string) {
+		repo1 := "foo"
+		require.NoError(t, c.CreateRepo(repo1))
+
+		// Now if we walk the FS we should see the file.
+		// This first act of 'walking' should also initiate the cache
+
+		ls := exec.Command("ls")
+		ls.Dir = mountpoint
+		out, err := ls.Output()
+		require.NoError(t, err)
+
+		require.Equal(t, "foo\n", string(out))
+
+		// Now create another repo, and look for it under the mount point
+		repo2 := "bar"
+		require.NoError(t, c.CreateRepo(repo2))
+
+		// Now if we walk the FS we should see the new file.
+		// This second ls on mac doesn't report the file!
+
+		ls = exec.Command("ls")
+		ls.Dir = mountpoint
+		out, err = ls.Output()
+		require.NoError(t, err)
+
+		require.Equal(t, true, "foo\nbar\n" == string(out) || "bar\nfoo\n" == string(out))
+
+	})
+}
+
+func TestOpenDirectory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+
+	testFuse(t, func(c client.APIClient, mountpoint string) {
+		repoName := "foo"
+		require.NoError(t, c.CreateRepo(repoName))
+		commit, err := c.StartCommit(repoName, "", "")
+		require.NoError(t, err)
+		_, err = c.PutFile(repoName, commit.ID, "dir/file", strings.NewReader("foo"))
+		require.NoError(t, err)
+		require.NoError(t, c.FinishCommit(repoName, commit.ID))
+		dirPath := filepath.Join(mountpoint, repoName, commit.ID, "dir")
+
+		for _, flag := range []int{os.O_RDONLY, os.O_RDONLY | syscall.O_DIRECTORY} {
+			dir, err := os.OpenFile(dirPath, flag, 0)
+			require.NoError(t, err)
+			names, err := dir.Readdirnames(0)
+			require.NoError(t, err)
+			require.Equal(t, []string{"file"}, names)
+			require.NoError(t, dir.Close())
+		}
+
+		_, err = os.OpenFile(filepath.Join(dirPath, "file"), os.O_RDONLY|syscall.O_DIRECTORY, 0)
+		require.YesError(t, err)
+		require.Equal(t, syscall.ENOTDIR, err.(*os.PathError).Err)
+		_, err = os.OpenFile(dirPath, os.O_WRONLY, 0)
+		require.YesError(t, err)
+		require.Equal(t, syscall.EISDIR, err.(*os.PathError).Err)
+	})
+}
+
+func TestLink(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+
+	testFuse(t, func(c client.APIClient, mountpoint string) {
+		repoName := "foo"
+		require.NoError(t, c.CreateRepo(repoName))
+		commit, err := c.StartCommit(repoName, "", "")
+		require.NoError(t, err)
+		filePath := filepath.Join(mountpoint, repoName, commit.ID, "file")
+		require.NoError(t, ioutil.WriteFile(filePath, []byte("foo"), 0644))
+		err = os.Link(filePath, filepath.Join(mountpoint, repoName, commit.ID, "link"))
+		require.YesError(t, err)
+		require.Equal(t, syscall.EPERM, err.(*os.LinkError).Err)
+	})
+}
+
+func TestFsyncThenWrite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+
+	testFuse(t, func(c client.APIClient, mountpoint string) {
+		repoName := "foo"
+		require.NoError(t, c.CreateRepo(repoName))
+		commit, err := c.StartCommit(repoName, "", "")
+		require.NoError(t, err)
+		f, err := os.Create(filepath.Join(mountpoint, repoName, commit.ID, "file"))
+		require.NoError(t, err)
+		_, err = f.WriteString("foo")
+		require.NoError(t, err)
+		require.NoError(t, f.Sync())
+		_, err = f.WriteString("bar")
+		require.NoError(t, err)
+		require.NoError(t, f.Sync())
+		_, err = f.WriteString("buzz")
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		require.NoError(t, c.FinishCommit(repoName, commit.ID))
+
+		var buffer bytes.Buffer
+		require.NoError(t, c.GetFile(repoName, commit.ID, "file", 0, 0, "", nil, &buffer))
+		require.Equal(t, "foobarbuzz", buffer.String())
+	})
+}
+
+func TestCreateExclusive(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+
+	testFuse(t, func(c client.APIClient, mountpoint string) {
+		repoName := "foo"
+		require.NoError(t, c.CreateRepo(repoName))
+		commit, err := c.StartCommit(repoName, "", "")
+		require.NoError(t, err)
+		_, err = c.PutFile(repoName, commit.ID, "existing", strings.NewReader("foo"))
+		require.NoError(t, err)
+
+		_, err = os.OpenFile(filepath.Join(mountpoint, repoName, commit.ID, "existing"), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		require.YesError(t, err)
+		require.Equal(t, syscall.EEXIST, err.(*os.PathError).Err)
+		f, err := os.OpenFile(filepath.Join(mountpoint, repoName, commit.ID, "new"), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		require.NoError(t, c.FinishCommit(repoName, commit.ID))
+	})
+}
+
+func TestMknod(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+
+	testFuse(t, func(c client.APIClient, mountpoint string) {
+		repoName := "foo"
+		require.NoError(t, c.CreateRepo(repoName))
+		commit, err := c.StartCommit(repoName, "", "")
+		require.NoError(t, err)
+		filePath := filepath.Join(mountpoint, repoName, commit.ID, "file")
+		require.NoError(t, syscall.Mknod(filePath, syscall.S_IFREG|0644, 0))
+		require.Equal(t, syscall.EPERM, syscall.Mknod(filepath.Join(mountpoint, repoName, commit.ID, "fifo"), syscall.S_IFIFO|0644, 0))
+		require.NoError(t, c.FinishCommit(repoName, commit.ID))
+
+		var buffer bytes.Buffer
+		require.NoError(t, c.GetFile(repoName, commit.ID, "file", 0, 0, "", nil, &buffer))
+		require.Equal(t, "", buffer.String())
+	})
+}
+
+func TestCommitMountPath(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+
+	commitMounts := []*fuse.CommitMount{
+		{
+			Commit: client.NewCommit("foo", ""),
+			Path:   "dir",
+		},
+	}
+	testFuseWithCommitMounts(t, commitMounts, nil, func(c client.APIClient, mountpoint string) {
+		repoName := "foo"
+		require.NoError(t, c.CreateRepo(repoName))
+		commit, err := c.StartCommit(repoName, "", "")
+		require.NoError(t, err)
+		_, err = c.PutFile(repoName, commit.ID, "dir/file", strings.NewReader("foo"))
+		require.NoError(t, err)
+		_, err = c.PutFile(repoName, commit.ID, "other", strings.NewReader("bar"))
+		require.NoError(t, err)
+		require.NoError(t, c.FinishCommit(repoName, commit.ID))
+
+		require.NoError(t, fstestutil.CheckDir(filepath.Join(mountpoint, repoName, commit.ID), map[string]fstestutil.FileInfoCheck{
+			"file": nil,
+		}))
+		data, err := ioutil.ReadFile(filepath.Join(mountpoint, repoName, commit.ID, "file"))
+		require.NoError(t, err)
+		require.Equal(t, "foo", string(data))
+	})
+}
+
+func TestDryRun(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+
+	testFuseWithOptions(t, &fuse.Options{DryRun: true}, func(c client.APIClient, mountpoint string) {
+		repoName := "foo"
+		require.NoError(t, c.CreateRepo(repoName))
+		commit, err := c.StartCommit(repoName, "", "")
+		require.NoError(t, err)
+		dir := filepath.Join(mountpoint, repoName, commit.ID, "dir")
+		require.NoError(t, os.Mkdir(dir, 0755))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "file"), []byte("foo"), 0644))
+
+		data, err := ioutil.ReadFile(filepath.Join(dir, "file"))
+		require.NoError(t, err)
+		require.Equal(t, "foo", string(data))
+		names, err := ioutil.ReadDir(dir)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(names))
+		require.Equal(t, "file", names[0].Name())
+
+		require.NoError(t, c.FinishCommit(repoName, commit.ID))
+		fileInfos, err := c.ListFile(repoName, commit.ID, "", "", nil, false)
+		require.NoError(t, err)
+		require.Equal(t, 0, len(fileInfos))
+	})
+}
+
+func TestCommitNames(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+
+	testFuseWithOptions(t, &fuse.Options{CommitNames: true}, func(c client.APIClient, mountpoint string) {
+		repoName := "foo"
+		require.NoError(t, c.CreateRepo(repoName))
+		commit, err := c.StartCommit(repoName, "", "master")
+		require.NoError(t, err)
+		_, err = c.PutFile(repoName, commit.ID, "file", strings.NewReader("foo"))
+		require.NoError(t, err)
+		require.NoError(t, c.FinishCommit(repoName, commit.ID))
+
+		names, err := ioutil.ReadDir(filepath.Join(mountpoint, repoName))
+		require.NoError(t, err)
+		require.Equal(t, 1, len(names))
+		require.True(t, strings.HasPrefix(names[0].Name(), "master@"))
+		for _, name := range []string{names[0].Name(), commit.ID} {
+			data, err := ioutil.ReadFile(filepath.Join(mountpoint, repoName, name, "file"))
+			require.NoError(t, err)
+			require.Equal(t, "foo", string(data))
+		}
+	})
+}
+
+func TestReadPastEOF(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+
+	testFuse(t, func(c client.APIClient, mountpoint string) {
+		repoName := "foo"
+		require.NoError(t, c.CreateRepo(repoName))
+		commit, err := c.StartCommit(repoName, "", "")
+		require.NoError(t, err)
+		_, err = c.PutFile(repoName, commit.ID, "file", strings.NewReader("foo"))
+		require.NoError(t, err)
+		require.NoError(t, c.FinishCommit(repoName, commit.ID))
+
+		f, err := os.Open(filepath.Join(mountpoint, repoName, commit.ID, "file"))
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, f.Close())
+		}()
+		buffer := make([]byte, 10)
+		n, err := f.ReadAt(buffer, 1)
+		require.Equal(t, io.EOF, err)
+		require.Equal(t, "oo", string(buffer[:n]))
+		n, err = f.ReadAt(buffer, 3)
+		require.Equal(t, io.EOF, err)
+		require.Equal(t, 0, n)
+	})
+}
+
+func TestMultipleShards(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+
+	opts := &fuse.Options{
+		Shards: []*pfsclient.Shard{
+			{FileNumber: 0, FileModulus: 2, BlockModulus: 1},
+			{FileNumber: 1, FileModulus: 2, BlockModulus: 1},
+		},
+	}
+	testFuseWithOptions(t, opts, func(c client.APIClient, mountpoint string) {
+		repoName := "foo"
+		require.NoError(t, c.CreateRepo(repoName))
+		commit, err := c.StartCommit(repoName, "", "")
+		require.NoError(t, err)
+		var names []string
+		for i := 0; i < 10; i++ {
+			name := fmt.Sprintf("file%d", i)
+			names = append(names, name)
+			require.NoError(t, ioutil.WriteFile(filepath.Join(mountpoint, repoName, commit.ID, name), []byte(name), 0644))
+		}
+		require.NoError(t, c.FinishCommit(repoName, commit.ID))
+
+		dir := filepath.Join(mountpoint, repoName, commit.ID)
+		checks := make(map[string]fstestutil.FileInfoCheck)
+		for _, name := range names {
+			checks[name] = nil
+		}
+		require.NoError(t, fstestutil.CheckDir(dir, checks))
+		for _, name := range names {
+			data, err := ioutil.ReadFile(filepath.Join(dir, name))
+			require.NoError(t, err)
+			require.Equal(t, name, string(data))
+		}
+	})
+}
+
+func TestFinishFile(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+
+	testFuseWithOptions(t, &fuse.Options{FinishCommit: true}, func(c client.APIClient, mountpoint string) {
+		repoName := "foo"
+		require.NoError(t, c.CreateRepo(repoName))
+		commit, err := c.StartCommit(repoName, "", "")
+		require.NoError(t, err)
+		// leave a handle open, .finish should flush it
+		file, err := os.Create(filepath.Join(mountpoint, repoName, commit.ID, "greeting"))
+		require.NoError(t, err)
+		defer func() {
+			_ = file.Close()
+		}()
+		_, err = file.Write([]byte("hello"))
+		require.NoError(t, err)
+		require.NoError(t, ioutil.WriteFile(filepath.Join(mountpoint, repoName, commit.ID, ".finish"), []byte("\n"), 0644))
+
+		commitInfo, err := c.InspectCommit(repoName, commit.ID)
+		require.NoError(t, err)
+		require.Equal(t, pfsclient.CommitType_COMMIT_TYPE_READ, commitInfo.CommitType)
+		var buffer bytes.Buffer
+		require.NoError(t, c.GetFile(repoName, commit.ID, "greeting", 0, 0, "", nil, &buffer))
+		require.Equal(t, "hello", buffer.String())
+	})
+}
+
+func TestFromCommits(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+
+	opts := &fuse.Options{FromCommits: make(map[string]string)}
+	testFuseWithOptions(t, opts, func(c client.APIClient, mountpoint string) {
+		repoName := "foo"
+		require.NoError(t, c.CreateRepo(repoName))
+		commit1, err := c.StartCommit(repoName, "", "")
+		require.NoError(t, err)
+		_, err = c.PutFile(repoName, commit1.ID, "old", strings.NewReader("old"))
+		require.NoError(t, err)
+		require.NoError(t, c.FinishCommit(repoName, commit1.ID))
+		commit2, err := c.StartCommit(repoName, commit1.ID, "")
+		require.NoError(t, err)
+		_, err = c.PutFile(repoName, commit2.ID, "new", strings.NewReader("new"))
+		require.NoError(t, err)
+		require.NoError(t, c.FinishCommit(repoName, commit2.ID))
+		// the mount shares the map, so we can fill it in now that we
+		// know the commit
+		opts.FromCommits[repoName] = commit1.ID
+
+		require.NoError(t, fstestutil.CheckDir(filepath.Join(mountpoint, repoName, commit2.ID), map[string]fstestutil.FileInfoCheck{
+			"new": nil,
+		}))
+	})
+}
+
+func TestVerifyWrites(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+
+	testFuseWithOptions(t, &fuse.Options{VerifyWrites: true}, func(c client.APIClient, mountpoint string) {
+		repoName := "foo"
+		require.NoError(t, c.CreateRepo(repoName))
+		commit, err := c.StartCommit(repoName, "", "")
+		require.NoError(t, err)
+		filePath := filepath.Join(mountpoint, repoName, commit.ID, "greeting")
+		require.NoError(t, ioutil.WriteFile(filePath, []byte("hello"), 0644))
+		require.NoError(t, c.FinishCommit(repoName, commit.ID))
+		data, err := ioutil.ReadFile(filePath)
+		require.NoError(t, err)
+		require.Equal(t, "hello", string(data))
 	})
 }
 
-func TestMountCachingViaShell(t *testing.T) {
+func TestReadOutsideShard(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipped because of short mode")
 	}
 
-	testFuse(t, func(c client.APIClient, mountpoint string) {
-		repo1 := "foo"
-		require.NoError(t, c.CreateRepo(repo1))
-
-		// Now if we walk the FS we should see the file.
-		// This first act of 'walking' should also initiate the cache
-
-		ls := exec.Command("ls")
-		ls.Dir = mountpoint
-		out, err := ls.Output()
-		require.NoError(t, err)
-
-		require.Equal(t, "foo\n", string(out))
-
-		// Now create another repo, and look for it under the mount point
-		repo2 := "bar"
-		require.NoError(t, c.CreateRepo(repo2))
-
-		// Now if we walk the FS we should see the new file.
-		// This second ls on mac doesn't report the file!
-
-		ls = exec.Command("ls")
-		ls.Dir = mountpoint
-		out, err = ls.Output()
+	shard := &pfsclient.Shard{FileNumber: 0, FileModulus: 2, BlockModulus: 1}
+	testFuseWithOptions(t, &fuse.Options{Shards: []*pfsclient.Shard{shard}}, func(c client.APIClient, mountpoint string) {
+		repoName := "foo"
+		require.NoError(t, c.CreateRepo(repoName))
+		commit, err := c.StartCommit(repoName, "", "")
 		require.NoError(t, err)
+		var names []string
+		for i := 0; i < 10; i++ {
+			name := fmt.Sprintf("file%d", i)
+			names = append(names, name)
+			_, err = c.PutFile(repoName, commit.ID, name, strings.NewReader(name))
+			require.NoError(t, err)
+		}
+		require.NoError(t, c.FinishCommit(repoName, commit.ID))
 
-		require.Equal(t, true, "foo\nbar\n" == string(out) || "bar\nfoo\n" == string(out))
-
+		var inShard, outsideShard int
+		for _, name := range names {
+			data, err := ioutil.ReadFile(filepath.Join(mountpoint, repoName, commit.ID, name))
+			if pfsserver.FileInShard(shard, &pfsclient.File{Path: name}) {
+				inShard++
+				require.NoError(t, err)
+				require.Equal(t, name, string(data))
+			} else {
+				outsideShard++
+				require.YesError(t, err)
+			}
+		}
+		require.True(t, inShard > 0)
+		require.True(t, outsideShard > 0)
 	})
 }
 
 func testFuse(
 	t *testing.T,
 	test func(client client.APIClient, mountpoint string),
+) {
+	testFuseWithOptions(t, nil, test)
+}
+
+func testFuseWithOptions(
+	t *testing.T,
+	opts *fuse.Options,
+	test func(client client.APIClient, mountpoint string),
+) {
+	testFuseWithCommitMounts(t, nil, opts, test)
+}
+
+func testFuseWithCommitMounts(
+	t *testing.T,
+	commitMounts []*fuse.CommitMount,
+	opts *fuse.Options,
+	test func(client client.APIClient, mountpoint string),
 ) {
 	fmt.Printf("XXX NEW TEST\n")
 	// don't leave goroutines running
@@ -473,7 +992,7 @@ func testFuse(
 	go func() {
 		defer wg.Done()
 		fmt.Printf("XXX mounting\n")
-		require.NoError(t, mounter.MountAndCreate(mountpoint, nil, nil, ready))
+		require.NoError(t, mounter.MountAndCreate(mountpoint, nil, commitMounts, opts, ready))
 	}()
 
 	<-ready
@@ -488,3 +1007,388 @@ func testFuse(
 	test(client.APIClient{PfsAPIClient: apiClient}, mountpoint)
 	fmt.Printf("XXX ran test callback\n")
 }
+
+func TestAccess(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+
+	testFuse(t, func(c client.APIClient, mountpoint string) {
+		repoName := "foo"
+		require.NoError(t, c.CreateRepo(repoName))
+		commit, err := c.StartCommit(repoName, "", "")
+		require.NoError(t, err)
+		_, err = c.PutFile(repoName, commit.ID, "file", strings.NewReader("foo"))
+		require.NoError(t, err)
+		// W_OK and R_OK, syscall doesn't define them
+		const wOK, rOK = 0x2, 0x4
+		filePath := filepath.Join(mountpoint, repoName, commit.ID, "file")
+		require.NoError(t, syscall.Access(filePath, rOK))
+		require.NoError(t, syscall.Access(filePath, wOK))
+		require.NoError(t, syscall.Access(filepath.Join(mountpoint, repoName, commit.ID), wOK))
+		require.NoError(t, c.FinishCommit(repoName, commit.ID))
+
+		require.NoError(t, syscall.Access(filePath, rOK))
+		require.Equal(t, syscall.EACCES, syscall.Access(filePath, wOK))
+		require.Equal(t, syscall.EACCES, syscall.Access(filepath.Join(mountpoint, repoName), wOK))
+	})
+}
+
+func TestEmptyRepoReadDir(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+
+	testFuse(t, func(c client.APIClient, mountpoint string) {
+		repoName := "foo"
+		require.NoError(t, c.CreateRepo(repoName))
+		require.NoError(t, fstestutil.CheckDir(filepath.Join(mountpoint, repoName), nil))
+	})
+
+	commitMounts := []*fuse.CommitMount{
+		{
+			Commit: client.NewCommit("foo", ""),
+		},
+	}
+	testFuseWithCommitMounts(t, commitMounts, nil, func(c client.APIClient, mountpoint string) {
+		repoName := "foo"
+		require.NoError(t, c.CreateRepo(repoName))
+		require.NoError(t, fstestutil.CheckDir(filepath.Join(mountpoint, repoName), nil))
+	})
+}
+
+func TestLatestCommit(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+
+	testFuseWithOptions(t, &fuse.Options{LatestCommit: true}, func(c client.APIClient, mountpoint string) {
+		repoName := "foo"
+		require.NoError(t, c.CreateRepo(repoName))
+		commit1, err := c.StartCommit(repoName, "", "master")
+		require.NoError(t, err)
+		_, err = c.PutFile(repoName, commit1.ID, "file", strings.NewReader("foo"))
+		require.NoError(t, err)
+		require.NoError(t, c.FinishCommit(repoName, commit1.ID))
+		// an open commit doesn't count
+		commit2, err := c.StartCommit(repoName, "", "master")
+		require.NoError(t, err)
+		_, err = c.PutFile(repoName, commit2.ID, "file", strings.NewReader("bar"))
+		require.NoError(t, err)
+
+		data, err := ioutil.ReadFile(filepath.Join(mountpoint, repoName, "file"))
+		require.NoError(t, err)
+		require.Equal(t, "foo", string(data))
+	})
+}
+
+func TestWatchCommits(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+
+	testFuseWithOptions(t, &fuse.Options{WatchCommits: true}, func(c client.APIClient, mountpoint string) {
+		repoName := "foo"
+		require.NoError(t, c.CreateRepo(repoName))
+		commit1, err := c.StartCommit(repoName, "", "master")
+		require.NoError(t, err)
+		require.NoError(t, c.FinishCommit(repoName, commit1.ID))
+		commit2, err := c.StartCommit(repoName, "", "master")
+		require.NoError(t, err)
+
+		path := filepath.Join(mountpoint, repoName, commit2.ID)
+		stat, err := os.Stat(path)
+		require.NoError(t, err)
+		require.True(t, stat.Mode()&0222 != 0)
+		require.NoError(t, c.FinishCommit(repoName, commit2.ID))
+		// the watcher flips the directory shortly after the commit finishes
+		for i := 0; i < 50; i++ {
+			stat, err = os.Stat(path)
+			require.NoError(t, err)
+			if stat.Mode()&0222 == 0 {
+				return
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		t.Fatalf("%s is still writable after its commit finished", path)
+	})
+}
+
+func TestDirSizes(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+
+	testFuseWithOptions(t, &fuse.Options{DirSizes: true}, func(c client.APIClient, mountpoint string) {
+		repoName := "foo"
+		require.NoError(t, c.CreateRepo(repoName))
+		commit, err := c.StartCommit(repoName, "", "")
+		require.NoError(t, err)
+		_, err = c.PutFile(repoName, commit.ID, "dir/file1", strings.NewReader("foo"))
+		require.NoError(t, err)
+		_, err = c.PutFile(repoName, commit.ID, "dir/subdir/file2", strings.NewReader("barbuzz"))
+		require.NoError(t, err)
+		require.NoError(t, c.FinishCommit(repoName, commit.ID))
+
+		stat, err := os.Stat(filepath.Join(mountpoint, repoName, commit.ID, "dir"))
+		require.NoError(t, err)
+		require.Equal(t, int64(10), stat.Size())
+		stat, err = os.Stat(filepath.Join(mountpoint, repoName, commit.ID, "dir", "subdir"))
+		require.NoError(t, err)
+		require.Equal(t, int64(7), stat.Size())
+	})
+}
+
+func TestReadThroughPageCache(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+
+	testFuse(t, func(c client.APIClient, mountpoint string) {
+		repoName := "foo"
+		require.NoError(t, c.CreateRepo(repoName))
+		commit, err := c.StartCommit(repoName, "", "")
+		require.NoError(t, err)
+		// bigger than a page and the max chunk size, so the kernel splits it
+		data := []byte(strings.Repeat("abcdefgh\n", 300*1024))
+		_, err = c.PutFile(repoName, commit.ID, "file", bytes.NewReader(data))
+		require.NoError(t, err)
+		require.NoError(t, c.FinishCommit(repoName, commit.ID))
+
+		path := filepath.Join(mountpoint, repoName, commit.ID, "file")
+		// the second read is served from the cache kept between opens
+		for i := 0; i < 2; i++ {
+			read, err := ioutil.ReadFile(path)
+			require.NoError(t, err)
+			require.Equal(t, data, read)
+		}
+	})
+}
+
+func TestConcurrentAppendsMounted(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+
+	testFuse(t, func(c client.APIClient, mountpoint string) {
+		repoName := "foo"
+		require.NoError(t, c.CreateRepo(repoName))
+		commit, err := c.StartCommit(repoName, "", "")
+		require.NoError(t, err)
+		path := filepath.Join(mountpoint, repoName, commit.ID, "log")
+		var files []*os.File
+		for i := 0; i < 2; i++ {
+			file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
+			require.NoError(t, err)
+			files = append(files, file)
+		}
+		for _, line := range []string{"a1\n", "b1\n", "a2\n", "b2\n"} {
+			file := files[0]
+			if line[0] == 'b' {
+				file = files[1]
+			}
+			_, err := file.Write([]byte(line))
+			require.NoError(t, err)
+		}
+		for _, file := range files {
+			require.NoError(t, file.Close())
+		}
+		require.NoError(t, c.FinishCommit(repoName, commit.ID))
+		var buffer bytes.Buffer
+		require.NoError(t, c.GetFile(repoName, commit.ID, "log", 0, 0, "", nil, &buffer))
+		require.Equal(t, "a1\nb1\na2\nb2\n", buffer.String())
+	})
+}
+
+func TestCommitPaths(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+
+	opts := &fuse.Options{LatestCommit: true, CommitPaths: true}
+	testFuseWithOptions(t, opts, func(c client.APIClient, mountpoint string) {
+		repoName := "foo"
+		require.NoError(t, c.CreateRepo(repoName))
+		var commitIDs []string
+		for _, data := range []string{"foo", "bar"} {
+			commit, err := c.StartCommit(repoName, "", "master")
+			require.NoError(t, err)
+			_, err = c.PutFile(repoName, commit.ID, "file", strings.NewReader(data))
+			require.NoError(t, err)
+			require.NoError(t, c.FinishCommit(repoName, commit.ID))
+			commitIDs = append(commitIDs, commit.ID)
+		}
+		// the first commit is readable even though the latest is the default
+		data, err := ioutil.ReadFile(filepath.Join(mountpoint, repoName, commitIDs[0], "file"))
+		require.NoError(t, err)
+		require.Equal(t, "foo", string(data))
+		data, err = ioutil.ReadFile(filepath.Join(mountpoint, repoName, commitIDs[1], "file"))
+		require.NoError(t, err)
+		require.True(t, strings.HasSuffix(string(data), "bar"))
+	})
+}
+
+func TestForceReadOnly(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+
+	opts := &fuse.Options{ForceReadOnly: true}
+	testFuseWithOptions(t, opts, func(c client.APIClient, mountpoint string) {
+		repoName := "foo"
+		require.NoError(t, c.CreateRepo(repoName))
+		commit, err := c.StartCommit(repoName, "", "")
+		require.NoError(t, err)
+		_, err = c.PutFile(repoName, commit.ID, "file", strings.NewReader("foo"))
+		require.NoError(t, err)
+		commitPath := filepath.Join(mountpoint, repoName, commit.ID)
+
+		data, err := ioutil.ReadFile(filepath.Join(commitPath, "file"))
+		require.NoError(t, err)
+		require.Equal(t, "foo", string(data))
+		stat, err := os.Stat(commitPath)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0), stat.Mode()&0222)
+
+		requireReadOnly(t, commitPath)
+
+		require.NoError(t, c.FinishCommit(repoName, commit.ID))
+		var buffer bytes.Buffer
+		require.NoError(t, c.GetFile(repoName, commit.ID, "file", 0, 0, "", nil, &buffer))
+		require.Equal(t, "foo", buffer.String())
+	})
+}
+
+func TestStatDotDot(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+
+	testFuse(t, func(c client.APIClient, mountpoint string) {
+		repoName := "foo"
+		require.NoError(t, c.CreateRepo(repoName))
+		commit, err := c.StartCommit(repoName, "", "")
+		require.NoError(t, err)
+		_, err = c.PutFile(repoName, commit.ID, "dir/file", strings.NewReader("foo"))
+		require.NoError(t, err)
+		require.NoError(t, c.FinishCommit(repoName, commit.ID))
+		commitPath := filepath.Join(mountpoint, repoName, commit.ID)
+
+		// filepath.Join would clean the dots away
+		for _, name := range []string{"/dir/..", "/dir/.", "/dir/../dir/file"} {
+			_, err := os.Stat(commitPath + name)
+			require.NoError(t, err)
+		}
+		stat, err := os.Stat(commitPath + "/dir/..")
+		require.NoError(t, err)
+		commitStat, err := os.Stat(commitPath)
+		require.NoError(t, err)
+		require.True(t, os.SameFile(stat, commitStat))
+		names, err := ioutil.ReadDir(commitPath + "/dir/..")
+		require.NoError(t, err)
+		require.Equal(t, 1, len(names))
+		require.Equal(t, "dir", names[0].Name())
+	})
+}
+
+func TestSingleCommit(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+
+	opts := &fuse.Options{SingleCommit: true}
+	testFuseWithOptions(t, opts, func(c client.APIClient, mountpoint string) {
+		for _, repo := range []struct {
+			name    string
+			commits int
+		}{
+			{"single", 1},
+			{"multiple", 2},
+		} {
+			require.NoError(t, c.CreateRepo(repo.name))
+			for i := 0; i < repo.commits; i++ {
+				commit, err := c.StartCommit(repo.name, "", "master")
+				require.NoError(t, err)
+				_, err = c.PutFile(repo.name, commit.ID, "file", strings.NewReader("foo"))
+				require.NoError(t, err)
+				require.NoError(t, c.FinishCommit(repo.name, commit.ID))
+			}
+		}
+		// the commit level is skipped for the repo with one commit
+		data, err := ioutil.ReadFile(filepath.Join(mountpoint, "single", "file"))
+		require.NoError(t, err)
+		require.Equal(t, "foo", string(data))
+
+		commitInfos, err := c.ListCommit([]string{"multiple"}, nil, client.CommitTypeNone, false, false, nil)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(commitInfos))
+		names, err := ioutil.ReadDir(filepath.Join(mountpoint, "multiple"))
+		require.NoError(t, err)
+		require.Equal(t, 2, len(names))
+		data, err = ioutil.ReadFile(filepath.Join(mountpoint, "multiple", commitInfos[0].Commit.ID, "file"))
+		require.NoError(t, err)
+		require.True(t, strings.HasSuffix(string(data), "foo"))
+	})
+}
+
+func TestDecompress(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+
+	opts := &fuse.Options{DecompressExtensions: []string{".gz"}}
+	testFuseWithOptions(t, opts, func(c client.APIClient, mountpoint string) {
+		repoName := "foo"
+		require.NoError(t, c.CreateRepo(repoName))
+		commit, err := c.StartCommit(repoName, "", "")
+		require.NoError(t, err)
+		data := strings.Repeat("foobarbaz", 1000)
+		var compressed bytes.Buffer
+		w := gzip.NewWriter(&compressed)
+		_, err = w.Write([]byte(data))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		_, err = c.PutFile(repoName, commit.ID, "file.gz", &compressed)
+		require.NoError(t, err)
+		require.NoError(t, c.FinishCommit(repoName, commit.ID))
+
+		read, err := ioutil.ReadFile(filepath.Join(mountpoint, repoName, commit.ID, "file.gz"))
+		require.NoError(t, err)
+		require.Equal(t, data, string(read))
+	})
+}
+
+func TestFinishedCommitReadOnly(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipped because of short mode")
+	}
+
+	testFuse(t, func(c client.APIClient, mountpoint string) {
+		repoName := "foo"
+		require.NoError(t, c.CreateRepo(repoName))
+		commit, err := c.StartCommit(repoName, "", "")
+		require.NoError(t, err)
+		_, err = c.PutFile(repoName, commit.ID, "file", strings.NewReader("foo"))
+		require.NoError(t, err)
+		require.NoError(t, c.FinishCommit(repoName, commit.ID))
+
+		requireReadOnly(t, filepath.Join(mountpoint, repoName, commit.ID))
+		// creating outside of a commit isn't a read only filesystem, it's
+		// just not allowed
+		err = os.Mkdir(filepath.Join(mountpoint, repoName, "dir"), 0755)
+		require.Equal(t, syscall.EPERM, err.(*os.PathError).Err)
+	})
+}
+
+// requireReadOnly checks that writing, creating, making a directory and
+// removing in commitPath, which has a file called file, fail with EROFS.
+func requireReadOnly(t *testing.T, commitPath string) {
+	err := ioutil.WriteFile(filepath.Join(commitPath, "file"), []byte("bar"), 0644)
+	require.Equal(t, syscall.EROFS, err.(*os.PathError).Err)
+	err = ioutil.WriteFile(filepath.Join(commitPath, "other"), []byte("bar"), 0644)
+	require.Equal(t, syscall.EROFS, err.(*os.PathError).Err)
+	err = os.Mkdir(filepath.Join(commitPath, "dir"), 0755)
+	require.Equal(t, syscall.EROFS, err.(*os.PathError).Err)
+	err = os.Remove(filepath.Join(commitPath, "file"))
+	require.Equal(t, syscall.EROFS, err.(*os.PathError).Err)
+}
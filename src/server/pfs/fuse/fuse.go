@@ -1,7 +1,11 @@
 package fuse
 
 import (
+	"os"
+	"time"
+
 	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
+	"go.pedge.io/lion"
 )
 
 type Mounter interface {
@@ -11,6 +15,7 @@ type Mounter interface {
 		mountPoint string,
 		shard *pfsclient.Shard,
 		commitMounts []*CommitMount, // nil means mount all commits
+		opts *Options, // nil means default options
 		ready chan bool,
 	) error
 
@@ -18,6 +23,7 @@ type Mounter interface {
 		mountPoint string,
 		shard *pfsclient.Shard,
 		commitMounts []*CommitMount, // nil means mount all commits
+		opts *Options, // nil means default options
 		ready chan bool,
 	) error
 	// Unmount unmounts a mounted filesystem (duh).
@@ -25,6 +31,89 @@ type Mounter interface {
 	Unmount(mountPoint string) error
 }
 
+// Options are mount-wide settings.
+type Options struct {
+	// ReadTimeout bounds each remote call made to serve a read, so that a
+	// hung server shows up as EIO rather than a hung mount. 0 means no
+	// timeout.
+	ReadTimeout time.Duration
+	// MaxRetries is how many times a read from pfs that fails with
+	// Unavailable or DeadlineExceeded is retried, with exponential backoff,
+	// before it returns EIO. 0 means no retries.
+	MaxRetries int
+	// Shards, when set, are merged into a single view for repos that don't
+	// have a CommitMount with their own shards. They take precedence over
+	// the shard passed to Mount.
+	Shards []*pfsclient.Shard
+	// FinishCommit makes a write to .finish at the root of a writable commit
+	// finish that commit once all of its open handles have been flushed.
+	FinishCommit bool
+	// FromCommits maps repo names or aliases to a commit ID, those repos
+	// only show files that have changed since that commit. CommitMounts with
+	// their own FromCommit take precedence.
+	FromCommits map[string]string
+	// VerifyWrites makes every flush check that pfs has all the bytes that
+	// were written, at the cost of an extra round trip.
+	VerifyWrites bool
+	// LatestCommit makes repos without a commit in their CommitMount show
+	// the files in their most recently finished commit, rather than a
+	// directory per commit. Repos with no finished commits still list their
+	// commits.
+	LatestCommit bool
+	// SingleCommit makes repos without a commit in their CommitMount, whose
+	// only commit is finished, show that commit's files rather than a
+	// directory with one commit in it. Repos with more commits, or an open
+	// one, still list their commits.
+	SingleCommit bool
+	// CommitNames lists commits as branch@start-time rather than by ID,
+	// commits can still be looked up by ID.
+	CommitNames bool
+	// MaxChunkSize is the most bytes sent to or requested from pfs in one
+	// message, larger reads and writes are split up. 0 means 1MB.
+	MaxChunkSize int
+	// DryRun keeps writes in memory instead of sending them to pfs, they
+	// can be read back through the mount until it's unmounted. Commits are
+	// never finished.
+	DryRun bool
+	// LogLevel is the lowest level the mount logs at. Per-operation logs
+	// are at debug, anything above LevelDebug skips them entirely, including
+	// the cost of building them. The zero value is LevelDebug.
+	LogLevel lion.Level
+	// FileMode is the permission bits of files in open commits, files in
+	// finished commits get the same bits without write, plus execute for
+	// scripts. 0 means 0666.
+	FileMode os.FileMode
+	// DirMode is FileMode for directories, read only directories are
+	// everything that isn't an open commit. 0 means 0775.
+	DirMode os.FileMode
+	// WatchCommits watches the open commits that are looked up, and makes
+	// them read only once they're finished elsewhere rather than at their
+	// next lookup.
+	WatchCommits bool
+	// DirSizes makes directories in finished commits report the total size
+	// of the files under them. It takes a recursive listing so it's only
+	// done when a directory is stat'd, ReadDirAll doesn't wait for it, and
+	// the result is cached.
+	DirSizes bool
+	// CommitPaths makes every repo a directory of its commits, so that any
+	// commit can be read as repo/commit-id/path without a CommitMount for
+	// it. Commits from CommitMounts, LatestCommit and SingleCommit aren't
+	// shown at the top of the repo, but they can still be looked up by ID.
+	CommitPaths bool
+	// ForceReadOnly makes open commits read only like finished ones, so
+	// nothing can be created, written or removed through the mount.
+	ForceReadOnly bool
+	// DecompressExtensions are file name extensions, e.g. ".gz", of files
+	// that are gzipped. Those files in finished commits are read
+	// decompressed, they can only be read in order since gzip can't be
+	// decompressed from the middle, so they aren't seekable. Their size is
+	// still the compressed size.
+	DecompressExtensions []string
+	// Metrics, if set, records the mount's lookups, listings, reads and
+	// writes, see NewMetrics.
+	Metrics *Metrics
+}
+
 // NewMounter creates a new Mounter.
 // Address can be left blank, it's used only for aesthetic purposes.
 func NewMounter(address string, apiClient pfsclient.APIClient) Mounter {
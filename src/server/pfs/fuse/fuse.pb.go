@@ -19,12 +19,19 @@ It has these top-level messages:
 	DirectoryLookup
 	DirectoryReadDirAll
 	DirectoryCreate
+	DirectoryOpen
+	NodeAccess
+	DirectoryMknod
 	DirectoryMkdir
 	FileAttr
 	FileRead
 	FileOpen
 	FileWrite
 	FileRemove
+	OpenWriters
+	CommitWatch
+	SelfTestStep
+	SelfTest
 */
 package fuse
 
@@ -32,6 +39,7 @@ import proto "github.com/golang/protobuf/proto"
 import fmt "fmt"
 import math "math"
 import pfs "github.com/pachyderm/pachyderm/src/client/pfs"
+import google_protobuf4 "go.pedge.io/pb/go/google/protobuf"
 import google_protobuf2 "go.pedge.io/pb/go/google/protobuf"
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -48,6 +56,12 @@ type CommitMount struct {
 	FromCommit *pfs.Commit `protobuf:"bytes,2,opt,name=from_commit,json=fromCommit" json:"from_commit,omitempty"`
 	Alias      string      `protobuf:"bytes,3,opt,name=alias" json:"alias,omitempty"`
 	Shard      *pfs.Shard  `protobuf:"bytes,4,opt,name=shard" json:"shard,omitempty"`
+	// shards, when set, are merged into a single view and take precedence
+	// over shard.
+	Shards []*pfs.Shard `protobuf:"bytes,5,rep,name=shards" json:"shards,omitempty"`
+	// path, when set, is the directory within the commit that's mounted, it
+	// takes the place of the commit's root.
+	Path string `protobuf:"bytes,6,opt,name=path" json:"path,omitempty"`
 }
 
 func (m *CommitMount) Reset()                    { *m = CommitMount{} }
@@ -76,6 +90,13 @@ func (m *CommitMount) GetShard() *pfs.Shard {
 	return nil
 }
 
+func (m *CommitMount) GetShards() []*pfs.Shard {
+	if m != nil {
+		return m.Shards
+	}
+	return nil
+}
+
 type Filesystem struct {
 	Shard        *pfs.Shard     `protobuf:"bytes,1,opt,name=shard" json:"shard,omitempty"`
 	CommitMounts []*CommitMount `protobuf:"bytes,2,rep,name=commit_mounts,json=commitMounts" json:"commit_mounts,omitempty"`
@@ -106,6 +127,7 @@ type Node struct {
 	Write     bool                        `protobuf:"varint,3,opt,name=write" json:"write,omitempty"`
 	Shard     *pfs.Shard                  `protobuf:"bytes,4,opt,name=shard" json:"shard,omitempty"`
 	Modified  *google_protobuf2.Timestamp `protobuf:"bytes,5,opt,name=modified" json:"modified,omitempty"`
+	Shards    []*pfs.Shard                `protobuf:"bytes,6,rep,name=shards" json:"shards,omitempty"`
 }
 
 func (m *Node) Reset()                    { *m = Node{} }
@@ -134,6 +156,13 @@ func (m *Node) GetModified() *google_protobuf2.Timestamp {
 	return nil
 }
 
+func (m *Node) GetShards() []*pfs.Shard {
+	if m != nil {
+		return m.Shards
+	}
+	return nil
+}
+
 type Attr struct {
 	Mode uint32 `protobuf:"varint,1,opt,name=Mode,json=mode" json:"Mode,omitempty"`
 }
@@ -279,6 +308,66 @@ func (m *DirectoryCreate) GetResult() *Node {
 	return nil
 }
 
+type DirectoryOpen struct {
+	Directory *Node  `protobuf:"bytes,1,opt,name=directory" json:"directory,omitempty"`
+	Error     string `protobuf:"bytes,2,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *DirectoryOpen) Reset()                    { *m = DirectoryOpen{} }
+func (m *DirectoryOpen) String() string            { return proto.CompactTextString(m) }
+func (*DirectoryOpen) ProtoMessage()               {}
+func (*DirectoryOpen) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+func (m *DirectoryOpen) GetDirectory() *Node {
+	if m != nil {
+		return m.Directory
+	}
+	return nil
+}
+
+type NodeAccess struct {
+	Node  *Node  `protobuf:"bytes,1,opt,name=node" json:"node,omitempty"`
+	Mask  uint32 `protobuf:"varint,2,opt,name=mask" json:"mask,omitempty"`
+	Error string `protobuf:"bytes,3,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *NodeAccess) Reset()                    { *m = NodeAccess{} }
+func (m *NodeAccess) String() string            { return proto.CompactTextString(m) }
+func (*NodeAccess) ProtoMessage()               {}
+func (*NodeAccess) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *NodeAccess) GetNode() *Node {
+	if m != nil {
+		return m.Node
+	}
+	return nil
+}
+
+type DirectoryMknod struct {
+	Directory *Node  `protobuf:"bytes,1,opt,name=directory" json:"directory,omitempty"`
+	Result    *Node  `protobuf:"bytes,2,opt,name=result" json:"result,omitempty"`
+	Error     string `protobuf:"bytes,3,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *DirectoryMknod) Reset()                    { *m = DirectoryMknod{} }
+func (m *DirectoryMknod) String() string            { return proto.CompactTextString(m) }
+func (*DirectoryMknod) ProtoMessage()               {}
+func (*DirectoryMknod) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+func (m *DirectoryMknod) GetDirectory() *Node {
+	if m != nil {
+		return m.Directory
+	}
+	return nil
+}
+
+func (m *DirectoryMknod) GetResult() *Node {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
 type DirectoryMkdir struct {
 	Directory *Node  `protobuf:"bytes,1,opt,name=directory" json:"directory,omitempty"`
 	Result    *Node  `protobuf:"bytes,2,opt,name=result" json:"result,omitempty"`
@@ -288,7 +377,7 @@ type DirectoryMkdir struct {
 func (m *DirectoryMkdir) Reset()                    { *m = DirectoryMkdir{} }
 func (m *DirectoryMkdir) String() string            { return proto.CompactTextString(m) }
 func (*DirectoryMkdir) ProtoMessage()               {}
-func (*DirectoryMkdir) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+func (*DirectoryMkdir) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
 
 func (m *DirectoryMkdir) GetDirectory() *Node {
 	if m != nil {
@@ -313,7 +402,7 @@ type FileAttr struct {
 func (m *FileAttr) Reset()                    { *m = FileAttr{} }
 func (m *FileAttr) String() string            { return proto.CompactTextString(m) }
 func (*FileAttr) ProtoMessage()               {}
-func (*FileAttr) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+func (*FileAttr) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
 
 func (m *FileAttr) GetFile() *Node {
 	if m != nil {
@@ -337,7 +426,7 @@ type FileRead struct {
 func (m *FileRead) Reset()                    { *m = FileRead{} }
 func (m *FileRead) String() string            { return proto.CompactTextString(m) }
 func (*FileRead) ProtoMessage()               {}
-func (*FileRead) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+func (*FileRead) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
 
 func (m *FileRead) GetFile() *Node {
 	if m != nil {
@@ -354,7 +443,7 @@ type FileOpen struct {
 func (m *FileOpen) Reset()                    { *m = FileOpen{} }
 func (m *FileOpen) String() string            { return proto.CompactTextString(m) }
 func (*FileOpen) ProtoMessage()               {}
-func (*FileOpen) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+func (*FileOpen) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
 
 func (m *FileOpen) GetFile() *Node {
 	if m != nil {
@@ -371,7 +460,7 @@ type FileWrite struct {
 func (m *FileWrite) Reset()                    { *m = FileWrite{} }
 func (m *FileWrite) String() string            { return proto.CompactTextString(m) }
 func (*FileWrite) ProtoMessage()               {}
-func (*FileWrite) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+func (*FileWrite) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
 
 func (m *FileWrite) GetFile() *Node {
 	if m != nil {
@@ -388,7 +477,7 @@ type FileRemove struct {
 func (m *FileRemove) Reset()                    { *m = FileRemove{} }
 func (m *FileRemove) String() string            { return proto.CompactTextString(m) }
 func (*FileRemove) ProtoMessage()               {}
-func (*FileRemove) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+func (*FileRemove) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
 
 func (m *FileRemove) GetFile() *Node {
 	if m != nil {
@@ -397,6 +486,85 @@ func (m *FileRemove) GetFile() *Node {
 	return nil
 }
 
+type OpenWriters struct {
+	File []*Node `protobuf:"bytes,1,rep,name=file" json:"file,omitempty"`
+}
+
+func (m *OpenWriters) Reset()                    { *m = OpenWriters{} }
+func (m *OpenWriters) String() string            { return proto.CompactTextString(m) }
+func (*OpenWriters) ProtoMessage()               {}
+func (*OpenWriters) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+
+func (m *OpenWriters) GetFile() []*Node {
+	if m != nil {
+		return m.File
+	}
+	return nil
+}
+
+type CommitWatch struct {
+	Commit *pfs.Commit `protobuf:"bytes,1,opt,name=commit" json:"commit,omitempty"`
+	Error  string      `protobuf:"bytes,2,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *CommitWatch) Reset()                    { *m = CommitWatch{} }
+func (m *CommitWatch) String() string            { return proto.CompactTextString(m) }
+func (*CommitWatch) ProtoMessage()               {}
+func (*CommitWatch) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+
+func (m *CommitWatch) GetCommit() *pfs.Commit {
+	if m != nil {
+		return m.Commit
+	}
+	return nil
+}
+
+// SelfTestStep is one call made by a self test, see filesystem.SelfTest.
+type SelfTestStep struct {
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// file is what the call was about, e.g. just the repo for list_commit
+	File     *pfs.File                  `protobuf:"bytes,2,opt,name=file" json:"file,omitempty"`
+	Duration *google_protobuf4.Duration `protobuf:"bytes,3,opt,name=duration" json:"duration,omitempty"`
+	Error    string                     `protobuf:"bytes,4,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *SelfTestStep) Reset()                    { *m = SelfTestStep{} }
+func (m *SelfTestStep) String() string            { return proto.CompactTextString(m) }
+func (*SelfTestStep) ProtoMessage()               {}
+func (*SelfTestStep) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+
+func (m *SelfTestStep) GetFile() *pfs.File {
+	if m != nil {
+		return m.File
+	}
+	return nil
+}
+
+func (m *SelfTestStep) GetDuration() *google_protobuf4.Duration {
+	if m != nil {
+		return m.Duration
+	}
+	return nil
+}
+
+type SelfTest struct {
+	// steps stop at the first that failed, or early if there was nothing to
+	// test the next step on, e.g. no repos
+	Step []*SelfTestStep `protobuf:"bytes,1,rep,name=step" json:"step,omitempty"`
+}
+
+func (m *SelfTest) Reset()                    { *m = SelfTest{} }
+func (m *SelfTest) String() string            { return proto.CompactTextString(m) }
+func (*SelfTest) ProtoMessage()               {}
+func (*SelfTest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+
+func (m *SelfTest) GetStep() []*SelfTestStep {
+	if m != nil {
+		return m.Step
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*CommitMount)(nil), "fuse.CommitMount")
 	proto.RegisterType((*Filesystem)(nil), "fuse.Filesystem")
@@ -408,51 +576,69 @@ func init() {
 	proto.RegisterType((*DirectoryLookup)(nil), "fuse.DirectoryLookup")
 	proto.RegisterType((*DirectoryReadDirAll)(nil), "fuse.DirectoryReadDirAll")
 	proto.RegisterType((*DirectoryCreate)(nil), "fuse.DirectoryCreate")
+	proto.RegisterType((*DirectoryOpen)(nil), "fuse.DirectoryOpen")
+	proto.RegisterType((*NodeAccess)(nil), "fuse.NodeAccess")
+	proto.RegisterType((*DirectoryMknod)(nil), "fuse.DirectoryMknod")
 	proto.RegisterType((*DirectoryMkdir)(nil), "fuse.DirectoryMkdir")
 	proto.RegisterType((*FileAttr)(nil), "fuse.FileAttr")
 	proto.RegisterType((*FileRead)(nil), "fuse.FileRead")
 	proto.RegisterType((*FileOpen)(nil), "fuse.FileOpen")
 	proto.RegisterType((*FileWrite)(nil), "fuse.FileWrite")
 	proto.RegisterType((*FileRemove)(nil), "fuse.FileRemove")
+	proto.RegisterType((*OpenWriters)(nil), "fuse.OpenWriters")
+	proto.RegisterType((*CommitWatch)(nil), "fuse.CommitWatch")
+	proto.RegisterType((*SelfTestStep)(nil), "fuse.SelfTestStep")
+	proto.RegisterType((*SelfTest)(nil), "fuse.SelfTest")
 }
 
 var fileDescriptor0 = []byte{
-	// 579 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xbc, 0x54, 0x4d, 0x6f, 0xd3, 0x40,
-	0x10, 0x95, 0x13, 0x27, 0x8a, 0x27, 0x0d, 0x94, 0xa5, 0x87, 0x28, 0x12, 0x50, 0x19, 0x0e, 0x3d,
-	0x20, 0x07, 0x15, 0x89, 0x33, 0xa1, 0x88, 0x13, 0x01, 0x69, 0x41, 0xe2, 0x18, 0xb9, 0xf1, 0xba,
-	0x58, 0xf5, 0x66, 0xad, 0xdd, 0x75, 0x51, 0xc5, 0x99, 0xff, 0xc0, 0x0f, 0xe1, 0x07, 0xb2, 0x3b,
-	0xeb, 0x2f, 0x44, 0xa3, 0xd0, 0x20, 0xf5, 0x90, 0x68, 0x67, 0xe7, 0x79, 0xde, 0x9b, 0x37, 0x63,
-	0xc3, 0x4c, 0x31, 0x79, 0xc5, 0xe4, 0xbc, 0x48, 0xd5, 0x3c, 0x2d, 0x15, 0xc3, 0xbf, 0xa8, 0x90,
-	0x42, 0x0b, 0xe2, 0xdb, 0xf3, 0xec, 0x68, 0x9d, 0x67, 0x6c, 0xa3, 0x11, 0x61, 0x7e, 0x2e, 0x37,
-	0x7b, 0x72, 0x21, 0xc4, 0x45, 0xce, 0xe6, 0x18, 0x9d, 0x97, 0xe9, 0x5c, 0x67, 0x9c, 0x29, 0x1d,
-	0xf3, 0xc2, 0x01, 0xc2, 0x9f, 0x1e, 0x8c, 0xcf, 0x04, 0xe7, 0x99, 0x5e, 0x8a, 0x72, 0xa3, 0xc9,
-	0x53, 0x18, 0xae, 0x31, 0x9c, 0x7a, 0xc7, 0xde, 0xc9, 0xf8, 0x74, 0x1c, 0xd9, 0x62, 0x0e, 0x41,
-	0xab, 0x14, 0x79, 0x0e, 0xe3, 0x54, 0x0a, 0xbe, 0xaa, 0x90, 0xbd, 0xbf, 0x91, 0x60, 0xf3, 0xee,
-	0x4c, 0x8e, 0x60, 0x10, 0xe7, 0x59, 0xac, 0xa6, 0x7d, 0x83, 0x0b, 0xa8, 0x0b, 0xc8, 0x31, 0x0c,
-	0xd4, 0xd7, 0x58, 0x26, 0x53, 0x1f, 0x9f, 0x06, 0x7c, 0xfa, 0x93, 0xbd, 0xa1, 0x2e, 0x11, 0xa6,
-	0x00, 0xef, 0xb2, 0x9c, 0xa9, 0x6b, 0xa5, 0x19, 0x6f, 0xf1, 0xde, 0x16, 0x3c, 0x79, 0x05, 0x13,
-	0x27, 0x68, 0xc5, 0x6d, 0x2b, 0xca, 0xe8, 0xea, 0x1b, 0xe4, 0x83, 0x08, 0xbd, 0xea, 0x34, 0x49,
-	0x0f, 0xd6, 0x6d, 0xa0, 0xc2, 0x5f, 0x1e, 0xf8, 0x1f, 0x44, 0xc2, 0xc8, 0x23, 0xf0, 0x53, 0x43,
-	0x58, 0x31, 0x04, 0xc8, 0x60, 0x15, 0x50, 0xbc, 0x36, 0x69, 0x90, 0xac, 0x10, 0x2b, 0xd7, 0x4c,
-	0x0f, 0x9b, 0x09, 0xec, 0xcd, 0x02, 0x1b, 0x32, 0x6d, 0x7e, 0x93, 0x99, 0x66, 0xd8, 0xe6, 0x88,
-	0xba, 0x60, 0x77, 0x9b, 0x46, 0xf6, 0x88, 0x8b, 0x24, 0x4b, 0x33, 0x96, 0x4c, 0x07, 0x08, 0x9a,
-	0x45, 0x6e, 0x6a, 0x51, 0x3d, 0xb5, 0xe8, 0x73, 0x3d, 0x35, 0xda, 0x60, 0xc3, 0x19, 0xf8, 0x0b,
-	0xad, 0x25, 0x21, 0xe0, 0x2f, 0x8d, 0x7a, 0x54, 0x3d, 0xa1, 0xbe, 0xc9, 0xb3, 0xf0, 0x14, 0x86,
-	0x6f, 0x33, 0x69, 0xd6, 0xc1, 0xaa, 0xca, 0x36, 0x75, 0xda, 0xa7, 0x2e, 0xb0, 0xcf, 0x6c, 0x62,
-	0xce, 0xaa, 0x26, 0xf0, 0x1c, 0x4a, 0xf0, 0xa9, 0x10, 0x9a, 0xbc, 0x00, 0x48, 0x1b, 0xdb, 0x2b,
-	0x2f, 0x0e, 0x9d, 0x87, 0xed, 0x38, 0x68, 0x07, 0x43, 0x42, 0x18, 0x4a, 0xa6, 0xca, 0xbc, 0xde,
-	0x04, 0x70, 0x68, 0xeb, 0x29, 0xad, 0x32, 0x56, 0x07, 0x93, 0x52, 0xc8, 0x7a, 0x09, 0x30, 0x08,
-	0x15, 0x4c, 0xac, 0xce, 0xb5, 0x16, 0xf2, 0x1a, 0x9b, 0x39, 0x81, 0x20, 0xa9, 0x2f, 0x9a, 0x49,
-	0xb7, 0xd5, 0xda, 0xe4, 0x36, 0x52, 0x5b, 0x65, 0x07, 0xe9, 0x0f, 0x0f, 0xee, 0x37, 0xac, 0xef,
-	0x85, 0xb8, 0x2c, 0x8b, 0x5b, 0xf0, 0xde, 0x60, 0x5d, 0x47, 0x4b, 0x7f, 0xab, 0x01, 0x87, 0xd0,
-	0x37, 0xf4, 0xb8, 0x06, 0x01, 0xb5, 0xc7, 0xf0, 0x3b, 0x3c, 0x6c, 0x64, 0x50, 0x16, 0x27, 0x26,
-	0x58, 0xe4, 0xf9, 0x2d, 0xa4, 0x3c, 0xeb, 0x58, 0x60, 0x37, 0xfd, 0xc0, 0xc1, 0xdc, 0xe4, 0x77,
-	0x98, 0x50, 0x76, 0x3c, 0x38, 0x93, 0x2c, 0x36, 0xab, 0xfa, 0xdf, 0xde, 0xff, 0xc3, 0xc0, 0x35,
-	0xdc, 0x6b, 0x68, 0x97, 0x97, 0xa6, 0xe2, 0x9d, 0xb0, 0x26, 0x30, 0xb2, 0xab, 0x8b, 0x1b, 0xf6,
-	0xf8, 0x8f, 0x97, 0xbc, 0x5b, 0xc3, 0xbd, 0xe5, 0xfb, 0xef, 0xd5, 0x6b, 0xc7, 0x62, 0x47, 0xb9,
-	0x93, 0xa5, 0xa9, 0xd0, 0xbb, 0xa1, 0xc2, 0xc7, 0x82, 0x6d, 0xf6, 0xac, 0xb0, 0x80, 0xc0, 0x56,
-	0xf8, 0x82, 0xdf, 0x9e, 0xfd, 0x4a, 0xbc, 0x71, 0x9f, 0x5d, 0xca, 0xb8, 0xb8, 0xda, 0xb3, 0xc6,
-	0xf9, 0x10, 0xbf, 0x5c, 0x2f, 0x7f, 0x07, 0x00, 0x00, 0xff, 0xff, 0xf8, 0x16, 0xd5, 0xea, 0xb7,
-	0x06, 0x00, 0x00,
+	// 756 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x56, 0xcf, 0x6f, 0xd3, 0x30,
+	0x14, 0x56, 0xda, 0xb4, 0x6a, 0x5f, 0x37, 0x18, 0x61, 0x87, 0x50, 0x69, 0xa3, 0x0a, 0x08, 0xed,
+	0x00, 0x2d, 0x1a, 0x62, 0x67, 0xca, 0x26, 0xc4, 0x81, 0x31, 0xc9, 0x9b, 0xd8, 0x71, 0xca, 0x9a,
+	0x97, 0x2d, 0x6a, 0x12, 0x47, 0xb6, 0x33, 0x34, 0x71, 0xe6, 0xca, 0xdf, 0xc6, 0x91, 0x3f, 0x07,
+	0xf9, 0x39, 0x49, 0xd3, 0xad, 0xd5, 0x7e, 0x49, 0xbb, 0x54, 0xb6, 0xdf, 0x97, 0xf7, 0x7d, 0xfe,
+	0xde, 0xb3, 0x5d, 0xe8, 0x4b, 0x14, 0x17, 0x28, 0x46, 0x59, 0x28, 0x47, 0x61, 0x2e, 0x91, 0x7e,
+	0x86, 0x99, 0xe0, 0x8a, 0x3b, 0xb6, 0x1e, 0xf7, 0xd7, 0x27, 0x71, 0x84, 0xa9, 0x22, 0x44, 0x16,
+	0x4a, 0x13, 0xeb, 0x6f, 0x9e, 0x71, 0x7e, 0x16, 0xe3, 0x88, 0x66, 0xa7, 0x79, 0x38, 0x0a, 0x72,
+	0xe1, 0xab, 0x88, 0xa7, 0x45, 0xfc, 0xe5, 0xd5, 0xb8, 0x8a, 0x12, 0x94, 0xca, 0x4f, 0x32, 0x03,
+	0xf0, 0xfe, 0x5a, 0xd0, 0xdb, 0xe5, 0x49, 0x12, 0xa9, 0x7d, 0x9e, 0xa7, 0xca, 0x79, 0x05, 0xed,
+	0x09, 0x4d, 0x5d, 0x6b, 0x60, 0x6d, 0xf5, 0xb6, 0x7b, 0x43, 0x4d, 0x66, 0x10, 0xac, 0x08, 0x39,
+	0x6f, 0xa1, 0x17, 0x0a, 0x9e, 0x9c, 0x14, 0xc8, 0xc6, 0x75, 0x24, 0xe8, 0xb8, 0x19, 0x3b, 0xeb,
+	0xd0, 0xf2, 0xe3, 0xc8, 0x97, 0x6e, 0x73, 0x60, 0x6d, 0x75, 0x99, 0x99, 0x38, 0x03, 0x68, 0xc9,
+	0x73, 0x5f, 0x04, 0xae, 0x4d, 0x5f, 0x03, 0x7d, 0x7d, 0xa8, 0x57, 0x98, 0x09, 0x38, 0x1e, 0xb4,
+	0x69, 0x20, 0xdd, 0xd6, 0xa0, 0x79, 0x05, 0x52, 0x44, 0x1c, 0x07, 0xec, 0xcc, 0x57, 0xe7, 0x6e,
+	0x9b, 0x52, 0xd3, 0xd8, 0x0b, 0x01, 0xbe, 0x44, 0x31, 0xca, 0x4b, 0xa9, 0x30, 0x99, 0xf1, 0x58,
+	0xcb, 0x78, 0x76, 0x60, 0xd5, 0x6c, 0xe4, 0x24, 0xd1, 0x16, 0x48, 0xb7, 0x41, 0x74, 0xcf, 0x86,
+	0x54, 0x83, 0x9a, 0x39, 0x6c, 0x65, 0x32, 0x9b, 0x48, 0xef, 0x9f, 0x05, 0xf6, 0x77, 0x1e, 0xa0,
+	0xb3, 0x01, 0x76, 0x18, 0xc5, 0x58, 0x30, 0x74, 0x89, 0x41, 0x2b, 0x60, 0xb4, 0xec, 0x6c, 0x00,
+	0x08, 0xcc, 0xf8, 0x89, 0x31, 0xa1, 0x41, 0x4a, 0xbb, 0x7a, 0x65, 0x4c, 0x46, 0xac, 0x43, 0xeb,
+	0xa7, 0x88, 0x14, 0x92, 0x3d, 0x1d, 0x66, 0x26, 0xb7, 0xb0, 0x67, 0x07, 0x3a, 0x09, 0x0f, 0xa2,
+	0x30, 0xc2, 0xc0, 0x6d, 0x11, 0xa8, 0x3f, 0x34, 0xd5, 0x1e, 0x96, 0xd5, 0x1e, 0x1e, 0x95, 0xd5,
+	0x66, 0x15, 0xb6, 0x66, 0x6b, 0x7b, 0x99, 0xad, 0x5e, 0x1f, 0xec, 0xb1, 0x52, 0x42, 0xdb, 0xbb,
+	0xcf, 0x03, 0xb3, 0xb3, 0x55, 0x66, 0x27, 0x3c, 0x40, 0x6f, 0x1b, 0xda, 0x7b, 0x91, 0xc0, 0x94,
+	0x0a, 0x1b, 0xa5, 0x65, 0xd8, 0x66, 0x66, 0xa2, 0xbf, 0x49, 0xfd, 0x04, 0x8b, 0x8d, 0xd2, 0xd8,
+	0x13, 0x60, 0x33, 0xce, 0x95, 0xf3, 0x1e, 0x20, 0xac, 0x4a, 0x53, 0xf8, 0xb5, 0x66, 0x7c, 0x9e,
+	0x95, 0x8c, 0xd5, 0x30, 0x5a, 0xad, 0x40, 0x99, 0xc7, 0x65, 0x97, 0x81, 0x41, 0x6b, 0xdf, 0x59,
+	0x11, 0xd1, 0x3a, 0x50, 0x08, 0x2e, 0xca, 0x06, 0xa3, 0x89, 0x27, 0x61, 0x55, 0xeb, 0x9c, 0x28,
+	0x2e, 0x2e, 0x69, 0x33, 0x5b, 0xd0, 0x0d, 0xca, 0x05, 0xd7, 0xba, 0x96, 0x6d, 0x16, 0x5c, 0x46,
+	0xaa, 0xb3, 0xdc, 0x40, 0xfa, 0xdb, 0x82, 0xa7, 0x15, 0xeb, 0x37, 0xce, 0xa7, 0x79, 0x76, 0x07,
+	0xde, 0x05, 0xd6, 0xd5, 0xb4, 0x34, 0x97, 0x1a, 0xb0, 0x06, 0x4d, 0x14, 0x82, 0x5a, 0xa5, 0xcb,
+	0xf4, 0xd0, 0xfb, 0x05, 0xcf, 0x2b, 0x19, 0x0c, 0xfd, 0x60, 0x2f, 0x12, 0xe3, 0x38, 0xbe, 0x83,
+	0x94, 0xd7, 0x35, 0x0b, 0x74, 0x97, 0xac, 0x18, 0x98, 0xa9, 0xfc, 0x0d, 0x26, 0xe4, 0x35, 0x0f,
+	0x76, 0x05, 0xfa, 0x0a, 0x1f, 0xee, 0xfd, 0x2d, 0x0a, 0x7e, 0x50, 0x2b, 0xf8, 0x41, 0x86, 0xe9,
+	0x1d, 0x48, 0xab, 0x84, 0x8d, 0x7a, 0xc2, 0x1f, 0x00, 0x1a, 0x38, 0x9e, 0x4c, 0x50, 0x4a, 0x67,
+	0x13, 0xec, 0xaa, 0xd9, 0xe7, 0x13, 0xd9, 0x65, 0xdf, 0x27, 0xbe, 0x9c, 0xba, 0x8d, 0xe2, 0xac,
+	0xf8, 0x72, 0xba, 0x44, 0xa8, 0x82, 0x27, 0x95, 0xd0, 0xfd, 0x69, 0xca, 0x83, 0x47, 0xb1, 0x67,
+	0x9e, 0x35, 0x88, 0xc4, 0xa3, 0xb0, 0x06, 0xd0, 0xd1, 0x27, 0x9b, 0x0e, 0xe0, 0xe6, 0xdc, 0x3d,
+	0x39, 0xe7, 0xa0, 0x5e, 0x7f, 0xc0, 0xb1, 0xfb, 0x64, 0x58, 0x74, 0xa7, 0xdf, 0xc8, 0xb2, 0xb8,
+	0xd6, 0x45, 0x06, 0xea, 0x9b, 0xfb, 0x65, 0x18, 0x43, 0x57, 0x67, 0x38, 0xa6, 0xeb, 0xfb, 0x7e,
+	0x29, 0x3e, 0x9b, 0x97, 0x8b, 0x61, 0xc2, 0x2f, 0xee, 0x9b, 0xe3, 0x1d, 0xf4, 0xf4, 0x26, 0x48,
+	0x86, 0x90, 0xb5, 0x24, 0xcd, 0x45, 0x49, 0xbc, 0xaf, 0xe5, 0xf3, 0x7f, 0xec, 0xab, 0xc9, 0xf9,
+	0xed, 0x9e, 0xff, 0xc5, 0xc4, 0x7f, 0x2c, 0x58, 0x39, 0xc4, 0x38, 0x3c, 0x42, 0xa9, 0x0e, 0x15,
+	0x66, 0xd5, 0x6d, 0x66, 0xd5, 0x6e, 0xb3, 0xf2, 0xa9, 0x6c, 0x2c, 0x7e, 0x2a, 0x3f, 0x42, 0xa7,
+	0xfc, 0x03, 0x53, 0x5c, 0x77, 0x2f, 0xae, 0xbd, 0x69, 0x7b, 0x05, 0x80, 0x55, 0xd0, 0x99, 0x20,
+	0xbb, 0x2e, 0x68, 0x1b, 0x3a, 0xa5, 0x1e, 0xe7, 0x0d, 0xd8, 0x52, 0x61, 0x56, 0xd8, 0xe0, 0x18,
+	0x1b, 0xea, 0x6a, 0x19, 0xc5, 0x4f, 0xdb, 0x44, 0xf3, 0xe1, 0xff, 0x00, 0x6d, 0xc0, 0x29, 0x0c,
+	0x90, 0x09, 0x00, 0x00,
 }
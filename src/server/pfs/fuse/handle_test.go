@@ -0,0 +1,361 @@
+package fuse
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"bazil.org/fuse"
+	"github.com/pachyderm/pachyderm/src/client"
+	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"go.pedge.io/pb/go/google/protobuf"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+func TestReleaseRemovesHandle(t *testing.T) {
+	f := &file{
+		directory: directory{
+			fs: newFilesystem(nil, nil, nil, nil),
+			Node: Node{
+				File: client.NewFile("repo", "commit", "file"),
+			},
+		},
+	}
+	open := f.newHandle()
+	for i := 0; i < 100; i++ {
+		h := f.newHandle()
+		require.NoError(t, h.Release(context.Background(), &fuse.ReleaseRequest{}))
+	}
+	require.Equal(t, 1, len(f.handles))
+	require.Equal(t, 1, len(f.fs.handles))
+	require.NoError(t, open.Release(context.Background(), &fuse.ReleaseRequest{}))
+	require.Equal(t, 0, len(f.handles))
+	require.Equal(t, 0, len(f.fs.handles))
+}
+
+type nopWriteCloser struct {
+	bytes.Buffer
+}
+
+func (w *nopWriteCloser) Close() error {
+	return nil
+}
+
+func TestOpenWriters(t *testing.T) {
+	f := &file{
+		directory: directory{
+			fs: newFilesystem(nil, nil, nil, nil),
+			Node: Node{
+				File: client.NewFile("repo", "commit", "file"),
+			},
+		},
+	}
+	h := f.newHandle()
+	f.newHandle()
+	require.Equal(t, 0, len(f.fs.openWriters()))
+	h.w = &nopWriteCloser{}
+	require.Equal(t, []*Node{&f.Node}, f.fs.openWriters())
+	require.NoError(t, h.Release(context.Background(), &fuse.ReleaseRequest{}))
+	require.Equal(t, 0, len(f.fs.openWriters()))
+}
+
+// chunkRecorder records the size of each Write.
+type chunkRecorder struct {
+	bytes.Buffer
+	sizes []int
+}
+
+func (c *chunkRecorder) Write(p []byte) (int, error) {
+	c.sizes = append(c.sizes, len(p))
+	return c.Buffer.Write(p)
+}
+
+func TestWriteChunks(t *testing.T) {
+	var w chunkRecorder
+	written, err := writeChunks(&w, []byte("foobarbaz!"), 3)
+	require.NoError(t, err)
+	require.Equal(t, 10, written)
+	require.Equal(t, "foobarbaz!", w.String())
+	require.Equal(t, []int{3, 3, 3, 1}, w.sizes)
+}
+
+// brokenWriteCloser fails every Write, like a PutFileWriter whose stream has
+// broken.
+type brokenWriteCloser struct {
+	err error
+}
+
+func (w *brokenWriteCloser) Write(p []byte) (int, error) {
+	return 0, w.err
+}
+
+func (w *brokenWriteCloser) Close() error {
+	return nil
+}
+
+func TestWriteErrorIsSticky(t *testing.T) {
+	f := &file{
+		directory: directory{
+			fs: newFilesystem(nil, nil, nil, nil),
+			Node: Node{
+				File:  client.NewFile("repo", "commit", "file"),
+				Write: true,
+			},
+		},
+	}
+	h := f.newHandle()
+	errBroken := errors.New("broken")
+	h.w = &brokenWriteCloser{errBroken}
+	require.Equal(t, errBroken, h.Write(
+		context.Background(),
+		&fuse.WriteRequest{Data: []byte("foo")},
+		&fuse.WriteResponse{},
+	))
+	// later calls fail too, even though a new writer would work
+	h.w = &nopWriteCloser{}
+	require.Equal(t, errBroken, h.Write(
+		context.Background(),
+		&fuse.WriteRequest{Data: []byte("foo")},
+		&fuse.WriteResponse{},
+	))
+	require.Equal(t, errBroken, h.Flush(context.Background(), &fuse.FlushRequest{}))
+	require.Equal(t, errBroken, h.Release(context.Background(), &fuse.ReleaseRequest{}))
+	require.Equal(t, 0, len(f.fs.openWriters()))
+}
+
+func TestConcurrentAppends(t *testing.T) {
+	fs := newFilesystem(nil, nil, nil, &Options{DryRun: true})
+	newFile := func() *file {
+		return &file{
+			directory: directory{
+				fs: fs,
+				Node: Node{
+					File:  client.NewFile("repo", "commit", "file"),
+					Write: true,
+				},
+			},
+		}
+	}
+	// two processes opening the file get their own nodes and handles
+	h1 := newFile().newAppendHandle()
+	h2 := newFile().newAppendHandle()
+	for _, write := range []struct {
+		h      *handle
+		data   string
+		offset int64
+	}{
+		{h1, "foo", 0},
+		// the kernel hasn't seen h1's write yet
+		{h2, "bar", 0},
+		{h1, "baz", 3},
+	} {
+		response := &fuse.WriteResponse{}
+		require.NoError(t, write.h.Write(
+			context.Background(),
+			&fuse.WriteRequest{Data: []byte(write.data), Offset: write.offset},
+			response,
+		))
+		require.Equal(t, len(write.data), response.Size)
+	}
+	require.Equal(t, uint64(9), h1.f.writtenSize())
+	require.NoError(t, h2.Release(context.Background(), &fuse.ReleaseRequest{}))
+	require.NoError(t, h1.Release(context.Background(), &fuse.ReleaseRequest{}))
+	require.Equal(t, "foobarbaz", string(fs.preview.read(client.NewFile("repo", "commit", "file"), 0, 100)))
+	require.Equal(t, 0, len(fs.appends))
+	require.Equal(t, 0, len(fs.openWriters()))
+}
+
+func TestOverwrite(t *testing.T) {
+	fs := newFilesystem(nil, nil, nil, &Options{DryRun: true})
+	newHandle := func(path string) (*handle, func(data []byte, offset int64)) {
+		f := &file{
+			directory: directory{
+				fs: fs,
+				Node: Node{
+					File:  client.NewFile("repo", "commit", path),
+					Write: true,
+				},
+			},
+		}
+		h := f.newHandle()
+		return h, func(data []byte, offset int64) {
+			response := &fuse.WriteResponse{}
+			require.NoError(t, h.Write(
+				context.Background(),
+				&fuse.WriteRequest{Data: data, Offset: offset},
+				response,
+			))
+			require.Equal(t, len(data), response.Size)
+		}
+	}
+	read := func(path string) []byte {
+		return fs.preview.read(client.NewFile("repo", "commit", path), 0, 20000)
+	}
+
+	h, write := newHandle("file")
+	first := bytes.Repeat([]byte("a"), 4096)
+	second := bytes.Repeat([]byte("b"), 4096)
+	write(first, 0)
+	write(second, 4096)
+	// repeating what was just written is skipped
+	write(second[2048:], 6144)
+	// a header rewritten after the body
+	write(bytes.Repeat([]byte("c"), 4096), 0)
+	write([]byte("d"), 8192)
+	write([]byte("e"), 10000)
+	write([]byte("f"), 8192)
+	require.NoError(t, h.Flush(context.Background(), &fuse.FlushRequest{}))
+	expected := append(bytes.Repeat([]byte("c"), 4096), second...)
+	expected = append(expected, make([]byte, 10001-len(expected))...)
+	expected[8192] = 'f'
+	expected[10000] = 'e'
+	require.Equal(t, expected, read("file"))
+	// the handle keeps working after it's put the whole file
+	write([]byte("g"), 0)
+	require.NoError(t, h.Release(context.Background(), &fuse.ReleaseRequest{}))
+	expected[0] = 'g'
+	require.Equal(t, expected, read("file"))
+
+	// a write before where a spill started
+	h, write = newHandle("other")
+	write([]byte("foo"), 0)
+	write([]byte("baz"), 6)
+	write([]byte("bar"), 3)
+	write([]byte("F"), 0)
+	require.NoError(t, h.Release(context.Background(), &fuse.ReleaseRequest{}))
+	require.Equal(t, "Foobarbaz", string(read("other")))
+}
+
+func TestWrittenSizeAfterRelease(t *testing.T) {
+	f := &file{
+		directory: directory{
+			fs: newFilesystem(nil, nil, nil, &Options{DryRun: true}),
+			Node: Node{
+				File:  client.NewFile("repo", "commit", "file"),
+				Write: true,
+			},
+		},
+	}
+	h := f.newHandle()
+	require.NoError(t, h.Write(
+		context.Background(),
+		&fuse.WriteRequest{Data: []byte("foo")},
+		&fuse.WriteResponse{},
+	))
+	require.Equal(t, uint64(3), f.writtenSize())
+	require.NoError(t, h.Release(context.Background(), &fuse.ReleaseRequest{}))
+	// the commit is still open, so this is all Attr has to go on
+	require.Equal(t, uint64(3), f.writtenSize())
+}
+
+func TestFinishFileUnderCommitMountPath(t *testing.T) {
+	fs := newFilesystem(
+		nil,
+		nil,
+		[]*CommitMount{{Commit: client.NewCommit("repo", "commit"), Path: "dir"}},
+		&Options{DryRun: true},
+	)
+	newFile := func(path string) *file {
+		return &file{
+			directory: directory{
+				fs: fs,
+				Node: Node{
+					File:  client.NewFile("repo", "commit", path),
+					Write: true,
+				},
+			},
+		}
+	}
+	require.False(t, newFile(".finish").isFinishFile())
+	require.True(t, newFile("dir/.finish").isFinishFile())
+
+	h := newFile("dir/greeting").newHandle()
+	require.NoError(t, h.Write(
+		context.Background(),
+		&fuse.WriteRequest{Data: []byte("hello")},
+		&fuse.WriteResponse{},
+	))
+	require.Equal(t, 1, len(fs.openWriters()))
+	require.NoError(t, newFile("dir/.finish").newHandle().Write(
+		context.Background(),
+		&fuse.WriteRequest{Data: []byte("\n")},
+		&fuse.WriteResponse{},
+	))
+	require.Equal(t, 0, len(fs.openWriters()))
+}
+
+// contentClient is a pfs client with a single file, content, which it
+// inspects as fileInfo.
+type contentClient struct {
+	inspectClient
+	content []byte
+}
+
+func (c *contentClient) GetFile(ctx context.Context, request *pfsclient.GetFileRequest, opts ...grpc.CallOption) (pfsclient.API_GetFileClient, error) {
+	content := c.content
+	if request.OffsetBytes > int64(len(content)) {
+		content = nil
+	} else {
+		content = content[request.OffsetBytes:]
+	}
+	if request.SizeBytes != 0 && request.SizeBytes < int64(len(content)) {
+		content = content[:request.SizeBytes]
+	}
+	return &bytesClient{values: [][]byte{content}}, nil
+}
+
+type bytesClient struct {
+	grpc.ClientStream
+	values [][]byte
+}
+
+func (c *bytesClient) Recv() (*google_protobuf.BytesValue, error) {
+	if len(c.values) == 0 {
+		return nil, io.EOF
+	}
+	value := c.values[0]
+	c.values = c.values[1:]
+	return &google_protobuf.BytesValue{Value: value}, nil
+}
+
+func TestReadAfterCommitFinished(t *testing.T) {
+	fs := newFilesystem(&contentClient{
+		inspectClient: inspectClient{fileInfo: &pfsclient.FileInfo{SizeBytes: 3}},
+		content:       []byte("foo"),
+	}, nil, nil, nil)
+	newFile := func() *file {
+		// looked up while the commit was open, so it has no size yet
+		return &file{
+			directory: directory{
+				fs: fs,
+				Node: Node{
+					File:  client.NewFile("repo", "commit", "file"),
+					Write: true,
+				},
+			},
+			mode: 0644,
+		}
+	}
+	fs.finished[commitKey(client.NewCommit("repo", "commit"))] = true
+	read := func(h *handle) []byte {
+		response := &fuse.ReadResponse{}
+		require.NoError(t, h.Read(context.Background(), &fuse.ReadRequest{Size: 4096}, response))
+		return response.Data
+	}
+
+	// Open inspects the file again now that its commit is finished
+	f := newFile()
+	h, err := f.Open(context.Background(), &fuse.OpenRequest{}, &fuse.OpenResponse{})
+	require.NoError(t, err)
+	size, ok := f.finishedSize()
+	require.True(t, ok)
+	require.Equal(t, int64(3), size)
+	require.Equal(t, "foo", string(read(h.(*handle))))
+
+	// without a finished size reads aren't clamped, GetFile reads short
+	f = newFile()
+	require.Equal(t, "foo", string(read(f.newHandle())))
+}
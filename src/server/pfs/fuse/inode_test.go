@@ -0,0 +1,74 @@
+package fuse
+
+import (
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestInodeForget(t *testing.T) {
+	fs := newFilesystem(nil, nil, nil, nil)
+	newNode := func(path string) *directory {
+		return &directory{
+			fs: fs,
+			Node: Node{
+				File: client.NewFile("repo", "commit", path),
+			},
+		}
+	}
+	foo1, foo2, bar := newNode("foo"), newNode("foo"), newNode("bar")
+	inode := foo1.inode()
+	require.Equal(t, inode, foo1.inode())
+	require.Equal(t, inode, foo2.inode())
+	require.NotEqual(t, inode, bar.inode())
+	require.Equal(t, 2, len(fs.inodes))
+
+	// foo2 still holds foo's number
+	foo1.Forget()
+	foo1.Forget()
+	require.Equal(t, inode, newNode("foo").inode())
+	foo2.Forget()
+	bar.Forget()
+	require.Equal(t, 1, len(fs.inodes))
+}
+
+func TestKnownInode(t *testing.T) {
+	fs := newFilesystem(nil, nil, nil, nil)
+	d := &directory{
+		fs: fs,
+		Node: Node{
+			File: client.NewFile("repo", "commit", "foo"),
+		},
+	}
+	require.Equal(t, uint64(0), fs.knownInode(d.File))
+	require.Equal(t, 0, len(fs.inodes))
+	inode := d.inode()
+	require.Equal(t, inode, fs.knownInode(client.NewFile("repo", "commit", "foo")))
+}
+
+func TestForgetAfterFileChanges(t *testing.T) {
+	fs := newFilesystem(nil, nil, nil, nil)
+	// a repo directory whose commit is filled in by ReadDirAll, after the
+	// kernel got its inode
+	d := &directory{
+		fs: fs,
+		Node: Node{
+			File: client.NewFile("repo", "", ""),
+		},
+	}
+	inode := d.inode()
+	d.File.Commit.ID = "commit"
+	d.File.Path = "dir"
+	other := &directory{
+		fs: fs,
+		Node: Node{
+			File: client.NewFile("repo", "commit", "dir"),
+		},
+	}
+	otherInode := other.inode()
+	d.Forget()
+	require.Equal(t, uint64(0), fs.knownInode(client.NewFile("repo", "", "")))
+	require.Equal(t, otherInode, fs.knownInode(other.File))
+	require.NotEqual(t, inode, otherInode)
+}
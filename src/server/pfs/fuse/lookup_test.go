@@ -0,0 +1,112 @@
+package fuse
+
+import (
+	"errors"
+	"testing"
+
+	"bazil.org/fuse"
+	"github.com/pachyderm/pachyderm/src/client"
+	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func TestLookUpDots(t *testing.T) {
+	fs := newFilesystem(nil, nil, nil, nil)
+	root, err := fs.Root()
+	require.NoError(t, err)
+	rootDir := root.(*directory)
+	repo := rootDir.copy()
+	repo.File.Commit.Repo.Name = "repo"
+	commit := repo.copy()
+	commit.File.Commit.ID = "commit"
+	dir := commit.copy()
+	dir.File.Path = "dir"
+
+	for _, lookup := range []struct {
+		d        *directory
+		name     string
+		expected *directory
+	}{
+		{dir, ".", dir},
+		{dir, "..", commit},
+		{commit, "..", repo},
+		{repo, "..", rootDir},
+		{rootDir, "..", rootDir},
+		{rootDir, ".", rootDir},
+	} {
+		result, err := lookup.d.Lookup(context.Background(), lookup.name)
+		require.NoError(t, err)
+		require.True(t, result == lookup.expected)
+	}
+}
+
+func TestLookUpMissAliases(t *testing.T) {
+	fs := newFilesystem(&inspectClient{fileInfo: &pfsclient.FileInfo{
+		File:     client.NewFile("repo", "commit", "file"),
+		FileType: pfsclient.FileType_FILE_TYPE_REGULAR,
+	}}, nil, nil, nil)
+	// the file isn't in the shard "prev" is mounted with
+	fs.misses.set(aliasKey(client.NewFile("repo", "commit", "file"), "prev"), struct{}{})
+	prev := &directory{fs: fs, Node: Node{File: client.NewFile("repo", "commit", ""), RepoAlias: "prev"}}
+	_, err := prev.Lookup(context.Background(), "file")
+	require.YesError(t, err)
+	in := &directory{fs: fs, Node: Node{File: client.NewFile("repo", "commit", ""), RepoAlias: "in"}}
+	_, err = in.Lookup(context.Background(), "file")
+	require.NoError(t, err)
+}
+
+// inspectErrClient is a pfs client whose every InspectFile fails with err.
+type inspectErrClient struct {
+	pfsclient.APIClient
+	err error
+}
+
+func (c *inspectErrClient) InspectFile(ctx context.Context, request *pfsclient.InspectFileRequest, opts ...grpc.CallOption) (*pfsclient.FileInfo, error) {
+	return nil, c.err
+}
+
+func TestCreateExclusiveInspectError(t *testing.T) {
+	create := func(apiClient pfsclient.APIClient) error {
+		d := &directory{
+			fs: newFilesystem(apiClient, nil, nil, nil),
+			Node: Node{
+				File:  client.NewFile("repo", "commit", ""),
+				Write: true,
+			},
+		}
+		_, _, err := d.Create(
+			context.Background(),
+			&fuse.CreateRequest{Name: "file", Flags: fuse.OpenExclusive},
+			&fuse.CreateResponse{},
+		)
+		return err
+	}
+	require.NoError(t, create(&inspectErrClient{err: grpc.Errorf(codes.NotFound, "file not found")}))
+	require.Equal(t, fuse.EEXIST, create(&inspectClient{fileInfo: &pfsclient.FileInfo{}}))
+	// we can't tell whether it exists
+	require.Equal(t, fuse.EIO, create(&inspectErrClient{err: errors.New("bad connection")}))
+}
+
+func TestLookUpCachesOnlyNotFound(t *testing.T) {
+	missKey := aliasKey(client.NewFile("repo", "commit", "file"), "")
+	lookUp := func(err error) (*filesystem, error) {
+		fs := newFilesystem(&inspectErrClient{err: err}, nil, nil, nil)
+		d := &directory{fs: fs, Node: Node{File: client.NewFile("repo", "commit", "")}}
+		_, err = d.Lookup(context.Background(), "file")
+		return fs, err
+	}
+
+	fs, err := lookUp(errors.New("bad connection"))
+	require.YesError(t, err)
+	require.True(t, err != fuse.ENOENT)
+	_, ok := fs.misses.get(missKey)
+	require.False(t, ok)
+
+	fs, err = lookUp(grpc.Errorf(codes.NotFound, "file not found"))
+	require.Equal(t, fuse.ENOENT, err)
+	_, ok = fs.misses.get(missKey)
+	require.True(t, ok)
+}
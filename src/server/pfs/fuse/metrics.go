@@ -0,0 +1,99 @@
+package fuse
+
+import (
+	"time"
+
+	"bazil.org/fuse"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	opLookup     = "lookup"
+	opReadDirAll = "read_dir_all"
+	opRead       = "read"
+	opWrite      = "write"
+)
+
+// Metrics counts a mount's lookups, directory listings, reads and writes,
+// how long they took, whether they failed and how many bytes were read and
+// written. It's a prometheus.Collector, pass it in Options.Metrics and
+// register it. One Metrics can be shared by several mounts.
+type Metrics struct {
+	ops     *prometheus.CounterVec
+	latency *prometheus.HistogramVec
+	bytes   *prometheus.CounterVec
+}
+
+// NewMetrics creates a new Metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		ops: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "pachyderm",
+				Subsystem: "fuse",
+				Name:      "operations_total",
+				Help:      "Operations served by the mount, result is ok, not_found or error.",
+			},
+			[]string{"op", "result"},
+		),
+		latency: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: "pachyderm",
+				Subsystem: "fuse",
+				Name:      "operation_duration_seconds",
+				Help:      "How long operations served by the mount took.",
+			},
+			[]string{"op"},
+		),
+		bytes: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: "pachyderm",
+				Subsystem: "fuse",
+				Name:      "bytes_total",
+				Help:      "Bytes read and written through the mount.",
+			},
+			[]string{"op"},
+		),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.ops.Describe(ch)
+	m.latency.Describe(ch)
+	m.bytes.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.ops.Collect(ch)
+	m.latency.Collect(ch)
+	m.bytes.Collect(ch)
+}
+
+// observe records an op that started at start, moved n bytes and returned
+// err. m may be nil, in which case nothing is recorded.
+func (m *Metrics) observe(op string, start time.Time, n int, err error) {
+	if m == nil {
+		return
+	}
+	m.latency.WithLabelValues(op).Observe(time.Since(start).Seconds())
+	m.ops.WithLabelValues(op, result(err)).Inc()
+	if n > 0 {
+		m.bytes.WithLabelValues(op).Add(float64(n))
+	}
+}
+
+// result is the result label for an op that returned err, ENOENT is
+// counted on its own since most missed lookups are just a path being
+// checked for.
+func result(err error) string {
+	switch err {
+	case nil:
+		return "ok"
+	case fuse.ENOENT:
+		return "not_found"
+	default:
+		return "error"
+	}
+}
@@ -0,0 +1,58 @@
+package fuse
+
+import (
+	"testing"
+	"time"
+
+	"bazil.org/fuse"
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"golang.org/x/net/context"
+)
+
+func TestMetrics(t *testing.T) {
+	metrics := NewMetrics()
+	fs := newFilesystem(nil, nil, nil, &Options{DryRun: true, Metrics: metrics})
+	f := &file{
+		directory: directory{
+			fs: fs,
+			Node: Node{
+				File:  client.NewFile("repo", "commit", "file"),
+				Write: true,
+			},
+		},
+	}
+	h := f.newHandle()
+	require.NoError(t, h.Write(
+		context.Background(),
+		&fuse.WriteRequest{Data: []byte("foo")},
+		&fuse.WriteResponse{},
+	))
+	require.NoError(t, h.Release(context.Background(), &fuse.ReleaseRequest{}))
+	h = f.newHandle()
+	response := &fuse.ReadResponse{}
+	require.NoError(t, h.Read(context.Background(), &fuse.ReadRequest{Size: 100}, response))
+	require.Equal(t, "foo", string(response.Data))
+
+	require.Equal(t, float64(3), counterValue(t, metrics.bytes.WithLabelValues(opWrite)))
+	require.Equal(t, float64(3), counterValue(t, metrics.bytes.WithLabelValues(opRead)))
+	require.Equal(t, float64(1), counterValue(t, metrics.ops.WithLabelValues(opWrite, "ok")))
+	require.Equal(t, float64(1), counterValue(t, metrics.ops.WithLabelValues(opRead, "ok")))
+
+	metrics.observe(opLookup, time.Now(), 0, fuse.ENOENT)
+	metrics.observe(opLookup, time.Now(), 0, fuse.EIO)
+	require.Equal(t, float64(1), counterValue(t, metrics.ops.WithLabelValues(opLookup, "not_found")))
+	require.Equal(t, float64(1), counterValue(t, metrics.ops.WithLabelValues(opLookup, "error")))
+
+	// mounts without Metrics record nothing
+	var noMetrics *Metrics
+	noMetrics.observe(opRead, time.Now(), 3, nil)
+}
+
+func counterValue(t *testing.T, counter prometheus.Counter) float64 {
+	metric := &dto.Metric{}
+	require.NoError(t, counter.Write(metric))
+	return metric.Counter.GetValue()
+}
@@ -0,0 +1,53 @@
+package fuse
+
+import (
+	"os"
+	"sync"
+	"testing"
+
+	"bazil.org/fuse"
+	"github.com/pachyderm/pachyderm/src/client"
+	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"golang.org/x/net/context"
+)
+
+func TestModes(t *testing.T) {
+	fs := newFilesystem(nil, nil, nil, nil)
+	require.Equal(t, os.FileMode(0666), fs.fileMode(true))
+	require.Equal(t, os.FileMode(0444), fs.fileMode(false))
+	require.Equal(t, os.FileMode(0775), fs.dirMode(true))
+	require.Equal(t, os.FileMode(0555), fs.dirMode(false))
+
+	fs = newFilesystem(nil, nil, nil, &Options{FileMode: 0660, DirMode: 0770})
+	require.Equal(t, os.FileMode(0660), fs.fileMode(true))
+	require.Equal(t, os.FileMode(0440), fs.fileMode(false))
+	require.Equal(t, os.FileMode(0770), fs.dirMode(true))
+	require.Equal(t, os.FileMode(0550), fs.dirMode(false))
+}
+
+func TestModeCache(t *testing.T) {
+	// inspectClient has no GetFile, sniffing the mode would panic
+	fs := newFilesystem(&inspectClient{fileInfo: &pfsclient.FileInfo{SizeBytes: 3}}, nil, nil, nil)
+	fs.modes.set(aliasKey(client.NewFile("repo", "commit", "script"), ""), os.FileMode(0555))
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		// the kernel has a node per lookup and stats them concurrently
+		f := &file{
+			directory: directory{
+				fs:   fs,
+				Node: Node{File: client.NewFile("repo", "commit", "script")},
+			},
+		}
+		for j := 0; j < 2; j++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				a := &fuse.Attr{}
+				require.NoError(t, f.Attr(context.Background(), a))
+				require.Equal(t, os.FileMode(0555), a.Mode)
+			}()
+		}
+	}
+	wg.Wait()
+}
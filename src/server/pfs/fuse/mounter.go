@@ -4,11 +4,13 @@ import (
 	"os"
 	"os/signal"
 	"sync"
+	"syscall"
 
 	"bazil.org/fuse"
 	"bazil.org/fuse/fs"
 	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
 	"go.pedge.io/lion"
+	"go.pedge.io/lion/proto"
 )
 
 const (
@@ -32,18 +34,20 @@ func (m *mounter) MountAndCreate(
 	mountPoint string,
 	shard *pfsclient.Shard,
 	commitMounts []*CommitMount,
+	opts *Options,
 	ready chan bool,
 ) error {
 	if err := os.MkdirAll(mountPoint, 0777); err != nil {
 		return err
 	}
-	return m.Mount(mountPoint, shard, commitMounts, ready)
+	return m.Mount(mountPoint, shard, commitMounts, opts, ready)
 }
 
 func (m *mounter) Mount(
 	mountPoint string,
 	shard *pfsclient.Shard,
 	commitMounts []*CommitMount,
+	opts *Options,
 	ready chan bool,
 ) (retErr error) {
 	var once sync.Once
@@ -83,8 +87,40 @@ func (m *mounter) Mount(
 			close(ready)
 		}
 	})
+	filesystem := newFilesystem(m.apiClient, shard, commitMounts, opts)
+	// SIGUSR1 logs the files that are still being written, which helps
+	// track down writers that never close their files, and a SelfTest, so
+	// a failing mount can be told apart from a failing pfs.
+	usr1Chan := make(chan os.Signal, 1)
+	signal.Notify(usr1Chan, syscall.SIGUSR1)
+	defer func() {
+		signal.Stop(usr1Chan)
+		close(usr1Chan)
+	}()
+	go func() {
+		for range usr1Chan {
+			protolion.Info(&OpenWriters{filesystem.openWriters()})
+			protolion.Info(filesystem.SelfTest(filesystem.ctx))
+		}
+	}()
+	// SIGUSR2 drops the mount's caches, so that changes made in pfs show up
+	// without remounting.
+	usr2Chan := make(chan os.Signal, 1)
+	signal.Notify(usr2Chan, syscall.SIGUSR2)
+	defer func() {
+		signal.Stop(usr2Chan)
+		close(usr2Chan)
+	}()
+	go func() {
+		for range usr2Chan {
+			filesystem.invalidateCache("", "")
+		}
+	}()
+	// stops the commit watchers
+	defer filesystem.cancel()
 	config := &fs.Config{}
-	if err := fs.New(conn, config).Serve(newFilesystem(m.apiClient, shard, commitMounts)); err != nil {
+	filesystem.server = fs.New(conn, config)
+	if err := filesystem.server.Serve(filesystem); err != nil {
 		return err
 	}
 	<-conn.Ready
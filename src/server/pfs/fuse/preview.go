@@ -0,0 +1,133 @@
+package fuse
+
+import (
+	"bytes"
+	"path"
+	"sort"
+	"sync"
+
+	"bazil.org/fuse"
+	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
+)
+
+// preview holds what a DryRun mount would have put in pfs, so that it can be
+// read back through the mount. Entries are keyed by key(file).
+type preview struct {
+	entries map[string]*previewEntry
+	lock    sync.Mutex
+}
+
+type previewEntry struct {
+	file *pfsclient.File
+	dir  bool
+	data bytes.Buffer
+}
+
+func newPreview() *preview {
+	return &preview{
+		entries: make(map[string]*previewEntry),
+	}
+}
+
+// writer returns a writer that appends to file, like PutFileWriter.
+func (p *preview) writer(file *pfsclient.File) *previewWriter {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.entry(file)
+	return &previewWriter{p, file}
+}
+
+func (p *preview) mkdir(file *pfsclient.File) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.entry(file).dir = true
+}
+
+func (p *preview) remove(file *pfsclient.File) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	delete(p.entries, key(file))
+}
+
+func (p *preview) exists(file *pfsclient.File) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	_, ok := p.entries[key(file)]
+	return ok
+}
+
+// size returns the number of bytes written to file.
+func (p *preview) size(file *pfsclient.File) int64 {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if entry, ok := p.entries[key(file)]; ok {
+		return int64(entry.data.Len())
+	}
+	return 0
+}
+
+// read returns up to size bytes of file from offset.
+func (p *preview) read(file *pfsclient.File, offset int64, size int64) []byte {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	entry, ok := p.entries[key(file)]
+	if !ok || offset >= int64(entry.data.Len()) {
+		return nil
+	}
+	data := entry.data.Bytes()[offset:]
+	if int64(len(data)) > size {
+		data = data[:size]
+	}
+	return append([]byte(nil), data...)
+}
+
+// dirents returns the entries directly under dir, sorted by name.
+func (p *preview) dirents(dir *pfsclient.File) []fuse.Dirent {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	var result []fuse.Dirent
+	for _, entry := range p.entries {
+		if entry.file.Commit.Repo.Name != dir.Commit.Repo.Name ||
+			entry.file.Commit.ID != dir.Commit.ID ||
+			path.Dir(path.Clean(entry.file.Path)) != path.Clean(dir.Path) {
+			continue
+		}
+		dirent := fuse.Dirent{Name: path.Base(entry.file.Path), Type: fuse.DT_File}
+		if entry.dir {
+			dirent.Type = fuse.DT_Dir
+		}
+		result = append(result, dirent)
+	}
+	sort.Sort(direntsByName(result))
+	return result
+}
+
+func (p *preview) entry(file *pfsclient.File) *previewEntry {
+	entry, ok := p.entries[key(file)]
+	if !ok {
+		entry = &previewEntry{file: file}
+		p.entries[key(file)] = entry
+	}
+	return entry
+}
+
+type previewWriter struct {
+	p    *preview
+	file *pfsclient.File
+}
+
+func (w *previewWriter) Write(data []byte) (int, error) {
+	w.p.lock.Lock()
+	defer w.p.lock.Unlock()
+	return w.p.entry(w.file).data.Write(data)
+}
+
+func (w *previewWriter) Close() error {
+	return nil
+}
+
+type direntsByName []fuse.Dirent
+
+func (d direntsByName) Len() int           { return len(d) }
+func (d direntsByName) Less(i, j int) bool { return d[i].Name < d[j].Name }
+func (d direntsByName) Swap(i, j int)      { d[i], d[j] = d[j], d[i] }
@@ -0,0 +1,34 @@
+package fuse
+
+import (
+	"io"
+	"testing"
+
+	"bazil.org/fuse"
+	"github.com/pachyderm/pachyderm/src/client"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+)
+
+func TestPreview(t *testing.T) {
+	p := newPreview()
+	file := client.NewFile("repo", "commit", "dir/file")
+	p.mkdir(client.NewFile("repo", "commit", "dir"))
+	w := p.writer(file)
+	_, err := io.WriteString(w, "foo")
+	require.NoError(t, err)
+	_, err = io.WriteString(w, "bar")
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	require.Equal(t, int64(6), p.size(file))
+	require.Equal(t, "oba", string(p.read(file, 2, 3)))
+	require.Equal(t, "bar", string(p.read(file, 3, 10)))
+	require.Equal(t, 0, len(p.read(file, 6, 10)))
+	require.Equal(t, []fuse.Dirent{{Name: "dir", Type: fuse.DT_Dir}}, p.dirents(client.NewFile("repo", "commit", "")))
+	require.Equal(t, []fuse.Dirent{{Name: "file", Type: fuse.DT_File}}, p.dirents(client.NewFile("repo", "commit", "dir")))
+	require.Equal(t, 0, len(p.dirents(client.NewFile("repo", "other", "dir"))))
+
+	p.remove(file)
+	require.Equal(t, int64(0), p.size(file))
+	require.Equal(t, 0, len(p.dirents(client.NewFile("repo", "commit", "dir"))))
+}
@@ -0,0 +1,74 @@
+package fuse
+
+import (
+	"io/ioutil"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
+	"go.pedge.io/proto/time"
+	"golang.org/x/net/context"
+)
+
+// SelfTest makes the calls the mount makes to serve a read, one after the
+// other and without the caches, and reports how long each took and the
+// first that failed. It lists the repos, the finished commits of the first
+// repo, the files at the root of one of them and reads the first chunk of
+// the first file. If SelfTest passes while the mount is failing the problem
+// is in the mount rather than in pfs. SIGUSR1 logs a SelfTest of a running
+// mount.
+func (f *filesystem) SelfTest(ctx context.Context) *SelfTest {
+	result := &SelfTest{}
+	step := func(name string, file *pfsclient.File, do func() error) bool {
+		start := time.Now()
+		err := do()
+		result.Step = append(result.Step, &SelfTestStep{
+			Name:     name,
+			File:     file,
+			Duration: prototime.DurationToProto(time.Since(start)),
+			Error:    errorToString(err),
+		})
+		return err == nil
+	}
+
+	var repoInfos []*pfsclient.RepoInfo
+	if !step("list_repo", nil, func() (err error) {
+		repoInfos, err = f.listRepo(ctx)
+		return err
+	}) || len(repoInfos) == 0 {
+		return result
+	}
+	repoName := repoInfos[0].Repo.Name
+
+	var commitInfos []*pfsclient.CommitInfo
+	if !step("list_commit", client.NewFile(repoName, "", ""), func() (err error) {
+		commitInfos, err = f.listCommit(ctx, repoName, pfsclient.CommitType_COMMIT_TYPE_READ)
+		return err
+	}) || len(commitInfos) == 0 {
+		return result
+	}
+	commitID := commitInfos[0].Commit.ID
+
+	var fileInfos []*pfsclient.FileInfo
+	if !step("list_file", client.NewFile(repoName, commitID, ""), func() (err error) {
+		fileInfos, err = f.listFile(ctx, repoName, commitID, "", "", nil, false)
+		return err
+	}) {
+		return result
+	}
+	for _, fileInfo := range fileInfos {
+		if fileInfo.FileType != pfsclient.FileType_FILE_TYPE_REGULAR {
+			continue
+		}
+		file := fileInfo.File
+		size := int64(f.maxChunkSize())
+		if size > int64(fileInfo.SizeBytes) {
+			size = int64(fileInfo.SizeBytes)
+		}
+		step("get_file", file, func() error {
+			return f.getFile(ctx, repoName, commitID, file.Path, 0, size, "", nil, ioutil.Discard)
+		})
+		break
+	}
+	return result
+}
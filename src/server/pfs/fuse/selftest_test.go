@@ -0,0 +1,54 @@
+package fuse
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pachyderm/pachyderm/src/client"
+	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// selfTestClient is a pfs client with a repo and a finished commit whose
+// files can't be listed.
+type selfTestClient struct {
+	pfsclient.APIClient
+	repoInfos []*pfsclient.RepoInfo
+}
+
+func (c *selfTestClient) ListRepo(ctx context.Context, request *pfsclient.ListRepoRequest, opts ...grpc.CallOption) (*pfsclient.RepoInfos, error) {
+	return &pfsclient.RepoInfos{RepoInfo: c.repoInfos}, nil
+}
+
+func (c *selfTestClient) ListCommit(ctx context.Context, request *pfsclient.ListCommitRequest, opts ...grpc.CallOption) (*pfsclient.CommitInfos, error) {
+	return &pfsclient.CommitInfos{CommitInfo: []*pfsclient.CommitInfo{
+		{Commit: client.NewCommit(request.Repo[0].Name, "commit")},
+	}}, nil
+}
+
+func (c *selfTestClient) ListFile(ctx context.Context, request *pfsclient.ListFileRequest, opts ...grpc.CallOption) (*pfsclient.FileInfos, error) {
+	return nil, errors.New("commit not found")
+}
+
+func TestSelfTest(t *testing.T) {
+	apiClient := &selfTestClient{}
+	fs := newFilesystem(apiClient, nil, nil, nil)
+	// with no repos there's nothing to test past listing them
+	selfTest := fs.SelfTest(context.Background())
+	require.Equal(t, 1, len(selfTest.Step))
+	require.Equal(t, "list_repo", selfTest.Step[0].Name)
+	require.Equal(t, "", selfTest.Step[0].Error)
+
+	apiClient.repoInfos = []*pfsclient.RepoInfo{{Repo: client.NewRepo("repo")}}
+	selfTest = fs.SelfTest(context.Background())
+	require.Equal(t, 3, len(selfTest.Step))
+	require.Equal(t, "list_commit", selfTest.Step[1].Name)
+	require.Equal(t, "repo", selfTest.Step[1].File.Commit.Repo.Name)
+	require.Equal(t, "", selfTest.Step[1].Error)
+	require.Equal(t, "list_file", selfTest.Step[2].Name)
+	require.Equal(t, "commit", selfTest.Step[2].File.Commit.ID)
+	require.Equal(t, "commit not found", selfTest.Step[2].Error)
+	require.NotNil(t, selfTest.Step[2].Duration)
+}
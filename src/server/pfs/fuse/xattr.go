@@ -0,0 +1,50 @@
+package fuse
+
+import (
+	"time"
+
+	"bazil.org/fuse"
+	"go.pedge.io/proto/time"
+	"golang.org/x/net/context"
+)
+
+// xattrs are the extended attributes exposed on every node, Getxattr and
+// Listxattr both read this so they can't disagree. An empty value means the
+// attribute isn't set on that node.
+var xattrs = []struct {
+	name string
+	get  func(node *Node) string
+}{
+	{"user.pfs.repo", func(node *Node) string { return node.File.Commit.Repo.Name }},
+	{"user.pfs.commit", func(node *Node) string { return node.File.Commit.ID }},
+	{"user.pfs.path", func(node *Node) string { return node.File.Path }},
+	{"user.pfs.modified", func(node *Node) string {
+		if node.Modified == nil {
+			return ""
+		}
+		return prototime.TimestampToTime(node.Modified).UTC().Format(time.RFC3339Nano)
+	}},
+}
+
+func (d *directory) Getxattr(ctx context.Context, request *fuse.GetxattrRequest, response *fuse.GetxattrResponse) error {
+	for _, xattr := range xattrs {
+		if xattr.name == request.Name {
+			value := xattr.get(&d.Node)
+			if value == "" {
+				break
+			}
+			response.Xattr = []byte(value)
+			return nil
+		}
+	}
+	return fuse.ErrNoXattr
+}
+
+func (d *directory) Listxattr(ctx context.Context, request *fuse.ListxattrRequest, response *fuse.ListxattrResponse) error {
+	for _, xattr := range xattrs {
+		if xattr.get(&d.Node) != "" {
+			response.Append(xattr.name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package fuse
+
+import (
+	"testing"
+	"time"
+
+	"bazil.org/fuse"
+	"github.com/pachyderm/pachyderm/src/client"
+	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"go.pedge.io/proto/time"
+	"golang.org/x/net/context"
+)
+
+func TestXattrs(t *testing.T) {
+	d := &directory{
+		Node: Node{
+			File: client.NewFile("repo", "commit", "dir"),
+		},
+	}
+	listResponse := &fuse.ListxattrResponse{}
+	require.NoError(t, d.Listxattr(context.Background(), &fuse.ListxattrRequest{}, listResponse))
+	require.Equal(t, "user.pfs.repo\x00user.pfs.commit\x00user.pfs.path\x00", string(listResponse.Xattr))
+
+	getResponse := &fuse.GetxattrResponse{}
+	require.NoError(t, d.Getxattr(context.Background(), &fuse.GetxattrRequest{Name: "user.pfs.commit"}, getResponse))
+	require.Equal(t, "commit", string(getResponse.Xattr))
+	// modified isn't listed, so it shouldn't be gettable either
+	require.Equal(t, fuse.ErrNoXattr, d.Getxattr(context.Background(), &fuse.GetxattrRequest{Name: "user.pfs.modified"}, getResponse))
+	require.Equal(t, fuse.ErrNoXattr, d.Getxattr(context.Background(), &fuse.GetxattrRequest{Name: "user.foo"}, getResponse))
+}
+
+func TestFileModifiedXattr(t *testing.T) {
+	modified := time.Unix(1000, 0)
+	fs := newFilesystem(&inspectClient{fileInfo: &pfsclient.FileInfo{
+		File:     client.NewFile("repo", "commit", "file"),
+		FileType: pfsclient.FileType_FILE_TYPE_REGULAR,
+		Modified: prototime.TimeToTimestamp(modified),
+	}}, nil, nil, nil)
+	d := &directory{fs: fs, Node: Node{File: client.NewFile("repo", "commit", "")}}
+	f, err := d.Lookup(context.Background(), "file")
+	require.NoError(t, err)
+	getResponse := &fuse.GetxattrResponse{}
+	require.NoError(t, f.(*file).Getxattr(context.Background(), &fuse.GetxattrRequest{Name: "user.pfs.modified"}, getResponse))
+	require.Equal(t, modified.UTC().Format(time.RFC3339Nano), string(getResponse.Xattr))
+}
@@ -406,7 +406,12 @@ func (a *internalAPIServer) InspectFile(ctx context.Context, request *pfs.Inspec
 	if err != nil {
 		return nil, err
 	}
-	return a.driver.InspectFile(request.File, request.Shard, request.FromCommit, shard, request.Unsafe)
+	fileInfo, err := a.driver.InspectFile(request.File, request.Shard, request.FromCommit, shard, request.Unsafe)
+	if err == pfsserver.ErrFileNotFound {
+		// like GetFile, so clients can tell a missing file from a failure
+		return nil, grpcErrorf(codes.NotFound, "%v", err)
+	}
+	return fileInfo, err
 }
 
 func (a *internalAPIServer) ListFile(ctx context.Context, request *pfs.ListFileRequest) (response *pfs.FileInfos, retErr error) {
@@ -11,13 +11,31 @@ It is generated from these files:
 It has these top-level messages:
 	JobInfo
 	JobInfos
+	RecentJobInfosRequest
+	GetJobInfosByCommitRangeRequest
+	WriteSummary
+	ReindexJobInfosResponse
 	JobOutput
 	JobState
+	JobStateTransition
 	PipelineInfo
 	PipelineInfoChange
 	PipelineInfos
+	GetJobInfosRequest
+	GetJobInfosResponse
+	GetPipelineInfosRequest
+	GetPipelineInfosResponse
+	DeleteJobInfosByCommitRequest
+	DeletePipelineAndJobsRequest
+	DeletePipelineAndJobsResponse
+	WaitPipelineDeletedRequest
 	SubscribePipelineInfosRequest
 	ListPipelineInfosRequest
+	ListPipelineNamesRequest
+	PipelineNames
+	JobStateCounts
+	PodCounters
+	PingResponse
 	Shard
 */
 package persist
@@ -27,6 +45,7 @@ import fmt "fmt"
 import math "math"
 import google_protobuf "go.pedge.io/pb/go/google/protobuf"
 import google_protobuf1 "go.pedge.io/pb/go/google/protobuf"
+import google_protobuf2 "go.pedge.io/pb/go/google/protobuf"
 import pfs "github.com/pachyderm/pachyderm/src/client/pfs"
 import pachyderm_pps "github.com/pachyderm/pachyderm/src/client/pps"
 
@@ -44,6 +63,30 @@ var _ = math.Inf
 // is compatible with the proto package it is being compiled against.
 const _ = proto.ProtoPackageIsVersion1
 
+type PipelineInfosOrder int32
+
+const (
+	PipelineInfosOrder_PIPELINE_INFOS_ORDER_NONE            PipelineInfosOrder = 0
+	PipelineInfosOrder_PIPELINE_INFOS_ORDER_CREATED_AT_ASC  PipelineInfosOrder = 1
+	PipelineInfosOrder_PIPELINE_INFOS_ORDER_CREATED_AT_DESC PipelineInfosOrder = 2
+)
+
+var PipelineInfosOrder_name = map[int32]string{
+	0: "PIPELINE_INFOS_ORDER_NONE",
+	1: "PIPELINE_INFOS_ORDER_CREATED_AT_ASC",
+	2: "PIPELINE_INFOS_ORDER_CREATED_AT_DESC",
+}
+var PipelineInfosOrder_value = map[string]int32{
+	"PIPELINE_INFOS_ORDER_NONE":            0,
+	"PIPELINE_INFOS_ORDER_CREATED_AT_ASC":  1,
+	"PIPELINE_INFOS_ORDER_CREATED_AT_DESC": 2,
+}
+
+func (x PipelineInfosOrder) String() string {
+	return proto.EnumName(PipelineInfosOrder_name, int32(x))
+}
+func (PipelineInfosOrder) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
 type JobInfo struct {
 	JobID         string                      `protobuf:"bytes,1,opt,name=job_id,json=jobId" json:"job_id,omitempty"`
 	Transform     *pachyderm_pps.Transform    `protobuf:"bytes,2,opt,name=transform" json:"transform,omitempty"`
@@ -51,7 +94,7 @@ type JobInfo struct {
 	Parallelism   uint64                      `protobuf:"varint,4,opt,name=parallelism" json:"parallelism,omitempty"`
 	Inputs        []*pachyderm_pps.JobInput   `protobuf:"bytes,5,rep,name=inputs" json:"inputs,omitempty"`
 	ParentJob     *pachyderm_pps.Job          `protobuf:"bytes,6,opt,name=parent_job,json=parentJob" json:"parent_job,omitempty"`
-	CreatedAt     *google_protobuf1.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt" json:"created_at,omitempty"`
+	CreatedAt     *google_protobuf2.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt" json:"created_at,omitempty"`
 	OutputCommit  *pfs.Commit                 `protobuf:"bytes,8,opt,name=output_commit,json=outputCommit" json:"output_commit,omitempty"`
 	State         pachyderm_pps.JobState      `protobuf:"varint,9,opt,name=state,enum=pachyderm.pps.JobState" json:"state,omitempty"`
 	CommitIndex   string                      `protobuf:"bytes,10,opt,name=commit_index,json=commitIndex" json:"commit_index,omitempty"`
@@ -59,6 +102,16 @@ type JobInfo struct {
 	PodsSucceeded uint64                      `protobuf:"varint,12,opt,name=pods_succeeded,json=podsSucceeded" json:"pods_succeeded,omitempty"`
 	PodsFailed    uint64                      `protobuf:"varint,13,opt,name=pods_failed,json=podsFailed" json:"pods_failed,omitempty"`
 	ShardModuli   []uint64                    `protobuf:"varint,14,rep,name=shard_moduli,json=shardModuli" json:"shard_moduli,omitempty"`
+	UpdatedAt     *google_protobuf2.Timestamp `protobuf:"bytes,15,opt,name=updated_at,json=updatedAt" json:"updated_at,omitempty"`
+	// the version of the pipeline's spec the job was created from, 0 if it
+	// isn't known
+	PipelineVersion uint64 `protobuf:"varint,16,opt,name=pipeline_version,json=pipelineVersion" json:"pipeline_version,omitempty"`
+	// how many times RestartJob has been called on the job
+	Restarts uint64 `protobuf:"varint,17,opt,name=restarts" json:"restarts,omitempty"`
+	// if set when the job is created its commit_index is left empty, even if
+	// it has inputs, so listing and deleting jobs by input commit never
+	// matches it. ReindexJobInfos leaves it empty too.
+	SkipCommitIndex bool `protobuf:"varint,18,opt,name=skip_commit_index,json=skipCommitIndex" json:"skip_commit_index,omitempty"`
 }
 
 func (m *JobInfo) Reset()                    { *m = JobInfo{} }
@@ -87,7 +140,7 @@ func (m *JobInfo) GetParentJob() *pachyderm_pps.Job {
 	return nil
 }
 
-func (m *JobInfo) GetCreatedAt() *google_protobuf1.Timestamp {
+func (m *JobInfo) GetCreatedAt() *google_protobuf2.Timestamp {
 	if m != nil {
 		return m.CreatedAt
 	}
@@ -101,8 +154,17 @@ func (m *JobInfo) GetOutputCommit() *pfs.Commit {
 	return nil
 }
 
+func (m *JobInfo) GetUpdatedAt() *google_protobuf2.Timestamp {
+	if m != nil {
+		return m.UpdatedAt
+	}
+	return nil
+}
+
 type JobInfos struct {
 	JobInfo []*JobInfo `protobuf:"bytes,1,rep,name=job_info,json=jobInfo" json:"job_info,omitempty"`
+	// pass as since_token to list only the jobs created or updated after these
+	NextToken string `protobuf:"bytes,2,opt,name=next_token,json=nextToken" json:"next_token,omitempty"`
 }
 
 func (m *JobInfos) Reset()                    { *m = JobInfos{} }
@@ -117,6 +179,58 @@ func (m *JobInfos) GetJobInfo() []*JobInfo {
 	return nil
 }
 
+type RecentJobInfosRequest struct {
+	N uint64 `protobuf:"varint,1,opt,name=n" json:"n,omitempty"`
+}
+
+func (m *RecentJobInfosRequest) Reset()                    { *m = RecentJobInfosRequest{} }
+func (m *RecentJobInfosRequest) String() string            { return proto.CompactTextString(m) }
+func (*RecentJobInfosRequest) ProtoMessage()               {}
+func (*RecentJobInfosRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+// commit indexes are the first 10 characters of each input commit ID, sorted
+// and concatenated, and compare as strings. So a range holds the jobs whose
+// lowest input commit ID falls in it, then by the next lowest and so on.
+// Commit IDs are random so this has nothing to do with when commits were
+// made.
+type GetJobInfosByCommitRangeRequest struct {
+	// inclusive
+	StartCommitIndex string `protobuf:"bytes,1,opt,name=start_commit_index,json=startCommitIndex" json:"start_commit_index,omitempty"`
+	// exclusive, empty means no upper bound
+	EndCommitIndex string `protobuf:"bytes,2,opt,name=end_commit_index,json=endCommitIndex" json:"end_commit_index,omitempty"`
+}
+
+func (m *GetJobInfosByCommitRangeRequest) Reset()         { *m = GetJobInfosByCommitRangeRequest{} }
+func (m *GetJobInfosByCommitRangeRequest) String() string { return proto.CompactTextString(m) }
+func (*GetJobInfosByCommitRangeRequest) ProtoMessage()    {}
+func (*GetJobInfosByCommitRangeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{3}
+}
+
+// what a write changed, unchanged counts documents that were written with
+// the values they already had
+type WriteSummary struct {
+	Inserted  uint64 `protobuf:"varint,1,opt,name=inserted" json:"inserted,omitempty"`
+	Replaced  uint64 `protobuf:"varint,2,opt,name=replaced" json:"replaced,omitempty"`
+	Deleted   uint64 `protobuf:"varint,3,opt,name=deleted" json:"deleted,omitempty"`
+	Unchanged uint64 `protobuf:"varint,4,opt,name=unchanged" json:"unchanged,omitempty"`
+}
+
+func (m *WriteSummary) Reset()                    { *m = WriteSummary{} }
+func (m *WriteSummary) String() string            { return proto.CompactTextString(m) }
+func (*WriteSummary) ProtoMessage()               {}
+func (*WriteSummary) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+type ReindexJobInfosResponse struct {
+	// number of jobs whose commit index changed
+	Updated uint64 `protobuf:"varint,1,opt,name=updated" json:"updated,omitempty"`
+}
+
+func (m *ReindexJobInfosResponse) Reset()                    { *m = ReindexJobInfosResponse{} }
+func (m *ReindexJobInfosResponse) String() string            { return proto.CompactTextString(m) }
+func (*ReindexJobInfosResponse) ProtoMessage()               {}
+func (*ReindexJobInfosResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
 type JobOutput struct {
 	JobID        string      `protobuf:"bytes,1,opt,name=job_id,json=jobId" json:"job_id,omitempty"`
 	OutputCommit *pfs.Commit `protobuf:"bytes,2,opt,name=output_commit,json=outputCommit" json:"output_commit,omitempty"`
@@ -125,7 +239,7 @@ type JobOutput struct {
 func (m *JobOutput) Reset()                    { *m = JobOutput{} }
 func (m *JobOutput) String() string            { return proto.CompactTextString(m) }
 func (*JobOutput) ProtoMessage()               {}
-func (*JobOutput) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+func (*JobOutput) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
 
 func (m *JobOutput) GetOutputCommit() *pfs.Commit {
 	if m != nil {
@@ -142,7 +256,18 @@ type JobState struct {
 func (m *JobState) Reset()                    { *m = JobState{} }
 func (m *JobState) String() string            { return proto.CompactTextString(m) }
 func (*JobState) ProtoMessage()               {}
-func (*JobState) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+func (*JobState) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+type JobStateTransition struct {
+	JobID string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId" json:"job_id,omitempty"`
+	From  pachyderm_pps.JobState `protobuf:"varint,2,opt,name=from,enum=pachyderm.pps.JobState" json:"from,omitempty"`
+	To    pachyderm_pps.JobState `protobuf:"varint,3,opt,name=to,enum=pachyderm.pps.JobState" json:"to,omitempty"`
+}
+
+func (m *JobStateTransition) Reset()                    { *m = JobStateTransition{} }
+func (m *JobStateTransition) String() string            { return proto.CompactTextString(m) }
+func (*JobStateTransition) ProtoMessage()               {}
+func (*JobStateTransition) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
 
 type PipelineInfo struct {
 	PipelineName string                         `protobuf:"bytes,1,opt,name=pipeline_name,json=pipelineName" json:"pipeline_name,omitempty"`
@@ -150,14 +275,17 @@ type PipelineInfo struct {
 	Parallelism  uint64                         `protobuf:"varint,3,opt,name=parallelism" json:"parallelism,omitempty"`
 	Inputs       []*pachyderm_pps.PipelineInput `protobuf:"bytes,4,rep,name=inputs" json:"inputs,omitempty"`
 	OutputRepo   *pfs.Repo                      `protobuf:"bytes,5,opt,name=output_repo,json=outputRepo" json:"output_repo,omitempty"`
-	CreatedAt    *google_protobuf1.Timestamp    `protobuf:"bytes,6,opt,name=created_at,json=createdAt" json:"created_at,omitempty"`
+	CreatedAt    *google_protobuf2.Timestamp    `protobuf:"bytes,6,opt,name=created_at,json=createdAt" json:"created_at,omitempty"`
 	Shard        uint64                         `protobuf:"varint,7,opt,name=shard" json:"shard,omitempty"`
+	// 1 when the pipeline info is created, each update increments it. Pipeline
+	// infos from before versions were added are version 0.
+	Version uint64 `protobuf:"varint,8,opt,name=version" json:"version,omitempty"`
 }
 
 func (m *PipelineInfo) Reset()                    { *m = PipelineInfo{} }
 func (m *PipelineInfo) String() string            { return proto.CompactTextString(m) }
 func (*PipelineInfo) ProtoMessage()               {}
-func (*PipelineInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+func (*PipelineInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
 
 func (m *PipelineInfo) GetTransform() *pachyderm_pps.Transform {
 	if m != nil {
@@ -180,7 +308,7 @@ func (m *PipelineInfo) GetOutputRepo() *pfs.Repo {
 	return nil
 }
 
-func (m *PipelineInfo) GetCreatedAt() *google_protobuf1.Timestamp {
+func (m *PipelineInfo) GetCreatedAt() *google_protobuf2.Timestamp {
 	if m != nil {
 		return m.CreatedAt
 	}
@@ -195,7 +323,7 @@ type PipelineInfoChange struct {
 func (m *PipelineInfoChange) Reset()                    { *m = PipelineInfoChange{} }
 func (m *PipelineInfoChange) String() string            { return proto.CompactTextString(m) }
 func (*PipelineInfoChange) ProtoMessage()               {}
-func (*PipelineInfoChange) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+func (*PipelineInfoChange) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
 
 func (m *PipelineInfoChange) GetPipeline() *PipelineInfo {
 	if m != nil {
@@ -206,12 +334,14 @@ func (m *PipelineInfoChange) GetPipeline() *PipelineInfo {
 
 type PipelineInfos struct {
 	PipelineInfo []*PipelineInfo `protobuf:"bytes,1,rep,name=pipeline_info,json=pipelineInfo" json:"pipeline_info,omitempty"`
+	// pass as page_token to get the next page, empty once there are no more
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken" json:"next_page_token,omitempty"`
 }
 
 func (m *PipelineInfos) Reset()                    { *m = PipelineInfos{} }
 func (m *PipelineInfos) String() string            { return proto.CompactTextString(m) }
 func (*PipelineInfos) ProtoMessage()               {}
-func (*PipelineInfos) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+func (*PipelineInfos) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
 
 func (m *PipelineInfos) GetPipelineInfo() []*PipelineInfo {
 	if m != nil {
@@ -220,15 +350,177 @@ func (m *PipelineInfos) GetPipelineInfo() []*PipelineInfo {
 	return nil
 }
 
+type GetJobInfosRequest struct {
+	Job []*pachyderm_pps.Job `protobuf:"bytes,1,rep,name=job" json:"job,omitempty"`
+}
+
+func (m *GetJobInfosRequest) Reset()                    { *m = GetJobInfosRequest{} }
+func (m *GetJobInfosRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetJobInfosRequest) ProtoMessage()               {}
+func (*GetJobInfosRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+func (m *GetJobInfosRequest) GetJob() []*pachyderm_pps.Job {
+	if m != nil {
+		return m.Job
+	}
+	return nil
+}
+
+type GetJobInfosResponse struct {
+	JobInfo []*JobInfo `protobuf:"bytes,1,rep,name=job_info,json=jobInfo" json:"job_info,omitempty"`
+	// the requested jobs that don't exist
+	Missing []*pachyderm_pps.Job `protobuf:"bytes,2,rep,name=missing" json:"missing,omitempty"`
+}
+
+func (m *GetJobInfosResponse) Reset()                    { *m = GetJobInfosResponse{} }
+func (m *GetJobInfosResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetJobInfosResponse) ProtoMessage()               {}
+func (*GetJobInfosResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+
+func (m *GetJobInfosResponse) GetJobInfo() []*JobInfo {
+	if m != nil {
+		return m.JobInfo
+	}
+	return nil
+}
+
+func (m *GetJobInfosResponse) GetMissing() []*pachyderm_pps.Job {
+	if m != nil {
+		return m.Missing
+	}
+	return nil
+}
+
+type GetPipelineInfosRequest struct {
+	Pipeline []*pachyderm_pps.Pipeline `protobuf:"bytes,1,rep,name=pipeline" json:"pipeline,omitempty"`
+}
+
+func (m *GetPipelineInfosRequest) Reset()                    { *m = GetPipelineInfosRequest{} }
+func (m *GetPipelineInfosRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetPipelineInfosRequest) ProtoMessage()               {}
+func (*GetPipelineInfosRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+
+func (m *GetPipelineInfosRequest) GetPipeline() []*pachyderm_pps.Pipeline {
+	if m != nil {
+		return m.Pipeline
+	}
+	return nil
+}
+
+type GetPipelineInfosResponse struct {
+	PipelineInfo []*PipelineInfo `protobuf:"bytes,1,rep,name=pipeline_info,json=pipelineInfo" json:"pipeline_info,omitempty"`
+	// the requested pipelines that don't exist
+	Missing []*pachyderm_pps.Pipeline `protobuf:"bytes,2,rep,name=missing" json:"missing,omitempty"`
+}
+
+func (m *GetPipelineInfosResponse) Reset()                    { *m = GetPipelineInfosResponse{} }
+func (m *GetPipelineInfosResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetPipelineInfosResponse) ProtoMessage()               {}
+func (*GetPipelineInfosResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+func (m *GetPipelineInfosResponse) GetPipelineInfo() []*PipelineInfo {
+	if m != nil {
+		return m.PipelineInfo
+	}
+	return nil
+}
+
+func (m *GetPipelineInfosResponse) GetMissing() []*pachyderm_pps.Pipeline {
+	if m != nil {
+		return m.Missing
+	}
+	return nil
+}
+
+type DeleteJobInfosByCommitRequest struct {
+	// jobs whose inputs are exactly these commits are deleted
+	Commits []*pfs.Commit `protobuf:"bytes,1,rep,name=commits" json:"commits,omitempty"`
+}
+
+func (m *DeleteJobInfosByCommitRequest) Reset()                    { *m = DeleteJobInfosByCommitRequest{} }
+func (m *DeleteJobInfosByCommitRequest) String() string            { return proto.CompactTextString(m) }
+func (*DeleteJobInfosByCommitRequest) ProtoMessage()               {}
+func (*DeleteJobInfosByCommitRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+
+func (m *DeleteJobInfosByCommitRequest) GetCommits() []*pfs.Commit {
+	if m != nil {
+		return m.Commits
+	}
+	return nil
+}
+
+type DeletePipelineAndJobsRequest struct {
+	Pipeline *pachyderm_pps.Pipeline `protobuf:"bytes,1,opt,name=pipeline" json:"pipeline,omitempty"`
+	// must be set, so that a pipeline's jobs are only deleted on purpose
+	Cascade bool `protobuf:"varint,2,opt,name=cascade" json:"cascade,omitempty"`
+}
+
+func (m *DeletePipelineAndJobsRequest) Reset()                    { *m = DeletePipelineAndJobsRequest{} }
+func (m *DeletePipelineAndJobsRequest) String() string            { return proto.CompactTextString(m) }
+func (*DeletePipelineAndJobsRequest) ProtoMessage()               {}
+func (*DeletePipelineAndJobsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+
+func (m *DeletePipelineAndJobsRequest) GetPipeline() *pachyderm_pps.Pipeline {
+	if m != nil {
+		return m.Pipeline
+	}
+	return nil
+}
+
+type DeletePipelineAndJobsResponse struct {
+	PipelineSummary *WriteSummary `protobuf:"bytes,1,opt,name=pipeline_summary,json=pipelineSummary" json:"pipeline_summary,omitempty"`
+	JobSummary      *WriteSummary `protobuf:"bytes,2,opt,name=job_summary,json=jobSummary" json:"job_summary,omitempty"`
+}
+
+func (m *DeletePipelineAndJobsResponse) Reset()                    { *m = DeletePipelineAndJobsResponse{} }
+func (m *DeletePipelineAndJobsResponse) String() string            { return proto.CompactTextString(m) }
+func (*DeletePipelineAndJobsResponse) ProtoMessage()               {}
+func (*DeletePipelineAndJobsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+
+func (m *DeletePipelineAndJobsResponse) GetPipelineSummary() *WriteSummary {
+	if m != nil {
+		return m.PipelineSummary
+	}
+	return nil
+}
+
+func (m *DeletePipelineAndJobsResponse) GetJobSummary() *WriteSummary {
+	if m != nil {
+		return m.JobSummary
+	}
+	return nil
+}
+
+type WaitPipelineDeletedRequest struct {
+	Pipeline *pachyderm_pps.Pipeline `protobuf:"bytes,1,opt,name=pipeline" json:"pipeline,omitempty"`
+	// 0 means wait until the call is cancelled
+	TimeoutMilliseconds uint64 `protobuf:"varint,2,opt,name=timeout_milliseconds,json=timeoutMilliseconds" json:"timeout_milliseconds,omitempty"`
+}
+
+func (m *WaitPipelineDeletedRequest) Reset()                    { *m = WaitPipelineDeletedRequest{} }
+func (m *WaitPipelineDeletedRequest) String() string            { return proto.CompactTextString(m) }
+func (*WaitPipelineDeletedRequest) ProtoMessage()               {}
+func (*WaitPipelineDeletedRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+
+func (m *WaitPipelineDeletedRequest) GetPipeline() *pachyderm_pps.Pipeline {
+	if m != nil {
+		return m.Pipeline
+	}
+	return nil
+}
+
 type SubscribePipelineInfosRequest struct {
 	IncludeInitial bool   `protobuf:"varint,1,opt,name=include_initial,json=includeInitial" json:"include_initial,omitempty"`
 	Shard          *Shard `protobuf:"bytes,2,opt,name=shard" json:"shard,omitempty"`
+	// if set, changes to the same pipeline within this many milliseconds are
+	// collapsed into one change with the latest state
+	CoalesceMilliseconds uint64 `protobuf:"varint,3,opt,name=coalesce_milliseconds,json=coalesceMilliseconds" json:"coalesce_milliseconds,omitempty"`
 }
 
 func (m *SubscribePipelineInfosRequest) Reset()                    { *m = SubscribePipelineInfosRequest{} }
 func (m *SubscribePipelineInfosRequest) String() string            { return proto.CompactTextString(m) }
 func (*SubscribePipelineInfosRequest) ProtoMessage()               {}
-func (*SubscribePipelineInfosRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+func (*SubscribePipelineInfosRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
 
 func (m *SubscribePipelineInfosRequest) GetShard() *Shard {
 	if m != nil {
@@ -239,12 +531,21 @@ func (m *SubscribePipelineInfosRequest) GetShard() *Shard {
 
 type ListPipelineInfosRequest struct {
 	Shard *Shard `protobuf:"bytes,1,opt,name=shard" json:"shard,omitempty"`
+	// only list pipelines whose names start with name_prefix
+	NamePrefix string `protobuf:"bytes,2,opt,name=name_prefix,json=namePrefix" json:"name_prefix,omitempty"`
+	// NONE leaves pipelines in whatever order rethink reads them
+	OrderBy PipelineInfosOrder `protobuf:"varint,3,opt,name=order_by,json=orderBy,enum=pachyderm.pps.persist.PipelineInfosOrder" json:"order_by,omitempty"`
+	// when set, at most limit pipelines are returned ordered by name, which
+	// can't be combined with order_by
+	Limit uint64 `protobuf:"varint,4,opt,name=limit" json:"limit,omitempty"`
+	// the next_page_token of the previous page
+	PageToken string `protobuf:"bytes,5,opt,name=page_token,json=pageToken" json:"page_token,omitempty"`
 }
 
 func (m *ListPipelineInfosRequest) Reset()                    { *m = ListPipelineInfosRequest{} }
 func (m *ListPipelineInfosRequest) String() string            { return proto.CompactTextString(m) }
 func (*ListPipelineInfosRequest) ProtoMessage()               {}
-func (*ListPipelineInfosRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+func (*ListPipelineInfosRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
 
 func (m *ListPipelineInfosRequest) GetShard() *Shard {
 	if m != nil {
@@ -253,6 +554,78 @@ func (m *ListPipelineInfosRequest) GetShard() *Shard {
 	return nil
 }
 
+type ListPipelineNamesRequest struct {
+	// only list the pipelines in shard
+	Shard *Shard `protobuf:"bytes,1,opt,name=shard" json:"shard,omitempty"`
+}
+
+func (m *ListPipelineNamesRequest) Reset()                    { *m = ListPipelineNamesRequest{} }
+func (m *ListPipelineNamesRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListPipelineNamesRequest) ProtoMessage()               {}
+func (*ListPipelineNamesRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+
+func (m *ListPipelineNamesRequest) GetShard() *Shard {
+	if m != nil {
+		return m.Shard
+	}
+	return nil
+}
+
+type PipelineNames struct {
+	// sorted
+	Name []string `protobuf:"bytes,1,rep,name=name" json:"name,omitempty"`
+}
+
+func (m *PipelineNames) Reset()                    { *m = PipelineNames{} }
+func (m *PipelineNames) String() string            { return proto.CompactTextString(m) }
+func (*PipelineNames) ProtoMessage()               {}
+func (*PipelineNames) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+
+type JobStateCounts struct {
+	// keyed by pachyderm.pps.JobState name, states without jobs are left out
+	Counts map[string]uint64 `protobuf:"bytes,1,rep,name=counts" json:"counts,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+}
+
+func (m *JobStateCounts) Reset()                    { *m = JobStateCounts{} }
+func (m *JobStateCounts) String() string            { return proto.CompactTextString(m) }
+func (*JobStateCounts) ProtoMessage()               {}
+func (*JobStateCounts) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+
+func (m *JobStateCounts) GetCounts() map[string]uint64 {
+	if m != nil {
+		return m.Counts
+	}
+	return nil
+}
+
+type PodCounters struct {
+	PodsStarted   uint64 `protobuf:"varint,1,opt,name=pods_started,json=podsStarted" json:"pods_started,omitempty"`
+	PodsSucceeded uint64 `protobuf:"varint,2,opt,name=pods_succeeded,json=podsSucceeded" json:"pods_succeeded,omitempty"`
+	PodsFailed    uint64 `protobuf:"varint,3,opt,name=pods_failed,json=podsFailed" json:"pods_failed,omitempty"`
+}
+
+func (m *PodCounters) Reset()                    { *m = PodCounters{} }
+func (m *PodCounters) String() string            { return proto.CompactTextString(m) }
+func (*PodCounters) ProtoMessage()               {}
+func (*PodCounters) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
+
+type PingResponse struct {
+	// how long the query took to come back from rethink
+	RoundTrip *google_protobuf.Duration `protobuf:"bytes,1,opt,name=round_trip,json=roundTrip" json:"round_trip,omitempty"`
+}
+
+func (m *PingResponse) Reset()                    { *m = PingResponse{} }
+func (m *PingResponse) String() string            { return proto.CompactTextString(m) }
+func (*PingResponse) ProtoMessage()               {}
+func (*PingResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
+
+func (m *PingResponse) GetRoundTrip() *google_protobuf.Duration {
+	if m != nil {
+		return m.RoundTrip
+	}
+	return nil
+}
+
 // As in, sharding
 type Shard struct {
 	Number uint64 `protobuf:"varint,1,opt,name=number" json:"number,omitempty"`
@@ -261,19 +634,38 @@ type Shard struct {
 func (m *Shard) Reset()                    { *m = Shard{} }
 func (m *Shard) String() string            { return proto.CompactTextString(m) }
 func (*Shard) ProtoMessage()               {}
-func (*Shard) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+func (*Shard) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
 
 func init() {
 	proto.RegisterType((*JobInfo)(nil), "pachyderm.pps.persist.JobInfo")
 	proto.RegisterType((*JobInfos)(nil), "pachyderm.pps.persist.JobInfos")
+	proto.RegisterType((*RecentJobInfosRequest)(nil), "pachyderm.pps.persist.RecentJobInfosRequest")
+	proto.RegisterType((*GetJobInfosByCommitRangeRequest)(nil), "pachyderm.pps.persist.GetJobInfosByCommitRangeRequest")
+	proto.RegisterType((*WriteSummary)(nil), "pachyderm.pps.persist.WriteSummary")
+	proto.RegisterType((*ReindexJobInfosResponse)(nil), "pachyderm.pps.persist.ReindexJobInfosResponse")
 	proto.RegisterType((*JobOutput)(nil), "pachyderm.pps.persist.JobOutput")
 	proto.RegisterType((*JobState)(nil), "pachyderm.pps.persist.JobState")
+	proto.RegisterType((*JobStateTransition)(nil), "pachyderm.pps.persist.JobStateTransition")
 	proto.RegisterType((*PipelineInfo)(nil), "pachyderm.pps.persist.PipelineInfo")
 	proto.RegisterType((*PipelineInfoChange)(nil), "pachyderm.pps.persist.PipelineInfoChange")
 	proto.RegisterType((*PipelineInfos)(nil), "pachyderm.pps.persist.PipelineInfos")
+	proto.RegisterType((*GetJobInfosRequest)(nil), "pachyderm.pps.persist.GetJobInfosRequest")
+	proto.RegisterType((*GetJobInfosResponse)(nil), "pachyderm.pps.persist.GetJobInfosResponse")
+	proto.RegisterType((*GetPipelineInfosRequest)(nil), "pachyderm.pps.persist.GetPipelineInfosRequest")
+	proto.RegisterType((*GetPipelineInfosResponse)(nil), "pachyderm.pps.persist.GetPipelineInfosResponse")
+	proto.RegisterType((*DeleteJobInfosByCommitRequest)(nil), "pachyderm.pps.persist.DeleteJobInfosByCommitRequest")
+	proto.RegisterType((*DeletePipelineAndJobsRequest)(nil), "pachyderm.pps.persist.DeletePipelineAndJobsRequest")
+	proto.RegisterType((*DeletePipelineAndJobsResponse)(nil), "pachyderm.pps.persist.DeletePipelineAndJobsResponse")
+	proto.RegisterType((*WaitPipelineDeletedRequest)(nil), "pachyderm.pps.persist.WaitPipelineDeletedRequest")
 	proto.RegisterType((*SubscribePipelineInfosRequest)(nil), "pachyderm.pps.persist.SubscribePipelineInfosRequest")
 	proto.RegisterType((*ListPipelineInfosRequest)(nil), "pachyderm.pps.persist.ListPipelineInfosRequest")
+	proto.RegisterType((*ListPipelineNamesRequest)(nil), "pachyderm.pps.persist.ListPipelineNamesRequest")
+	proto.RegisterType((*PipelineNames)(nil), "pachyderm.pps.persist.PipelineNames")
+	proto.RegisterType((*JobStateCounts)(nil), "pachyderm.pps.persist.JobStateCounts")
+	proto.RegisterType((*PodCounters)(nil), "pachyderm.pps.persist.PodCounters")
+	proto.RegisterType((*PingResponse)(nil), "pachyderm.pps.persist.PingResponse")
 	proto.RegisterType((*Shard)(nil), "pachyderm.pps.persist.Shard")
+	proto.RegisterEnum("pachyderm.pps.persist.PipelineInfosOrder", PipelineInfosOrder_name, PipelineInfosOrder_value)
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -291,27 +683,74 @@ type APIClient interface {
 	// job_id cannot be set
 	// timestamp cannot be set
 	CreateJobInfo(ctx context.Context, in *JobInfo, opts ...grpc.CallOption) (*JobInfo, error)
+	// like CreateJobInfo but created_at must be set, for backfilling history.
+	// Only servers started with imports allowed accept it.
+	ImportJobInfo(ctx context.Context, in *JobInfo, opts ...grpc.CallOption) (*JobInfo, error)
 	InspectJob(ctx context.Context, in *pachyderm_pps.InspectJobRequest, opts ...grpc.CallOption) (*JobInfo, error)
+	// in the order requested
+	GetJobInfos(ctx context.Context, in *GetJobInfosRequest, opts ...grpc.CallOption) (*GetJobInfosResponse, error)
 	// ordered by time, latest to earliest
 	ListJobInfos(ctx context.Context, in *pachyderm_pps.ListJobRequest, opts ...grpc.CallOption) (*JobInfos, error)
+	// like ListJobInfos but sends jobs as they're read, so large results aren't
+	// buffered. Jobs from several pipelines aren't sorted, and there's no
+	// next_token.
+	ListJobInfosStream(ctx context.Context, in *pachyderm_pps.ListJobRequest, opts ...grpc.CallOption) (API_ListJobInfosStreamClient, error)
+	// sends every job of the pipeline, unordered and without buffering them,
+	// for archiving pipelines with too many jobs to list at once
+	ExportJobInfos(ctx context.Context, in *pachyderm_pps.Pipeline, opts ...grpc.CallOption) (API_ExportJobInfosClient, error)
+	// the n most recently created jobs across all pipelines, latest to earliest
+	RecentJobInfos(ctx context.Context, in *RecentJobInfosRequest, opts ...grpc.CallOption) (*JobInfos, error)
+	// counts the jobs in each state, of the pipeline or of every pipeline if
+	// its name is empty
+	AggregateJobStates(ctx context.Context, in *pachyderm_pps.Pipeline, opts ...grpc.CallOption) (*JobStateCounts, error)
+	// ordered by commit index
+	GetJobInfosByCommitRange(ctx context.Context, in *GetJobInfosByCommitRangeRequest, opts ...grpc.CallOption) (*JobInfos, error)
 	// should only be called when rolling back if a Job does not start!
-	DeleteJobInfo(ctx context.Context, in *pachyderm_pps.Job, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
+	DeleteJobInfo(ctx context.Context, in *pachyderm_pps.Job, opts ...grpc.CallOption) (*WriteSummary, error)
+	// for garbage collecting commits, deleting nothing isn't an error
+	DeleteJobInfosByCommit(ctx context.Context, in *DeleteJobInfosByCommitRequest, opts ...grpc.CallOption) (*WriteSummary, error)
+	// recomputes every job's commit index from its inputs, jobs that are
+	// already up to date are left alone so it's safe to rerun
+	ReindexJobInfos(ctx context.Context, in *google_protobuf1.Empty, opts ...grpc.CallOption) (*ReindexJobInfosResponse, error)
 	// JobOutput rpcs
-	CreateJobOutput(ctx context.Context, in *JobOutput, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
+	CreateJobOutput(ctx context.Context, in *JobOutput, opts ...grpc.CallOption) (*WriteSummary, error)
 	// JobState rpcs
-	CreateJobState(ctx context.Context, in *JobState, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
+	CreateJobState(ctx context.Context, in *JobState, opts ...grpc.CallOption) (*WriteSummary, error)
+	// only changes the state if it's currently from
+	TransitionJobState(ctx context.Context, in *JobStateTransition, opts ...grpc.CallOption) (*google_protobuf1.Empty, error)
+	// puts the job back in its starting state: running, no pods counted and no
+	// output commit, all in one write
+	RestartJob(ctx context.Context, in *pachyderm_pps.Job, opts ...grpc.CallOption) (*google_protobuf1.Empty, error)
 	// Pipeline rpcs
 	CreatePipelineInfo(ctx context.Context, in *PipelineInfo, opts ...grpc.CallOption) (*PipelineInfo, error)
 	GetPipelineInfo(ctx context.Context, in *pachyderm_pps.Pipeline, opts ...grpc.CallOption) (*PipelineInfo, error)
-	// ordered by time, latest to earliest
+	// replaces the pipeline info only if its version is still the request's,
+	// created_at is kept and the result has the next version
+	UpdatePipelineInfo(ctx context.Context, in *PipelineInfo, opts ...grpc.CallOption) (*PipelineInfo, error)
+	// in the order requested
+	GetPipelineInfos(ctx context.Context, in *GetPipelineInfosRequest, opts ...grpc.CallOption) (*GetPipelineInfosResponse, error)
+	// ordered by order_by
 	ListPipelineInfos(ctx context.Context, in *ListPipelineInfosRequest, opts ...grpc.CallOption) (*PipelineInfos, error)
-	DeletePipelineInfo(ctx context.Context, in *pachyderm_pps.Pipeline, opts ...grpc.CallOption) (*google_protobuf.Empty, error)
+	// like ListPipelineInfos but only reads the names, for when that's all
+	// that's needed
+	ListPipelineNames(ctx context.Context, in *ListPipelineNamesRequest, opts ...grpc.CallOption) (*PipelineNames, error)
+	DeletePipelineInfo(ctx context.Context, in *pachyderm_pps.Pipeline, opts ...grpc.CallOption) (*WriteSummary, error)
+	// deletes the pipeline info and every job info of the pipeline
+	DeletePipelineAndJobs(ctx context.Context, in *DeletePipelineAndJobsRequest, opts ...grpc.CallOption) (*DeletePipelineAndJobsResponse, error)
 	SubscribePipelineInfos(ctx context.Context, in *SubscribePipelineInfosRequest, opts ...grpc.CallOption) (API_SubscribePipelineInfosClient, error)
+	// returns once the pipeline info doesn't exist, it may already be gone
+	WaitPipelineDeleted(ctx context.Context, in *WaitPipelineDeletedRequest, opts ...grpc.CallOption) (*google_protobuf1.Empty, error)
 	// Shard rpcs
 	// Returns the new job info
 	StartPod(ctx context.Context, in *pachyderm_pps.Job, opts ...grpc.CallOption) (*JobInfo, error)
 	SucceedPod(ctx context.Context, in *pachyderm_pps.Job, opts ...grpc.CallOption) (*JobInfo, error)
 	FailPod(ctx context.Context, in *pachyderm_pps.Job, opts ...grpc.CallOption) (*JobInfo, error)
+	GetPodCounters(ctx context.Context, in *pachyderm_pps.Job, opts ...grpc.CallOption) (*PodCounters, error)
+	// used when a job is restarted
+	ResetPodCounters(ctx context.Context, in *pachyderm_pps.Job, opts ...grpc.CallOption) (*google_protobuf1.Empty, error)
+	// runs a trivial query, for checking that the database is reachable and
+	// how long a round trip to it takes
+	Ping(ctx context.Context, in *google_protobuf1.Empty, opts ...grpc.CallOption) (*PingResponse, error)
 }
 
 type aPIClient struct {
@@ -331,93 +770,80 @@ func (c *aPIClient) CreateJobInfo(ctx context.Context, in *JobInfo, opts ...grpc
 	return out, nil
 }
 
-func (c *aPIClient) InspectJob(ctx context.Context, in *pachyderm_pps.InspectJobRequest, opts ...grpc.CallOption) (*JobInfo, error) {
+func (c *aPIClient) ImportJobInfo(ctx context.Context, in *JobInfo, opts ...grpc.CallOption) (*JobInfo, error) {
 	out := new(JobInfo)
-	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/InspectJob", in, out, c.cc, opts...)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/ImportJobInfo", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *aPIClient) ListJobInfos(ctx context.Context, in *pachyderm_pps.ListJobRequest, opts ...grpc.CallOption) (*JobInfos, error) {
-	out := new(JobInfos)
-	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/ListJobInfos", in, out, c.cc, opts...)
+func (c *aPIClient) InspectJob(ctx context.Context, in *pachyderm_pps.InspectJobRequest, opts ...grpc.CallOption) (*JobInfo, error) {
+	out := new(JobInfo)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/InspectJob", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *aPIClient) DeleteJobInfo(ctx context.Context, in *pachyderm_pps.Job, opts ...grpc.CallOption) (*google_protobuf.Empty, error) {
-	out := new(google_protobuf.Empty)
-	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/DeleteJobInfo", in, out, c.cc, opts...)
+func (c *aPIClient) GetJobInfos(ctx context.Context, in *GetJobInfosRequest, opts ...grpc.CallOption) (*GetJobInfosResponse, error) {
+	out := new(GetJobInfosResponse)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/GetJobInfos", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *aPIClient) CreateJobOutput(ctx context.Context, in *JobOutput, opts ...grpc.CallOption) (*google_protobuf.Empty, error) {
-	out := new(google_protobuf.Empty)
-	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/CreateJobOutput", in, out, c.cc, opts...)
+func (c *aPIClient) ListJobInfos(ctx context.Context, in *pachyderm_pps.ListJobRequest, opts ...grpc.CallOption) (*JobInfos, error) {
+	out := new(JobInfos)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/ListJobInfos", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *aPIClient) CreateJobState(ctx context.Context, in *JobState, opts ...grpc.CallOption) (*google_protobuf.Empty, error) {
-	out := new(google_protobuf.Empty)
-	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/CreateJobState", in, out, c.cc, opts...)
+func (c *aPIClient) ListJobInfosStream(ctx context.Context, in *pachyderm_pps.ListJobRequest, opts ...grpc.CallOption) (API_ListJobInfosStreamClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_API_serviceDesc.Streams[0], c.cc, "/pachyderm.pps.persist.API/ListJobInfosStream", opts...)
 	if err != nil {
 		return nil, err
 	}
-	return out, nil
-}
-
-func (c *aPIClient) CreatePipelineInfo(ctx context.Context, in *PipelineInfo, opts ...grpc.CallOption) (*PipelineInfo, error) {
-	out := new(PipelineInfo)
-	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/CreatePipelineInfo", in, out, c.cc, opts...)
-	if err != nil {
+	x := &aPIListJobInfosStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
-	return out, nil
-}
-
-func (c *aPIClient) GetPipelineInfo(ctx context.Context, in *pachyderm_pps.Pipeline, opts ...grpc.CallOption) (*PipelineInfo, error) {
-	out := new(PipelineInfo)
-	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/GetPipelineInfo", in, out, c.cc, opts...)
-	if err != nil {
+	if err := x.ClientStream.CloseSend(); err != nil {
 		return nil, err
 	}
-	return out, nil
+	return x, nil
 }
 
-func (c *aPIClient) ListPipelineInfos(ctx context.Context, in *ListPipelineInfosRequest, opts ...grpc.CallOption) (*PipelineInfos, error) {
-	out := new(PipelineInfos)
-	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/ListPipelineInfos", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+type API_ListJobInfosStreamClient interface {
+	Recv() (*JobInfo, error)
+	grpc.ClientStream
 }
 
-func (c *aPIClient) DeletePipelineInfo(ctx context.Context, in *pachyderm_pps.Pipeline, opts ...grpc.CallOption) (*google_protobuf.Empty, error) {
-	out := new(google_protobuf.Empty)
-	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/DeletePipelineInfo", in, out, c.cc, opts...)
-	if err != nil {
+type aPIListJobInfosStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *aPIListJobInfosStreamClient) Recv() (*JobInfo, error) {
+	m := new(JobInfo)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
-	return out, nil
+	return m, nil
 }
 
-func (c *aPIClient) SubscribePipelineInfos(ctx context.Context, in *SubscribePipelineInfosRequest, opts ...grpc.CallOption) (API_SubscribePipelineInfosClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_API_serviceDesc.Streams[0], c.cc, "/pachyderm.pps.persist.API/SubscribePipelineInfos", opts...)
+func (c *aPIClient) ExportJobInfos(ctx context.Context, in *pachyderm_pps.Pipeline, opts ...grpc.CallOption) (API_ExportJobInfosClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_API_serviceDesc.Streams[1], c.cc, "/pachyderm.pps.persist.API/ExportJobInfos", opts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &aPISubscribePipelineInfosClient{stream}
+	x := &aPIExportJobInfosClient{stream}
 	if err := x.ClientStream.SendMsg(in); err != nil {
 		return nil, err
 	}
@@ -427,91 +853,368 @@ func (c *aPIClient) SubscribePipelineInfos(ctx context.Context, in *SubscribePip
 	return x, nil
 }
 
-type API_SubscribePipelineInfosClient interface {
-	Recv() (*PipelineInfoChange, error)
+type API_ExportJobInfosClient interface {
+	Recv() (*JobInfo, error)
 	grpc.ClientStream
 }
 
-type aPISubscribePipelineInfosClient struct {
+type aPIExportJobInfosClient struct {
 	grpc.ClientStream
 }
 
-func (x *aPISubscribePipelineInfosClient) Recv() (*PipelineInfoChange, error) {
-	m := new(PipelineInfoChange)
+func (x *aPIExportJobInfosClient) Recv() (*JobInfo, error) {
+	m := new(JobInfo)
 	if err := x.ClientStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
-func (c *aPIClient) StartPod(ctx context.Context, in *pachyderm_pps.Job, opts ...grpc.CallOption) (*JobInfo, error) {
-	out := new(JobInfo)
-	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/StartPod", in, out, c.cc, opts...)
+func (c *aPIClient) RecentJobInfos(ctx context.Context, in *RecentJobInfosRequest, opts ...grpc.CallOption) (*JobInfos, error) {
+	out := new(JobInfos)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/RecentJobInfos", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *aPIClient) SucceedPod(ctx context.Context, in *pachyderm_pps.Job, opts ...grpc.CallOption) (*JobInfo, error) {
-	out := new(JobInfo)
-	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/SucceedPod", in, out, c.cc, opts...)
+func (c *aPIClient) AggregateJobStates(ctx context.Context, in *pachyderm_pps.Pipeline, opts ...grpc.CallOption) (*JobStateCounts, error) {
+	out := new(JobStateCounts)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/AggregateJobStates", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-func (c *aPIClient) FailPod(ctx context.Context, in *pachyderm_pps.Job, opts ...grpc.CallOption) (*JobInfo, error) {
-	out := new(JobInfo)
-	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/FailPod", in, out, c.cc, opts...)
+func (c *aPIClient) GetJobInfosByCommitRange(ctx context.Context, in *GetJobInfosByCommitRangeRequest, opts ...grpc.CallOption) (*JobInfos, error) {
+	out := new(JobInfos)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/GetJobInfosByCommitRange", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-// Server API for API service
-
-type APIServer interface {
-	// Job rpcs
-	// job_id cannot be set
-	// timestamp cannot be set
-	CreateJobInfo(context.Context, *JobInfo) (*JobInfo, error)
-	InspectJob(context.Context, *pachyderm_pps.InspectJobRequest) (*JobInfo, error)
-	// ordered by time, latest to earliest
-	ListJobInfos(context.Context, *pachyderm_pps.ListJobRequest) (*JobInfos, error)
-	// should only be called when rolling back if a Job does not start!
-	DeleteJobInfo(context.Context, *pachyderm_pps.Job) (*google_protobuf.Empty, error)
-	// JobOutput rpcs
-	CreateJobOutput(context.Context, *JobOutput) (*google_protobuf.Empty, error)
-	// JobState rpcs
-	CreateJobState(context.Context, *JobState) (*google_protobuf.Empty, error)
-	// Pipeline rpcs
-	CreatePipelineInfo(context.Context, *PipelineInfo) (*PipelineInfo, error)
-	GetPipelineInfo(context.Context, *pachyderm_pps.Pipeline) (*PipelineInfo, error)
-	// ordered by time, latest to earliest
-	ListPipelineInfos(context.Context, *ListPipelineInfosRequest) (*PipelineInfos, error)
-	DeletePipelineInfo(context.Context, *pachyderm_pps.Pipeline) (*google_protobuf.Empty, error)
-	SubscribePipelineInfos(*SubscribePipelineInfosRequest, API_SubscribePipelineInfosServer) error
-	// Shard rpcs
-	// Returns the new job info
-	StartPod(context.Context, *pachyderm_pps.Job) (*JobInfo, error)
-	SucceedPod(context.Context, *pachyderm_pps.Job) (*JobInfo, error)
-	FailPod(context.Context, *pachyderm_pps.Job) (*JobInfo, error)
+func (c *aPIClient) DeleteJobInfo(ctx context.Context, in *pachyderm_pps.Job, opts ...grpc.CallOption) (*WriteSummary, error) {
+	out := new(WriteSummary)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/DeleteJobInfo", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func RegisterAPIServer(s *grpc.Server, srv APIServer) {
-	s.RegisterService(&_API_serviceDesc, srv)
+func (c *aPIClient) DeleteJobInfosByCommit(ctx context.Context, in *DeleteJobInfosByCommitRequest, opts ...grpc.CallOption) (*WriteSummary, error) {
+	out := new(WriteSummary)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/DeleteJobInfosByCommit", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func _API_CreateJobInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(JobInfo)
-	if err := dec(in); err != nil {
+func (c *aPIClient) ReindexJobInfos(ctx context.Context, in *google_protobuf1.Empty, opts ...grpc.CallOption) (*ReindexJobInfosResponse, error) {
+	out := new(ReindexJobInfosResponse)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/ReindexJobInfos", in, out, c.cc, opts...)
+	if err != nil {
 		return nil, err
 	}
-	if interceptor == nil {
-		return srv.(APIServer).CreateJobInfo(ctx, in)
+	return out, nil
+}
+
+func (c *aPIClient) CreateJobOutput(ctx context.Context, in *JobOutput, opts ...grpc.CallOption) (*WriteSummary, error) {
+	out := new(WriteSummary)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/CreateJobOutput", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) CreateJobState(ctx context.Context, in *JobState, opts ...grpc.CallOption) (*WriteSummary, error) {
+	out := new(WriteSummary)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/CreateJobState", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) TransitionJobState(ctx context.Context, in *JobStateTransition, opts ...grpc.CallOption) (*google_protobuf1.Empty, error) {
+	out := new(google_protobuf1.Empty)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/TransitionJobState", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) RestartJob(ctx context.Context, in *pachyderm_pps.Job, opts ...grpc.CallOption) (*google_protobuf1.Empty, error) {
+	out := new(google_protobuf1.Empty)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/RestartJob", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) CreatePipelineInfo(ctx context.Context, in *PipelineInfo, opts ...grpc.CallOption) (*PipelineInfo, error) {
+	out := new(PipelineInfo)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/CreatePipelineInfo", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) GetPipelineInfo(ctx context.Context, in *pachyderm_pps.Pipeline, opts ...grpc.CallOption) (*PipelineInfo, error) {
+	out := new(PipelineInfo)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/GetPipelineInfo", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) UpdatePipelineInfo(ctx context.Context, in *PipelineInfo, opts ...grpc.CallOption) (*PipelineInfo, error) {
+	out := new(PipelineInfo)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/UpdatePipelineInfo", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) GetPipelineInfos(ctx context.Context, in *GetPipelineInfosRequest, opts ...grpc.CallOption) (*GetPipelineInfosResponse, error) {
+	out := new(GetPipelineInfosResponse)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/GetPipelineInfos", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) ListPipelineInfos(ctx context.Context, in *ListPipelineInfosRequest, opts ...grpc.CallOption) (*PipelineInfos, error) {
+	out := new(PipelineInfos)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/ListPipelineInfos", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) ListPipelineNames(ctx context.Context, in *ListPipelineNamesRequest, opts ...grpc.CallOption) (*PipelineNames, error) {
+	out := new(PipelineNames)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/ListPipelineNames", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) DeletePipelineInfo(ctx context.Context, in *pachyderm_pps.Pipeline, opts ...grpc.CallOption) (*WriteSummary, error) {
+	out := new(WriteSummary)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/DeletePipelineInfo", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) DeletePipelineAndJobs(ctx context.Context, in *DeletePipelineAndJobsRequest, opts ...grpc.CallOption) (*DeletePipelineAndJobsResponse, error) {
+	out := new(DeletePipelineAndJobsResponse)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/DeletePipelineAndJobs", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) SubscribePipelineInfos(ctx context.Context, in *SubscribePipelineInfosRequest, opts ...grpc.CallOption) (API_SubscribePipelineInfosClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_API_serviceDesc.Streams[2], c.cc, "/pachyderm.pps.persist.API/SubscribePipelineInfos", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &aPISubscribePipelineInfosClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type API_SubscribePipelineInfosClient interface {
+	Recv() (*PipelineInfoChange, error)
+	grpc.ClientStream
+}
+
+type aPISubscribePipelineInfosClient struct {
+	grpc.ClientStream
+}
+
+func (x *aPISubscribePipelineInfosClient) Recv() (*PipelineInfoChange, error) {
+	m := new(PipelineInfoChange)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *aPIClient) WaitPipelineDeleted(ctx context.Context, in *WaitPipelineDeletedRequest, opts ...grpc.CallOption) (*google_protobuf1.Empty, error) {
+	out := new(google_protobuf1.Empty)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/WaitPipelineDeleted", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) StartPod(ctx context.Context, in *pachyderm_pps.Job, opts ...grpc.CallOption) (*JobInfo, error) {
+	out := new(JobInfo)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/StartPod", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) SucceedPod(ctx context.Context, in *pachyderm_pps.Job, opts ...grpc.CallOption) (*JobInfo, error) {
+	out := new(JobInfo)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/SucceedPod", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) FailPod(ctx context.Context, in *pachyderm_pps.Job, opts ...grpc.CallOption) (*JobInfo, error) {
+	out := new(JobInfo)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/FailPod", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) GetPodCounters(ctx context.Context, in *pachyderm_pps.Job, opts ...grpc.CallOption) (*PodCounters, error) {
+	out := new(PodCounters)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/GetPodCounters", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) ResetPodCounters(ctx context.Context, in *pachyderm_pps.Job, opts ...grpc.CallOption) (*google_protobuf1.Empty, error) {
+	out := new(google_protobuf1.Empty)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/ResetPodCounters", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIClient) Ping(ctx context.Context, in *google_protobuf1.Empty, opts ...grpc.CallOption) (*PingResponse, error) {
+	out := new(PingResponse)
+	err := grpc.Invoke(ctx, "/pachyderm.pps.persist.API/Ping", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for API service
+
+type APIServer interface {
+	// Job rpcs
+	// job_id cannot be set
+	// timestamp cannot be set
+	CreateJobInfo(context.Context, *JobInfo) (*JobInfo, error)
+	// like CreateJobInfo but created_at must be set, for backfilling history.
+	// Only servers started with imports allowed accept it.
+	ImportJobInfo(context.Context, *JobInfo) (*JobInfo, error)
+	InspectJob(context.Context, *pachyderm_pps.InspectJobRequest) (*JobInfo, error)
+	// in the order requested
+	GetJobInfos(context.Context, *GetJobInfosRequest) (*GetJobInfosResponse, error)
+	// ordered by time, latest to earliest
+	ListJobInfos(context.Context, *pachyderm_pps.ListJobRequest) (*JobInfos, error)
+	// like ListJobInfos but sends jobs as they're read, so large results aren't
+	// buffered. Jobs from several pipelines aren't sorted, and there's no
+	// next_token.
+	ListJobInfosStream(*pachyderm_pps.ListJobRequest, API_ListJobInfosStreamServer) error
+	// sends every job of the pipeline, unordered and without buffering them,
+	// for archiving pipelines with too many jobs to list at once
+	ExportJobInfos(*pachyderm_pps.Pipeline, API_ExportJobInfosServer) error
+	// the n most recently created jobs across all pipelines, latest to earliest
+	RecentJobInfos(context.Context, *RecentJobInfosRequest) (*JobInfos, error)
+	// counts the jobs in each state, of the pipeline or of every pipeline if
+	// its name is empty
+	AggregateJobStates(context.Context, *pachyderm_pps.Pipeline) (*JobStateCounts, error)
+	// ordered by commit index
+	GetJobInfosByCommitRange(context.Context, *GetJobInfosByCommitRangeRequest) (*JobInfos, error)
+	// should only be called when rolling back if a Job does not start!
+	DeleteJobInfo(context.Context, *pachyderm_pps.Job) (*WriteSummary, error)
+	// for garbage collecting commits, deleting nothing isn't an error
+	DeleteJobInfosByCommit(context.Context, *DeleteJobInfosByCommitRequest) (*WriteSummary, error)
+	// recomputes every job's commit index from its inputs, jobs that are
+	// already up to date are left alone so it's safe to rerun
+	ReindexJobInfos(context.Context, *google_protobuf1.Empty) (*ReindexJobInfosResponse, error)
+	// JobOutput rpcs
+	CreateJobOutput(context.Context, *JobOutput) (*WriteSummary, error)
+	// JobState rpcs
+	CreateJobState(context.Context, *JobState) (*WriteSummary, error)
+	// only changes the state if it's currently from
+	TransitionJobState(context.Context, *JobStateTransition) (*google_protobuf1.Empty, error)
+	// puts the job back in its starting state: running, no pods counted and no
+	// output commit, all in one write
+	RestartJob(context.Context, *pachyderm_pps.Job) (*google_protobuf1.Empty, error)
+	// Pipeline rpcs
+	CreatePipelineInfo(context.Context, *PipelineInfo) (*PipelineInfo, error)
+	GetPipelineInfo(context.Context, *pachyderm_pps.Pipeline) (*PipelineInfo, error)
+	// replaces the pipeline info only if its version is still the request's,
+	// created_at is kept and the result has the next version
+	UpdatePipelineInfo(context.Context, *PipelineInfo) (*PipelineInfo, error)
+	// in the order requested
+	GetPipelineInfos(context.Context, *GetPipelineInfosRequest) (*GetPipelineInfosResponse, error)
+	// ordered by order_by
+	ListPipelineInfos(context.Context, *ListPipelineInfosRequest) (*PipelineInfos, error)
+	// like ListPipelineInfos but only reads the names, for when that's all
+	// that's needed
+	ListPipelineNames(context.Context, *ListPipelineNamesRequest) (*PipelineNames, error)
+	DeletePipelineInfo(context.Context, *pachyderm_pps.Pipeline) (*WriteSummary, error)
+	// deletes the pipeline info and every job info of the pipeline
+	DeletePipelineAndJobs(context.Context, *DeletePipelineAndJobsRequest) (*DeletePipelineAndJobsResponse, error)
+	SubscribePipelineInfos(*SubscribePipelineInfosRequest, API_SubscribePipelineInfosServer) error
+	// returns once the pipeline info doesn't exist, it may already be gone
+	WaitPipelineDeleted(context.Context, *WaitPipelineDeletedRequest) (*google_protobuf1.Empty, error)
+	// Shard rpcs
+	// Returns the new job info
+	StartPod(context.Context, *pachyderm_pps.Job) (*JobInfo, error)
+	SucceedPod(context.Context, *pachyderm_pps.Job) (*JobInfo, error)
+	FailPod(context.Context, *pachyderm_pps.Job) (*JobInfo, error)
+	GetPodCounters(context.Context, *pachyderm_pps.Job) (*PodCounters, error)
+	// used when a job is restarted
+	ResetPodCounters(context.Context, *pachyderm_pps.Job) (*google_protobuf1.Empty, error)
+	// runs a trivial query, for checking that the database is reachable and
+	// how long a round trip to it takes
+	Ping(context.Context, *google_protobuf1.Empty) (*PingResponse, error)
+}
+
+func RegisterAPIServer(s *grpc.Server, srv APIServer) {
+	s.RegisterService(&_API_serviceDesc, srv)
+}
+
+func _API_CreateJobInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobInfo)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).CreateJobInfo(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
@@ -523,6 +1226,24 @@ func _API_CreateJobInfo_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _API_ImportJobInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobInfo)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).ImportJobInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pachyderm.pps.persist.API/ImportJobInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).ImportJobInfo(ctx, req.(*JobInfo))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _API_InspectJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(pachyderm_pps.InspectJobRequest)
 	if err := dec(in); err != nil {
@@ -541,6 +1262,24 @@ func _API_InspectJob_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _API_GetJobInfos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJobInfosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).GetJobInfos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pachyderm.pps.persist.API/GetJobInfos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).GetJobInfos(ctx, req.(*GetJobInfosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _API_ListJobInfos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(pachyderm_pps.ListJobRequest)
 	if err := dec(in); err != nil {
@@ -559,6 +1298,102 @@ func _API_ListJobInfos_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _API_ListJobInfosStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(pachyderm_pps.ListJobRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(APIServer).ListJobInfosStream(m, &aPIListJobInfosStreamServer{stream})
+}
+
+type API_ListJobInfosStreamServer interface {
+	Send(*JobInfo) error
+	grpc.ServerStream
+}
+
+type aPIListJobInfosStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *aPIListJobInfosStreamServer) Send(m *JobInfo) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _API_ExportJobInfos_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(pachyderm_pps.Pipeline)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(APIServer).ExportJobInfos(m, &aPIExportJobInfosServer{stream})
+}
+
+type API_ExportJobInfosServer interface {
+	Send(*JobInfo) error
+	grpc.ServerStream
+}
+
+type aPIExportJobInfosServer struct {
+	grpc.ServerStream
+}
+
+func (x *aPIExportJobInfosServer) Send(m *JobInfo) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _API_RecentJobInfos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecentJobInfosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).RecentJobInfos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pachyderm.pps.persist.API/RecentJobInfos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).RecentJobInfos(ctx, req.(*RecentJobInfosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_AggregateJobStates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(pachyderm_pps.Pipeline)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).AggregateJobStates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pachyderm.pps.persist.API/AggregateJobStates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).AggregateJobStates(ctx, req.(*pachyderm_pps.Pipeline))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_GetJobInfosByCommitRange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJobInfosByCommitRangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).GetJobInfosByCommitRange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pachyderm.pps.persist.API/GetJobInfosByCommitRange",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).GetJobInfosByCommitRange(ctx, req.(*GetJobInfosByCommitRangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _API_DeleteJobInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(pachyderm_pps.Job)
 	if err := dec(in); err != nil {
@@ -577,6 +1412,42 @@ func _API_DeleteJobInfo_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _API_DeleteJobInfosByCommit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteJobInfosByCommitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).DeleteJobInfosByCommit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pachyderm.pps.persist.API/DeleteJobInfosByCommit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).DeleteJobInfosByCommit(ctx, req.(*DeleteJobInfosByCommitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_ReindexJobInfos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(google_protobuf1.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).ReindexJobInfos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pachyderm.pps.persist.API/ReindexJobInfos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).ReindexJobInfos(ctx, req.(*google_protobuf1.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _API_CreateJobOutput_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(JobOutput)
 	if err := dec(in); err != nil {
@@ -613,6 +1484,42 @@ func _API_CreateJobState_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _API_TransitionJobState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobStateTransition)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).TransitionJobState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pachyderm.pps.persist.API/TransitionJobState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).TransitionJobState(ctx, req.(*JobStateTransition))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_RestartJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(pachyderm_pps.Job)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).RestartJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pachyderm.pps.persist.API/RestartJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).RestartJob(ctx, req.(*pachyderm_pps.Job))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _API_CreatePipelineInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(PipelineInfo)
 	if err := dec(in); err != nil {
@@ -649,6 +1556,42 @@ func _API_GetPipelineInfo_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _API_UpdatePipelineInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PipelineInfo)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).UpdatePipelineInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pachyderm.pps.persist.API/UpdatePipelineInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).UpdatePipelineInfo(ctx, req.(*PipelineInfo))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_GetPipelineInfos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPipelineInfosRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).GetPipelineInfos(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pachyderm.pps.persist.API/GetPipelineInfos",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).GetPipelineInfos(ctx, req.(*GetPipelineInfosRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _API_ListPipelineInfos_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListPipelineInfosRequest)
 	if err := dec(in); err != nil {
@@ -667,6 +1610,24 @@ func _API_ListPipelineInfos_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _API_ListPipelineNames_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPipelineNamesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).ListPipelineNames(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pachyderm.pps.persist.API/ListPipelineNames",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).ListPipelineNames(ctx, req.(*ListPipelineNamesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _API_DeletePipelineInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(pachyderm_pps.Pipeline)
 	if err := dec(in); err != nil {
@@ -685,6 +1646,24 @@ func _API_DeletePipelineInfo_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _API_DeletePipelineAndJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePipelineAndJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).DeletePipelineAndJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pachyderm.pps.persist.API/DeletePipelineAndJobs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).DeletePipelineAndJobs(ctx, req.(*DeletePipelineAndJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _API_SubscribePipelineInfos_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(SubscribePipelineInfosRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -706,6 +1685,24 @@ func (x *aPISubscribePipelineInfosServer) Send(m *PipelineInfoChange) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _API_WaitPipelineDeleted_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WaitPipelineDeletedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).WaitPipelineDeleted(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pachyderm.pps.persist.API/WaitPipelineDeleted",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).WaitPipelineDeleted(ctx, req.(*WaitPipelineDeletedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _API_StartPod_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(pachyderm_pps.Job)
 	if err := dec(in); err != nil {
@@ -760,6 +1757,60 @@ func _API_FailPod_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _API_GetPodCounters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(pachyderm_pps.Job)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).GetPodCounters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pachyderm.pps.persist.API/GetPodCounters",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).GetPodCounters(ctx, req.(*pachyderm_pps.Job))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_ResetPodCounters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(pachyderm_pps.Job)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).ResetPodCounters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pachyderm.pps.persist.API/ResetPodCounters",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).ResetPodCounters(ctx, req.(*pachyderm_pps.Job))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _API_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(google_protobuf1.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIServer).Ping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pachyderm.pps.persist.API/Ping",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIServer).Ping(ctx, req.(*google_protobuf1.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _API_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "pachyderm.pps.persist.API",
 	HandlerType: (*APIServer)(nil),
@@ -768,18 +1819,46 @@ var _API_serviceDesc = grpc.ServiceDesc{
 			MethodName: "CreateJobInfo",
 			Handler:    _API_CreateJobInfo_Handler,
 		},
+		{
+			MethodName: "ImportJobInfo",
+			Handler:    _API_ImportJobInfo_Handler,
+		},
 		{
 			MethodName: "InspectJob",
 			Handler:    _API_InspectJob_Handler,
 		},
+		{
+			MethodName: "GetJobInfos",
+			Handler:    _API_GetJobInfos_Handler,
+		},
 		{
 			MethodName: "ListJobInfos",
 			Handler:    _API_ListJobInfos_Handler,
 		},
+		{
+			MethodName: "RecentJobInfos",
+			Handler:    _API_RecentJobInfos_Handler,
+		},
+		{
+			MethodName: "AggregateJobStates",
+			Handler:    _API_AggregateJobStates_Handler,
+		},
+		{
+			MethodName: "GetJobInfosByCommitRange",
+			Handler:    _API_GetJobInfosByCommitRange_Handler,
+		},
 		{
 			MethodName: "DeleteJobInfo",
 			Handler:    _API_DeleteJobInfo_Handler,
 		},
+		{
+			MethodName: "DeleteJobInfosByCommit",
+			Handler:    _API_DeleteJobInfosByCommit_Handler,
+		},
+		{
+			MethodName: "ReindexJobInfos",
+			Handler:    _API_ReindexJobInfos_Handler,
+		},
 		{
 			MethodName: "CreateJobOutput",
 			Handler:    _API_CreateJobOutput_Handler,
@@ -788,6 +1867,14 @@ var _API_serviceDesc = grpc.ServiceDesc{
 			MethodName: "CreateJobState",
 			Handler:    _API_CreateJobState_Handler,
 		},
+		{
+			MethodName: "TransitionJobState",
+			Handler:    _API_TransitionJobState_Handler,
+		},
+		{
+			MethodName: "RestartJob",
+			Handler:    _API_RestartJob_Handler,
+		},
 		{
 			MethodName: "CreatePipelineInfo",
 			Handler:    _API_CreatePipelineInfo_Handler,
@@ -796,14 +1883,34 @@ var _API_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetPipelineInfo",
 			Handler:    _API_GetPipelineInfo_Handler,
 		},
+		{
+			MethodName: "UpdatePipelineInfo",
+			Handler:    _API_UpdatePipelineInfo_Handler,
+		},
+		{
+			MethodName: "GetPipelineInfos",
+			Handler:    _API_GetPipelineInfos_Handler,
+		},
 		{
 			MethodName: "ListPipelineInfos",
 			Handler:    _API_ListPipelineInfos_Handler,
 		},
+		{
+			MethodName: "ListPipelineNames",
+			Handler:    _API_ListPipelineNames_Handler,
+		},
 		{
 			MethodName: "DeletePipelineInfo",
 			Handler:    _API_DeletePipelineInfo_Handler,
 		},
+		{
+			MethodName: "DeletePipelineAndJobs",
+			Handler:    _API_DeletePipelineAndJobs_Handler,
+		},
+		{
+			MethodName: "WaitPipelineDeleted",
+			Handler:    _API_WaitPipelineDeleted_Handler,
+		},
 		{
 			MethodName: "StartPod",
 			Handler:    _API_StartPod_Handler,
@@ -816,8 +1923,30 @@ var _API_serviceDesc = grpc.ServiceDesc{
 			MethodName: "FailPod",
 			Handler:    _API_FailPod_Handler,
 		},
+		{
+			MethodName: "GetPodCounters",
+			Handler:    _API_GetPodCounters_Handler,
+		},
+		{
+			MethodName: "ResetPodCounters",
+			Handler:    _API_ResetPodCounters_Handler,
+		},
+		{
+			MethodName: "Ping",
+			Handler:    _API_Ping_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListJobInfosStream",
+			Handler:       _API_ListJobInfosStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExportJobInfos",
+			Handler:       _API_ExportJobInfos_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "SubscribePipelineInfos",
 			Handler:       _API_SubscribePipelineInfos_Handler,
@@ -827,66 +1956,135 @@ var _API_serviceDesc = grpc.ServiceDesc{
 }
 
 var fileDescriptor0 = []byte{
-	// 964 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x9c, 0x56, 0xeb, 0x72, 0xdb, 0x44,
-	0x14, 0xae, 0x13, 0xc7, 0xb6, 0x8e, 0x2f, 0x19, 0x76, 0x4a, 0xaa, 0x31, 0x2d, 0x31, 0x2a, 0x0c,
-	0x97, 0x19, 0xe4, 0x12, 0x3a, 0xcc, 0xf0, 0x83, 0x29, 0x6d, 0x28, 0xc5, 0x40, 0x8b, 0xab, 0xe4,
-	0x0f, 0xfc, 0x11, 0xb2, 0xb4, 0x4e, 0xd4, 0xd1, 0x0d, 0x5d, 0x3a, 0x74, 0x06, 0x1e, 0x80, 0xb7,
-	0xe2, 0x11, 0x78, 0x24, 0xce, 0x9e, 0x5d, 0x39, 0x8e, 0x6d, 0xd9, 0x6a, 0x7e, 0x78, 0xac, 0xfd,
-	0xce, 0x77, 0x2e, 0xfb, 0xed, 0x39, 0x2b, 0xc1, 0x28, 0xe3, 0xe9, 0x6b, 0x9e, 0x8e, 0x93, 0x24,
-	0x1b, 0x27, 0x3c, 0xcd, 0xfc, 0x2c, 0x2f, 0xff, 0xcd, 0x24, 0x8d, 0xf3, 0x98, 0xbd, 0x9b, 0x38,
-	0xee, 0xe5, 0x1b, 0x8f, 0xa7, 0xa1, 0x89, 0x24, 0x53, 0x19, 0x87, 0xef, 0x5d, 0xc4, 0xf1, 0x45,
-	0xc0, 0xc7, 0x44, 0x9a, 0x15, 0xf3, 0x31, 0x0f, 0x93, 0xfc, 0x8d, 0xf4, 0x19, 0x1e, 0xaf, 0x1a,
-	0x73, 0x3f, 0xe4, 0x59, 0xee, 0x84, 0x89, 0x22, 0xdc, 0x76, 0x03, 0x9f, 0x47, 0x98, 0x6a, 0x9e,
-	0x89, 0xdf, 0x2a, 0x2a, 0x8a, 0x49, 0x14, 0x6a, 0xfc, 0xd7, 0x84, 0xf6, 0x8f, 0xf1, 0x6c, 0x12,
-	0xcd, 0xb1, 0x18, 0x68, 0xbd, 0x8a, 0x67, 0xb6, 0xef, 0xe9, 0x8d, 0x51, 0xe3, 0x13, 0xcd, 0x3a,
-	0xc0, 0xd5, 0xc4, 0x63, 0x5f, 0x81, 0x96, 0xa7, 0x4e, 0x94, 0xcd, 0xe3, 0x34, 0xd4, 0xf7, 0xd0,
-	0xd2, 0x3d, 0xd1, 0xcd, 0xeb, 0x75, 0x9f, 0x97, 0x76, 0xeb, 0x8a, 0xca, 0xee, 0x43, 0x3f, 0xf1,
-	0x13, 0x1e, 0xf8, 0x11, 0xb7, 0x23, 0x27, 0xe4, 0xfa, 0x3e, 0x45, 0xed, 0x95, 0xe0, 0x0b, 0xc4,
-	0xd8, 0x08, 0xba, 0x89, 0x93, 0x3a, 0x41, 0x80, 0x50, 0x16, 0xea, 0x4d, 0xa4, 0x34, 0xad, 0x65,
-	0x88, 0x8d, 0xa1, 0xe5, 0x47, 0x49, 0x91, 0x67, 0xfa, 0xc1, 0x68, 0x1f, 0x73, 0xdf, 0x59, 0xc9,
-	0x4d, 0xd5, 0xa3, 0xdd, 0x52, 0x34, 0xf6, 0x05, 0x00, 0xfa, 0xe3, 0x56, 0x6d, 0xac, 0x5f, 0x6f,
-	0x51, 0xc1, 0x6c, 0xdd, 0xc9, 0xd2, 0x24, 0x0b, 0x1f, 0xd9, 0xd7, 0x00, 0x6e, 0xca, 0x9d, 0x9c,
-	0x7b, 0xb6, 0x93, 0xeb, 0x6d, 0x72, 0x19, 0x9a, 0x52, 0x67, 0xb3, 0xd4, 0xd9, 0x3c, 0x2f, 0x75,
-	0xb6, 0x34, 0xc5, 0x7e, 0x9c, 0xb3, 0x07, 0xd0, 0x8f, 0x8b, 0x1c, 0x13, 0xdb, 0x6e, 0x1c, 0x86,
-	0x7e, 0xae, 0x77, 0xc8, 0xbb, 0x6b, 0x0a, 0xe5, 0x4f, 0x09, 0xb2, 0x7a, 0x92, 0x21, 0x57, 0xec,
-	0x73, 0x38, 0xc0, 0x28, 0x39, 0xd7, 0x35, 0x64, 0x0e, 0x36, 0xed, 0xe7, 0x4c, 0x98, 0x2d, 0xc9,
-	0x62, 0x1f, 0x40, 0x4f, 0x46, 0xb6, 0xfd, 0xc8, 0xe3, 0x7f, 0xea, 0x40, 0x2a, 0x76, 0x25, 0x36,
-	0x11, 0x90, 0xa0, 0x24, 0xb1, 0x97, 0xd9, 0xe8, 0x90, 0x62, 0x55, 0x7a, 0x57, 0xa9, 0x88, 0xd8,
-	0x99, 0x84, 0xd8, 0x47, 0x30, 0x90, 0x94, 0xc2, 0x75, 0x39, 0xf7, 0x90, 0xd4, 0x23, 0x52, 0x9f,
-	0x48, 0x25, 0xc8, 0x8e, 0x81, 0xbc, 0xec, 0xb9, 0xe3, 0x07, 0xc8, 0xe9, 0x13, 0x07, 0x04, 0xf4,
-	0x3d, 0x21, 0x22, 0x55, 0x76, 0xe9, 0xa4, 0x9e, 0x1d, 0xc6, 0x5e, 0x11, 0xf8, 0xfa, 0x00, 0xcf,
-	0x04, 0x53, 0x11, 0xf6, 0x9c, 0x20, 0xe3, 0x29, 0x74, 0x54, 0x47, 0x65, 0x28, 0x6c, 0x87, 0x5a,
-	0x0a, 0x17, 0xd8, 0x54, 0xe2, 0xf8, 0xde, 0x37, 0x37, 0xb6, 0xbc, 0xa9, 0x5c, 0xac, 0xf6, 0x2b,
-	0xf9, 0x60, 0x9c, 0x83, 0x86, 0xd8, 0x2f, 0xa4, 0x5c, 0x55, 0x6b, 0xae, 0x89, 0xbf, 0xb7, 0x43,
-	0x7c, 0x63, 0x4a, 0xc5, 0x91, 0xc0, 0x55, 0x41, 0x17, 0xe7, 0xb3, 0x57, 0xe7, 0x7c, 0x8c, 0x7f,
-	0xf7, 0xa0, 0x37, 0x55, 0x2d, 0x4d, 0x63, 0xb4, 0xd6, 0xf7, 0x8d, 0x0d, 0x7d, 0x7f, 0xd3, 0xa1,
-	0x5a, 0x99, 0x97, 0xfd, 0xf5, 0x79, 0x79, 0xb8, 0x98, 0x97, 0x26, 0x09, 0x7e, 0x77, 0x25, 0xec,
-	0x55, 0xad, 0xcb, 0x43, 0xf3, 0x19, 0x74, 0x95, 0x92, 0x29, 0x4f, 0x62, 0x1c, 0x35, 0x51, 0x91,
-	0x46, 0x3a, 0x5a, 0x08, 0x58, 0x20, 0xad, 0xe2, 0x79, 0x65, 0x5a, 0x5a, 0x6f, 0x33, 0x2d, 0xb7,
-	0x51, 0x5b, 0xd1, 0x2a, 0x34, 0x63, 0x4d, 0x4b, 0x2e, 0x8c, 0x18, 0xd8, 0xb2, 0x82, 0xa7, 0x97,
-	0x4e, 0x74, 0xc1, 0xd9, 0x23, 0xe8, 0x94, 0x92, 0x91, 0x84, 0xdd, 0x93, 0xfb, 0x15, 0xbd, 0xb3,
-	0xec, 0x6c, 0x2d, 0x9c, 0x98, 0x0e, 0xed, 0x94, 0x87, 0xf1, 0x6b, 0x6c, 0x64, 0xa1, 0x70, 0xc7,
-	0x2a, 0x97, 0xc6, 0xaf, 0xd0, 0x5f, 0xf6, 0xc9, 0xd8, 0x0f, 0x4b, 0x67, 0xb6, 0xd4, 0xac, 0xb5,
-	0x12, 0x2e, 0x0e, 0x96, 0xda, 0xf6, 0x2f, 0xb8, 0x77, 0x56, 0xcc, 0x32, 0x37, 0xf5, 0x67, 0xfc,
-	0x5a, 0x0e, 0x8b, 0xff, 0x51, 0xa0, 0x20, 0xec, 0x63, 0x38, 0xf4, 0x23, 0x37, 0x28, 0x3c, 0x91,
-	0xc9, 0xcf, 0x7d, 0x27, 0xa0, 0xdd, 0x75, 0xac, 0x81, 0x82, 0x27, 0x12, 0x65, 0x27, 0xa5, 0x56,
-	0xb2, 0x3d, 0xee, 0x56, 0xd4, 0x72, 0x26, 0x38, 0xa5, 0x92, 0x2f, 0x40, 0xff, 0x19, 0xc1, 0x8d,
-	0x89, 0x17, 0xf1, 0x1a, 0xf5, 0xe3, 0x1d, 0xc3, 0x01, 0xad, 0xd9, 0x11, 0xb4, 0xa2, 0x22, 0x9c,
-	0xf1, 0x94, 0xbc, 0x9b, 0x96, 0x5a, 0x9d, 0xfc, 0xa3, 0xc1, 0xfe, 0xe3, 0xe9, 0x84, 0xbd, 0x84,
-	0xfe, 0x29, 0x9d, 0x72, 0xf9, 0x32, 0xd9, 0x31, 0xe7, 0xc3, 0x1d, 0x76, 0xe3, 0x16, 0x9b, 0x02,
-	0x4c, 0xa2, 0x2c, 0xe1, 0x2e, 0x5d, 0xd1, 0xa3, 0x15, 0xfe, 0x95, 0x49, 0xed, 0xaf, 0x56, 0xc4,
-	0x9e, 0x50, 0x67, 0x71, 0x3b, 0xdd, 0x5b, 0xf1, 0x50, 0xc6, 0x32, 0xe0, 0xf1, 0xf6, 0x80, 0x19,
-	0x46, 0xfc, 0x06, 0xfa, 0xdf, 0xf1, 0x80, 0x5f, 0x6d, 0x7b, 0xc3, 0x8b, 0x66, 0x78, 0xb4, 0x36,
-	0x1b, 0x4f, 0xc5, 0xeb, 0x1c, 0xdd, 0x9f, 0xc3, 0xe1, 0x42, 0x35, 0x75, 0xd3, 0x8d, 0xaa, 0x93,
-	0x4a, 0xc6, 0x96, 0x70, 0x3f, 0xc1, 0x60, 0x11, 0x4e, 0x5e, 0x71, 0x5b, 0xb6, 0x40, 0x84, 0x2d,
-	0xc1, 0x7e, 0x07, 0x26, 0x83, 0x5d, 0xbf, 0xdc, 0x6a, 0x4c, 0xc4, 0xb0, 0x0e, 0x09, 0x33, 0xbc,
-	0x84, 0xc3, 0x67, 0xfc, 0x5a, 0xaf, 0xb2, 0x3b, 0x15, 0x97, 0x55, 0xdd, 0x90, 0x01, 0xbc, 0xb3,
-	0xd6, 0xff, 0x6c, 0x5c, 0xe1, 0x5b, 0x35, 0x29, 0xc3, 0x0f, 0x6b, 0x24, 0x13, 0xa7, 0xff, 0x0c,
-	0x98, 0x3c, 0xfd, 0x7a, 0x7b, 0xa8, 0xd6, 0xfa, 0x6f, 0x38, 0xda, 0x7c, 0x69, 0xb0, 0x87, 0x55,
-	0x53, 0xba, 0xed, 0x8e, 0x19, 0x7e, 0x5a, 0x63, 0x03, 0xf2, 0x96, 0x35, 0x6e, 0x3d, 0x68, 0xb0,
-	0x6f, 0xa1, 0x43, 0xdf, 0x09, 0xd3, 0xd8, 0xdb, 0xd8, 0xc0, 0xbb, 0x27, 0xeb, 0x09, 0x80, 0xfa,
-	0x88, 0xb8, 0x79, 0x8c, 0x47, 0xd0, 0x16, 0x1f, 0x19, 0x37, 0x0e, 0xf0, 0x44, 0xfb, 0xad, 0xad,
-	0xc0, 0x59, 0x8b, 0x24, 0xfe, 0xf2, 0xff, 0x00, 0x00, 0x00, 0xff, 0xff, 0xe6, 0x52, 0xb5, 0xda,
-	0x82, 0x0b, 0x00, 0x00,
+	// 2077 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x58, 0xdd, 0x72, 0xdb, 0xc6,
+	0x15, 0x16, 0x48, 0xfd, 0x90, 0x87, 0x12, 0x45, 0xaf, 0x2d, 0x1b, 0x61, 0xad, 0x88, 0x85, 0xed,
+	0x5a, 0x76, 0x5d, 0x2a, 0x96, 0x3c, 0x99, 0xd8, 0x37, 0xa9, 0x2c, 0xd1, 0x09, 0x35, 0xb1, 0xc4,
+	0x42, 0x6a, 0xdc, 0xa6, 0x17, 0x08, 0x48, 0xac, 0x68, 0xc8, 0x04, 0x16, 0xc5, 0x2e, 0x3c, 0xd6,
+	0x45, 0x33, 0xd3, 0x99, 0x4c, 0x1f, 0xa1, 0x7d, 0x8e, 0x5e, 0xf4, 0x0d, 0xfa, 0x14, 0x7d, 0x84,
+	0x3e, 0x44, 0xa7, 0xb3, 0x3f, 0x00, 0x41, 0x12, 0x10, 0x11, 0xdb, 0xb9, 0xd0, 0x88, 0x7b, 0xfe,
+	0xf6, 0xec, 0xd9, 0x73, 0xbe, 0x3d, 0x07, 0xd0, 0xa2, 0x38, 0x7c, 0x8b, 0xc3, 0x9d, 0x20, 0xa0,
+	0x3b, 0x01, 0x0e, 0xa9, 0x4b, 0x59, 0xfc, 0xbf, 0x1d, 0x84, 0x84, 0x11, 0xb4, 0x11, 0xd8, 0x83,
+	0xd7, 0x97, 0x0e, 0x0e, 0xbd, 0x76, 0x10, 0xd0, 0xb6, 0x62, 0x36, 0x3f, 0x1d, 0x12, 0x32, 0x1c,
+	0xe1, 0x1d, 0x21, 0xd4, 0x8f, 0xce, 0x77, 0x9c, 0x28, 0xb4, 0x99, 0x4b, 0x7c, 0xa9, 0xd6, 0xfc,
+	0xc5, 0x34, 0x1f, 0x7b, 0x01, 0xbb, 0x54, 0xcc, 0xad, 0x69, 0x26, 0x73, 0x3d, 0x4c, 0x99, 0xed,
+	0x05, 0x4a, 0xe0, 0xc6, 0x60, 0xe4, 0x62, 0x9f, 0xed, 0x04, 0xe7, 0x94, 0xff, 0x4d, 0x53, 0xb9,
+	0xb3, 0x81, 0xa2, 0x1a, 0xff, 0x5b, 0x82, 0x95, 0x23, 0xd2, 0xef, 0xfa, 0xe7, 0x04, 0x6d, 0xc0,
+	0xf2, 0x05, 0xe9, 0x5b, 0xae, 0xa3, 0x6b, 0x2d, 0x6d, 0xbb, 0x6a, 0x2e, 0x5d, 0x90, 0x7e, 0xd7,
+	0x41, 0x9f, 0x43, 0x95, 0x85, 0xb6, 0x4f, 0xcf, 0x49, 0xe8, 0xe9, 0xa5, 0x96, 0xb6, 0x5d, 0xdb,
+	0xd5, 0xdb, 0x93, 0xe7, 0x3a, 0x8b, 0xf9, 0xe6, 0x58, 0x14, 0xdd, 0x81, 0xb5, 0xc0, 0x0d, 0xf0,
+	0xc8, 0xf5, 0xb1, 0xe5, 0xdb, 0x1e, 0xd6, 0xcb, 0xc2, 0xea, 0x6a, 0x4c, 0x3c, 0xb6, 0x3d, 0x8c,
+	0x5a, 0x50, 0x0b, 0xec, 0xd0, 0x1e, 0x8d, 0xf0, 0xc8, 0xa5, 0x9e, 0xbe, 0xd8, 0xd2, 0xb6, 0x17,
+	0xcd, 0x34, 0x09, 0xed, 0xc0, 0xb2, 0xeb, 0x07, 0x11, 0xa3, 0xfa, 0x52, 0xab, 0xbc, 0x5d, 0xdb,
+	0xbd, 0x35, 0xb5, 0xb7, 0xf0, 0x3e, 0x88, 0x98, 0xa9, 0xc4, 0xd0, 0x63, 0x80, 0xc0, 0x0e, 0xb1,
+	0xcf, 0xac, 0x0b, 0xd2, 0xd7, 0x97, 0x85, 0xc3, 0x68, 0x56, 0xc9, 0xac, 0x4a, 0xa9, 0x23, 0xd2,
+	0x47, 0x4f, 0x01, 0x06, 0x21, 0xb6, 0x19, 0x76, 0x2c, 0x9b, 0xe9, 0x2b, 0x42, 0xa5, 0xd9, 0x96,
+	0x71, 0x6e, 0xc7, 0x71, 0x6e, 0x9f, 0xc5, 0x71, 0x36, 0xab, 0x4a, 0x7a, 0x9f, 0xa1, 0xcf, 0x60,
+	0x8d, 0x44, 0x2c, 0x88, 0x98, 0x35, 0x20, 0x9e, 0xe7, 0x32, 0xbd, 0x22, 0xb4, 0x6b, 0x6d, 0x1e,
+	0xf9, 0x03, 0x41, 0x32, 0x57, 0xa5, 0x84, 0x5c, 0xa1, 0xdf, 0xc0, 0x12, 0x65, 0x36, 0xc3, 0x7a,
+	0xb5, 0xa5, 0x6d, 0xd7, 0xb3, 0xce, 0x73, 0xca, 0xd9, 0xa6, 0x94, 0x42, 0xbf, 0x84, 0x55, 0x69,
+	0xd9, 0x72, 0x7d, 0x07, 0xbf, 0xd3, 0x41, 0x44, 0xb1, 0x26, 0x69, 0x5d, 0x4e, 0xe2, 0x22, 0x01,
+	0x71, 0xa8, 0x45, 0x99, 0x1d, 0x32, 0xec, 0xe8, 0x35, 0x15, 0x45, 0xe2, 0xd0, 0x53, 0x49, 0x42,
+	0xf7, 0xa0, 0x2e, 0x45, 0xa2, 0xc1, 0x00, 0x63, 0x07, 0x3b, 0xfa, 0xaa, 0x10, 0x5a, 0x13, 0x42,
+	0x31, 0x11, 0x6d, 0x81, 0xd0, 0xb2, 0xce, 0x6d, 0x77, 0x84, 0x1d, 0x7d, 0x4d, 0xc8, 0x00, 0x27,
+	0xbd, 0x10, 0x14, 0xbe, 0x15, 0x7d, 0x6d, 0x87, 0x8e, 0xe5, 0x11, 0x27, 0x1a, 0xb9, 0x7a, 0xbd,
+	0x55, 0xe6, 0x5b, 0x09, 0xda, 0x4b, 0x41, 0xe2, 0xc1, 0x8c, 0x02, 0x27, 0x0e, 0xe6, 0xfa, 0xfc,
+	0x60, 0x2a, 0xe9, 0x7d, 0x86, 0x1e, 0x40, 0x23, 0x49, 0x99, 0xb7, 0xbc, 0x56, 0x88, 0xaf, 0x37,
+	0x84, 0x0f, 0xeb, 0x31, 0xfd, 0x5b, 0x49, 0x46, 0x4d, 0xa8, 0x84, 0x58, 0x1c, 0x98, 0xea, 0xd7,
+	0x84, 0x48, 0xb2, 0x46, 0x0f, 0xe1, 0x1a, 0x7d, 0xe3, 0x06, 0xd6, 0x44, 0xdc, 0x50, 0x4b, 0xdb,
+	0xae, 0x98, 0xeb, 0x9c, 0x71, 0x30, 0x8e, 0x9d, 0xe1, 0x40, 0x45, 0xe5, 0x3f, 0x45, 0x4f, 0xa1,
+	0x22, 0x0a, 0xc0, 0x3f, 0x27, 0xba, 0x26, 0x92, 0xed, 0xd3, 0x76, 0x66, 0x01, 0xb7, 0x95, 0x8a,
+	0xb9, 0x72, 0x21, 0x7f, 0xa0, 0x4d, 0x00, 0x1f, 0xbf, 0x63, 0x16, 0x23, 0x6f, 0xb0, 0x2f, 0xaa,
+	0xa4, 0x6a, 0x56, 0x39, 0xe5, 0x8c, 0x13, 0x8c, 0x7b, 0xb0, 0x61, 0xe2, 0x81, 0xcc, 0x36, 0xb1,
+	0x97, 0x89, 0xff, 0x1c, 0x61, 0xca, 0xd0, 0x2a, 0x68, 0xbe, 0x28, 0xb7, 0x45, 0x53, 0xf3, 0x8d,
+	0x4b, 0xd8, 0xfa, 0x0a, 0x27, 0x32, 0xcf, 0x2f, 0x55, 0xfe, 0xd8, 0xfe, 0x10, 0xc7, 0x0a, 0x8f,
+	0x00, 0x89, 0x53, 0x4e, 0x1e, 0x4e, 0x16, 0x6c, 0x43, 0x70, 0x52, 0xa7, 0x43, 0xdb, 0xd0, 0xc0,
+	0xbe, 0x33, 0x29, 0x2b, 0x9d, 0xab, 0x63, 0xdf, 0x49, 0xc7, 0xe1, 0x07, 0x58, 0x7d, 0x15, 0xba,
+	0x0c, 0x9f, 0x46, 0x9e, 0x67, 0x87, 0x97, 0x3c, 0xbe, 0xae, 0x4f, 0xb1, 0xc8, 0x27, 0xe9, 0x5f,
+	0xb2, 0x96, 0xb1, 0x0f, 0x46, 0xf6, 0x00, 0x3b, 0x7a, 0x29, 0x8e, 0xbd, 0x5c, 0x23, 0x1d, 0x56,
+	0x1c, 0x3c, 0xc2, 0x5c, 0xad, 0x2c, 0x58, 0xf1, 0x12, 0xdd, 0x86, 0x6a, 0xe4, 0x0f, 0x5e, 0xf3,
+	0xc3, 0x38, 0xaa, 0xd0, 0xc7, 0x04, 0x63, 0x0f, 0x6e, 0x99, 0x58, 0x38, 0x38, 0x0e, 0x11, 0x0d,
+	0x88, 0x4f, 0x31, 0x37, 0xa9, 0x52, 0x44, 0x79, 0x12, 0x2f, 0x8d, 0x33, 0xa8, 0x1e, 0x91, 0xfe,
+	0x89, 0xa8, 0xae, 0x3c, 0xf8, 0x9a, 0x29, 0xd0, 0xd2, 0x9c, 0x02, 0x35, 0x7a, 0x50, 0x89, 0x8b,
+	0x30, 0xcf, 0x68, 0x52, 0xc3, 0xa5, 0x22, 0x35, 0x6c, 0xfc, 0x55, 0x03, 0x14, 0xd3, 0x04, 0x56,
+	0xba, 0x1c, 0xec, 0xf3, 0x8c, 0xff, 0x1a, 0x16, 0xcf, 0x43, 0xe2, 0xcd, 0xb3, 0x2d, 0x84, 0xd0,
+	0x7d, 0x28, 0x31, 0xa2, 0x97, 0xaf, 0x16, 0x2d, 0x31, 0x62, 0xfc, 0xa7, 0x04, 0xab, 0x3d, 0x55,
+	0x44, 0x22, 0x65, 0x67, 0xf0, 0x59, 0xcb, 0xc0, 0xe7, 0xf7, 0x05, 0xff, 0x29, 0x5c, 0x2f, 0xcf,
+	0xe2, 0xfa, 0x93, 0x04, 0xd7, 0x17, 0x45, 0xa9, 0xdd, 0x9e, 0x32, 0x3b, 0xf6, 0x35, 0x0d, 0xee,
+	0x0f, 0xa1, 0xa6, 0x6e, 0x33, 0xc4, 0x01, 0xd1, 0x97, 0x84, 0x47, 0x55, 0x71, 0x97, 0x26, 0x0e,
+	0x88, 0x09, 0x92, 0xcb, 0x7f, 0x4f, 0xa1, 0xfa, 0xf2, 0x4f, 0x41, 0xf5, 0x1b, 0xb0, 0x24, 0x20,
+	0x4d, 0xbc, 0x05, 0x8b, 0xa6, 0x5c, 0xf0, 0x44, 0x8c, 0x51, 0xa9, 0x22, 0x13, 0x51, 0x2d, 0x0d,
+	0x02, 0x28, 0x1d, 0xdb, 0x03, 0x91, 0xd4, 0xe8, 0x4b, 0xa8, 0xc4, 0xc1, 0x14, 0xc1, 0xad, 0xed,
+	0xde, 0xc9, 0xc1, 0x93, 0xb4, 0xb2, 0x99, 0x28, 0xf1, 0x0d, 0x43, 0xec, 0x91, 0xb7, 0xaa, 0xce,
+	0x2a, 0x66, 0xbc, 0xe4, 0x19, 0xb5, 0x96, 0x56, 0xa2, 0xe8, 0xeb, 0xd4, 0x75, 0xa6, 0x10, 0xac,
+	0xd0, 0x8e, 0xab, 0x41, 0x6a, 0x85, 0x7e, 0x05, 0xeb, 0x02, 0xcb, 0x02, 0x7b, 0x88, 0x27, 0x00,
+	0x6d, 0x8d, 0x93, 0x7b, 0xf6, 0x10, 0x4b, 0x50, 0x7b, 0x06, 0x28, 0x85, 0x56, 0x31, 0x40, 0xdd,
+	0x85, 0x32, 0x7f, 0x77, 0xe5, 0xee, 0x59, 0xef, 0x2e, 0x67, 0x1b, 0x3f, 0xc0, 0xf5, 0x09, 0x5d,
+	0x55, 0xea, 0x1f, 0x80, 0xc0, 0x8f, 0x60, 0xc5, 0x73, 0x29, 0x75, 0xfd, 0xa1, 0x5e, 0xca, 0xdd,
+	0x3b, 0x16, 0x31, 0x8e, 0xe1, 0xd6, 0x57, 0x98, 0x4d, 0x44, 0x30, 0x3e, 0xc0, 0xde, 0xc4, 0xad,
+	0x65, 0xb5, 0x1c, 0xb1, 0xda, 0xf8, 0xa6, 0x8c, 0x7f, 0x68, 0xa0, 0xcf, 0x1a, 0x54, 0xa7, 0xfa,
+	0x78, 0x57, 0xf3, 0x78, 0xfa, 0x90, 0xb9, 0xae, 0x25, 0x27, 0x7d, 0x01, 0x9b, 0x87, 0x02, 0x81,
+	0x67, 0x9e, 0x15, 0x75, 0xde, 0x7b, 0xb0, 0x22, 0x91, 0x91, 0x2a, 0xbf, 0x26, 0xa0, 0x31, 0xe6,
+	0x19, 0x1e, 0xdc, 0x96, 0x76, 0xe2, 0x2d, 0xf6, 0x7d, 0xe7, 0x88, 0xf4, 0x73, 0xc2, 0xa6, 0x15,
+	0x0a, 0x1b, 0x4f, 0xf0, 0x81, 0x4d, 0x07, 0xb6, 0x83, 0xe3, 0x04, 0x57, 0x4b, 0xe3, 0x5f, 0x5a,
+	0xec, 0xf7, 0xcc, 0x7e, 0x2a, 0xaa, 0xc7, 0xa9, 0x66, 0x81, 0xca, 0x57, 0x6b, 0x4e, 0x95, 0xa5,
+	0x1f, 0xb8, 0x71, 0x47, 0xa1, 0x08, 0xe8, 0x10, 0x6a, 0x3c, 0xf7, 0x62, 0x53, 0xa5, 0xe2, 0xa6,
+	0xe0, 0x82, 0xf4, 0xd5, 0x6f, 0xe3, 0x47, 0x0d, 0x9a, 0xaf, 0x6c, 0x37, 0xc9, 0x04, 0x79, 0x06,
+	0xe7, 0x83, 0xa2, 0xf4, 0x18, 0x6e, 0x30, 0xd7, 0xc3, 0x24, 0x62, 0x96, 0xe7, 0x8e, 0x46, 0x2e,
+	0xc5, 0x03, 0xe2, 0x3b, 0x54, 0xbd, 0xbd, 0xd7, 0x15, 0xef, 0x65, 0x8a, 0x65, 0xfc, 0x53, 0x83,
+	0xcd, 0xd3, 0xa8, 0x4f, 0x07, 0xa1, 0xdb, 0xc7, 0x99, 0x69, 0x7e, 0x1f, 0xd6, 0x5d, 0x7f, 0x30,
+	0x8a, 0x1c, 0x9e, 0x93, 0x2e, 0x73, 0xed, 0x91, 0x70, 0xa8, 0x62, 0xd6, 0x15, 0xb9, 0x2b, 0xa9,
+	0x68, 0x37, 0xc6, 0x42, 0x19, 0x91, 0xdb, 0x39, 0x11, 0x39, 0xe5, 0x32, 0x31, 0x52, 0xee, 0xc1,
+	0xc6, 0x80, 0xd8, 0x23, 0x4c, 0x07, 0x78, 0xd2, 0x65, 0xf9, 0x10, 0xdc, 0x88, 0x99, 0x13, 0x3e,
+	0xff, 0x57, 0x03, 0xfd, 0x1b, 0x97, 0x66, 0x57, 0x65, 0xe2, 0x85, 0x56, 0xdc, 0x8b, 0x2d, 0xa8,
+	0xf1, 0x87, 0xcd, 0x0a, 0x42, 0x7c, 0xee, 0xc6, 0x8d, 0x0f, 0x70, 0x52, 0x4f, 0x50, 0xd0, 0x21,
+	0x54, 0x48, 0xe8, 0xe0, 0xd0, 0xea, 0x5f, 0xaa, 0x27, 0xf4, 0x41, 0x81, 0x9a, 0xa4, 0x27, 0x5c,
+	0xc7, 0x5c, 0x11, 0xaa, 0xcf, 0x2f, 0xf9, 0x63, 0x31, 0x72, 0x79, 0x67, 0x21, 0x9b, 0x1a, 0xb9,
+	0xe0, 0x1d, 0x61, 0x0a, 0x40, 0x97, 0x64, 0x47, 0x18, 0x24, 0xe0, 0x79, 0x3c, 0x79, 0x56, 0xfe,
+	0xd8, 0x7e, 0xc8, 0x59, 0x8d, 0x3b, 0xb0, 0x36, 0x61, 0x0b, 0x21, 0x58, 0x54, 0xaf, 0x7a, 0x79,
+	0xbb, 0x6a, 0x8a, 0xdf, 0xc6, 0xdf, 0x35, 0xa8, 0xc7, 0x4d, 0xc1, 0x01, 0x89, 0x7c, 0x46, 0x51,
+	0x17, 0x96, 0x07, 0xe2, 0x97, 0x2a, 0xfe, 0xc7, 0xf9, 0x78, 0x9b, 0x52, 0x6b, 0xcb, 0x7f, 0x1d,
+	0x9f, 0x85, 0x97, 0xa6, 0x32, 0xd0, 0x7c, 0x0a, 0xb5, 0x14, 0x19, 0x35, 0xa0, 0xfc, 0x06, 0x5f,
+	0xaa, 0xae, 0x82, 0xff, 0xe4, 0x81, 0x7a, 0x6b, 0x8f, 0x22, 0xac, 0x12, 0x57, 0x2e, 0x9e, 0x95,
+	0xbe, 0xd0, 0x8c, 0x77, 0x50, 0xeb, 0x11, 0x47, 0x68, 0xe3, 0x90, 0xce, 0x0c, 0x34, 0x5a, 0x91,
+	0x81, 0xa6, 0x54, 0x60, 0xa0, 0x29, 0x4f, 0x0f, 0x34, 0xc6, 0xd7, 0xbc, 0x2b, 0xf2, 0x87, 0x09,
+	0xaa, 0x7c, 0x01, 0x10, 0x92, 0xc8, 0x77, 0x2c, 0x16, 0xba, 0x81, 0xba, 0x80, 0x4f, 0x66, 0x9a,
+	0x86, 0x43, 0x35, 0xaf, 0x9b, 0x55, 0x21, 0x7c, 0x16, 0xba, 0x81, 0xb1, 0x05, 0x4b, 0xe2, 0x46,
+	0xd0, 0x4d, 0x58, 0xf6, 0x23, 0xaf, 0x8f, 0x43, 0xe5, 0xb7, 0x5a, 0x3d, 0xfc, 0x9b, 0x06, 0x68,
+	0x36, 0x8f, 0xd0, 0x26, 0x7c, 0xd2, 0xeb, 0xf6, 0x3a, 0xdf, 0x74, 0x8f, 0x3b, 0x56, 0xf7, 0xf8,
+	0xc5, 0xc9, 0xa9, 0x75, 0x62, 0x1e, 0x76, 0x4c, 0xeb, 0xf8, 0xe4, 0xb8, 0xd3, 0x58, 0x40, 0xf7,
+	0xe1, 0x4e, 0x26, 0xfb, 0xc0, 0xec, 0xec, 0x9f, 0x75, 0x0e, 0xad, 0xfd, 0x33, 0x6b, 0xff, 0xf4,
+	0xa0, 0xa1, 0xa1, 0x6d, 0xb8, 0x3b, 0x4f, 0xf0, 0xb0, 0x73, 0x7a, 0xd0, 0x28, 0xed, 0xfe, 0xfb,
+	0x16, 0x94, 0xf7, 0x7b, 0x5d, 0xf4, 0x3b, 0x58, 0x3b, 0x10, 0x2d, 0x4f, 0xfc, 0x05, 0x60, 0xce,
+	0x63, 0xdb, 0x9c, 0xc3, 0x37, 0x16, 0xb8, 0xc9, 0xae, 0x17, 0x90, 0x90, 0x7d, 0x3c, 0x93, 0x3d,
+	0x80, 0xae, 0x4f, 0x03, 0x3c, 0xe0, 0x36, 0x51, 0x6b, 0x4a, 0x7e, 0xcc, 0x52, 0xd5, 0x53, 0xc0,
+	0xe2, 0x39, 0xd4, 0x52, 0xcd, 0x07, 0xca, 0xab, 0xf9, 0xd9, 0xe6, 0xa6, 0xf9, 0xb0, 0x88, 0xa8,
+	0xcc, 0x24, 0xe1, 0xf9, 0x2a, 0xaf, 0xf1, 0x64, 0xa3, 0xcd, 0x29, 0x6d, 0xc5, 0x8c, 0x8d, 0x6f,
+	0x5d, 0xed, 0x38, 0x35, 0x16, 0xd0, 0x2b, 0x40, 0x69, 0x8b, 0xa7, 0x2c, 0xc4, 0xb6, 0x37, 0xcf,
+	0xee, 0xdc, 0x80, 0x7c, 0xa6, 0xa1, 0x97, 0x50, 0xef, 0xbc, 0x4b, 0xdd, 0x1b, 0x45, 0x79, 0xef,
+	0x52, 0x21, 0x73, 0x36, 0xd4, 0x27, 0xe7, 0x5d, 0xf4, 0x28, 0x47, 0x2b, 0x73, 0x2c, 0x2e, 0x12,
+	0x8a, 0x6f, 0x01, 0xed, 0x0f, 0x87, 0x21, 0x1e, 0xca, 0xfc, 0x15, 0xe0, 0x74, 0x85, 0xd7, 0xf7,
+	0x0a, 0xe1, 0x9a, 0xb1, 0x80, 0x22, 0xd1, 0xc8, 0x65, 0xce, 0xe0, 0xe8, 0xf3, 0xf9, 0xd7, 0x9f,
+	0x35, 0xb4, 0x17, 0x39, 0xce, 0x11, 0xac, 0x4d, 0xb4, 0x69, 0x28, 0xa3, 0x7d, 0x6d, 0x16, 0xe9,
+	0x46, 0xc4, 0x11, 0x6e, 0x66, 0xb7, 0x7c, 0xe8, 0x49, 0x8e, 0x81, 0x2b, 0x3b, 0xc4, 0xa2, 0xdb,
+	0xfe, 0x09, 0xd6, 0xa7, 0x46, 0x78, 0x74, 0x73, 0x06, 0x39, 0x3b, 0xfc, 0x4b, 0x66, 0xb3, 0x9d,
+	0x9b, 0x0d, 0x99, 0x9f, 0x00, 0x8c, 0x05, 0xf4, 0x1d, 0xac, 0x27, 0x58, 0xa5, 0x06, 0xfe, 0x56,
+	0x7e, 0x54, 0xa5, 0x44, 0x51, 0xc7, 0xff, 0x00, 0xf5, 0xc4, 0xb6, 0x1c, 0xfb, 0xb7, 0xe6, 0x64,
+	0x4b, 0x51, 0xcb, 0x7f, 0x04, 0x34, 0x9e, 0xf7, 0x13, 0xeb, 0x0f, 0xe6, 0x58, 0x1f, 0xab, 0x34,
+	0x73, 0x02, 0x68, 0x2c, 0xa0, 0x67, 0x00, 0xa6, 0xfc, 0xe0, 0xc5, 0x61, 0x31, 0x2b, 0x5b, 0xf2,
+	0x75, 0xbf, 0x07, 0x24, 0x0f, 0x3c, 0xf9, 0x41, 0xa0, 0x40, 0xef, 0xd3, 0x2c, 0x22, 0x24, 0xde,
+	0x81, 0xf5, 0xa9, 0x71, 0x28, 0xbf, 0x34, 0x0b, 0x9a, 0xfc, 0x1e, 0xd0, 0xef, 0xc5, 0x77, 0x9f,
+	0x9f, 0xcd, 0xe9, 0x08, 0x1a, 0x53, 0x4e, 0x53, 0xd4, 0xce, 0x2f, 0xf9, 0xac, 0x3e, 0xb5, 0xb9,
+	0x53, 0x58, 0x3e, 0x49, 0xed, 0x11, 0x5c, 0x9b, 0x69, 0x7b, 0x51, 0x9e, 0x9d, 0xbc, 0x06, 0xb9,
+	0x79, 0xb7, 0xc0, 0x19, 0xe9, 0xec, 0x6e, 0xb2, 0x59, 0x2c, 0xb2, 0x5b, 0xba, 0x45, 0x9d, 0xbb,
+	0x9b, 0x10, 0x36, 0x16, 0xd0, 0x19, 0xa0, 0xc9, 0x29, 0xee, 0xfd, 0x52, 0x61, 0xaa, 0xac, 0x7e,
+	0xd4, 0x60, 0x23, 0x73, 0x38, 0x44, 0x7b, 0x57, 0x02, 0x5c, 0xf6, 0xe8, 0xda, 0x7c, 0xf2, 0xd3,
+	0x94, 0x92, 0x8b, 0xfb, 0x0b, 0xdc, 0xcc, 0x9e, 0xb1, 0x72, 0x71, 0xf6, 0xca, 0x91, 0xac, 0x59,
+	0x64, 0xf8, 0x90, 0x9f, 0x96, 0xc4, 0x23, 0xdb, 0x87, 0xeb, 0x19, 0x93, 0x26, 0xca, 0xeb, 0xe0,
+	0xf3, 0xa7, 0xd2, 0x2b, 0x90, 0xe2, 0xb7, 0x50, 0x11, 0x1d, 0x77, 0x8f, 0x38, 0x99, 0x18, 0x33,
+	0xbf, 0xd9, 0x7a, 0x0e, 0xa0, 0xda, 0xf1, 0xf7, 0xb7, 0xf1, 0x25, 0xac, 0xf0, 0x76, 0xfd, 0xfd,
+	0x0d, 0x1c, 0x41, 0x9d, 0x17, 0x60, 0x6a, 0xc4, 0xc8, 0xb2, 0x63, 0xe4, 0xc5, 0x7f, 0xac, 0x27,
+	0x42, 0xd2, 0x30, 0x31, 0x9d, 0x6f, 0x2d, 0x3f, 0xa8, 0x1d, 0x58, 0xe4, 0x33, 0x47, 0xee, 0xeb,
+	0x98, 0x0f, 0x57, 0xe3, 0x41, 0xc5, 0x58, 0x78, 0x5e, 0xfd, 0x6e, 0x45, 0x71, 0xfa, 0xcb, 0xc2,
+	0xc2, 0xde, 0xff, 0x07, 0x00, 0x5c, 0x6d, 0x69, 0x65, 0x92, 0x1c, 0x00, 0x00,
 }
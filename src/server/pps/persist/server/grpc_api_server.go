@@ -0,0 +1,193 @@
+package server
+
+import (
+	ppsclient "github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pps/persist"
+	"go.pedge.io/pb/go/google/protobuf"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// NewGRPCAPIServer returns apiServer as it should be registered with a
+// grpc.Server: its ValidationErrors are sent as codes.InvalidArgument, with
+// the field in front of the message, the vendored grpc would otherwise send
+// them as codes.Unknown. The vendored grpc.Server takes no interceptors,
+// which would otherwise be the place for this.
+func NewGRPCAPIServer(apiServer persist.APIServer) persist.APIServer {
+	return &grpcAPIServer{apiServer}
+}
+
+type grpcAPIServer struct {
+	apiServer persist.APIServer
+}
+
+// toGRPCError returns err as gRPC clients should see it.
+func toGRPCError(err error) error {
+	if validationErr, ok := err.(*ValidationError); ok {
+		return grpc.Errorf(codes.InvalidArgument, "%s: %s", validationErr.Field, validationErr.Message)
+	}
+	return err
+}
+
+func (s *grpcAPIServer) CreateJobInfo(ctx context.Context, request *persist.JobInfo) (*persist.JobInfo, error) {
+	response, err := s.apiServer.CreateJobInfo(ctx, request)
+	return response, toGRPCError(err)
+}
+
+func (s *grpcAPIServer) ImportJobInfo(ctx context.Context, request *persist.JobInfo) (*persist.JobInfo, error) {
+	response, err := s.apiServer.ImportJobInfo(ctx, request)
+	return response, toGRPCError(err)
+}
+
+func (s *grpcAPIServer) InspectJob(ctx context.Context, request *ppsclient.InspectJobRequest) (*persist.JobInfo, error) {
+	response, err := s.apiServer.InspectJob(ctx, request)
+	return response, toGRPCError(err)
+}
+
+func (s *grpcAPIServer) GetJobInfos(ctx context.Context, request *persist.GetJobInfosRequest) (*persist.GetJobInfosResponse, error) {
+	response, err := s.apiServer.GetJobInfos(ctx, request)
+	return response, toGRPCError(err)
+}
+
+func (s *grpcAPIServer) ListJobInfos(ctx context.Context, request *ppsclient.ListJobRequest) (*persist.JobInfos, error) {
+	response, err := s.apiServer.ListJobInfos(ctx, request)
+	return response, toGRPCError(err)
+}
+
+func (s *grpcAPIServer) ListJobInfosStream(request *ppsclient.ListJobRequest, server persist.API_ListJobInfosStreamServer) error {
+	return toGRPCError(s.apiServer.ListJobInfosStream(request, server))
+}
+
+func (s *grpcAPIServer) ExportJobInfos(request *ppsclient.Pipeline, server persist.API_ExportJobInfosServer) error {
+	return toGRPCError(s.apiServer.ExportJobInfos(request, server))
+}
+
+func (s *grpcAPIServer) RecentJobInfos(ctx context.Context, request *persist.RecentJobInfosRequest) (*persist.JobInfos, error) {
+	response, err := s.apiServer.RecentJobInfos(ctx, request)
+	return response, toGRPCError(err)
+}
+
+func (s *grpcAPIServer) AggregateJobStates(ctx context.Context, request *ppsclient.Pipeline) (*persist.JobStateCounts, error) {
+	response, err := s.apiServer.AggregateJobStates(ctx, request)
+	return response, toGRPCError(err)
+}
+
+func (s *grpcAPIServer) GetJobInfosByCommitRange(ctx context.Context, request *persist.GetJobInfosByCommitRangeRequest) (*persist.JobInfos, error) {
+	response, err := s.apiServer.GetJobInfosByCommitRange(ctx, request)
+	return response, toGRPCError(err)
+}
+
+func (s *grpcAPIServer) DeleteJobInfo(ctx context.Context, request *ppsclient.Job) (*persist.WriteSummary, error) {
+	response, err := s.apiServer.DeleteJobInfo(ctx, request)
+	return response, toGRPCError(err)
+}
+
+func (s *grpcAPIServer) DeleteJobInfosByCommit(ctx context.Context, request *persist.DeleteJobInfosByCommitRequest) (*persist.WriteSummary, error) {
+	response, err := s.apiServer.DeleteJobInfosByCommit(ctx, request)
+	return response, toGRPCError(err)
+}
+
+func (s *grpcAPIServer) ReindexJobInfos(ctx context.Context, request *google_protobuf.Empty) (*persist.ReindexJobInfosResponse, error) {
+	response, err := s.apiServer.ReindexJobInfos(ctx, request)
+	return response, toGRPCError(err)
+}
+
+func (s *grpcAPIServer) CreateJobOutput(ctx context.Context, request *persist.JobOutput) (*persist.WriteSummary, error) {
+	response, err := s.apiServer.CreateJobOutput(ctx, request)
+	return response, toGRPCError(err)
+}
+
+func (s *grpcAPIServer) CreateJobState(ctx context.Context, request *persist.JobState) (*persist.WriteSummary, error) {
+	response, err := s.apiServer.CreateJobState(ctx, request)
+	return response, toGRPCError(err)
+}
+
+func (s *grpcAPIServer) TransitionJobState(ctx context.Context, request *persist.JobStateTransition) (*google_protobuf.Empty, error) {
+	response, err := s.apiServer.TransitionJobState(ctx, request)
+	return response, toGRPCError(err)
+}
+
+func (s *grpcAPIServer) RestartJob(ctx context.Context, request *ppsclient.Job) (*google_protobuf.Empty, error) {
+	response, err := s.apiServer.RestartJob(ctx, request)
+	return response, toGRPCError(err)
+}
+
+func (s *grpcAPIServer) CreatePipelineInfo(ctx context.Context, request *persist.PipelineInfo) (*persist.PipelineInfo, error) {
+	response, err := s.apiServer.CreatePipelineInfo(ctx, request)
+	return response, toGRPCError(err)
+}
+
+func (s *grpcAPIServer) GetPipelineInfo(ctx context.Context, request *ppsclient.Pipeline) (*persist.PipelineInfo, error) {
+	response, err := s.apiServer.GetPipelineInfo(ctx, request)
+	return response, toGRPCError(err)
+}
+
+func (s *grpcAPIServer) UpdatePipelineInfo(ctx context.Context, request *persist.PipelineInfo) (*persist.PipelineInfo, error) {
+	response, err := s.apiServer.UpdatePipelineInfo(ctx, request)
+	return response, toGRPCError(err)
+}
+
+func (s *grpcAPIServer) GetPipelineInfos(ctx context.Context, request *persist.GetPipelineInfosRequest) (*persist.GetPipelineInfosResponse, error) {
+	response, err := s.apiServer.GetPipelineInfos(ctx, request)
+	return response, toGRPCError(err)
+}
+
+func (s *grpcAPIServer) ListPipelineInfos(ctx context.Context, request *persist.ListPipelineInfosRequest) (*persist.PipelineInfos, error) {
+	response, err := s.apiServer.ListPipelineInfos(ctx, request)
+	return response, toGRPCError(err)
+}
+
+func (s *grpcAPIServer) ListPipelineNames(ctx context.Context, request *persist.ListPipelineNamesRequest) (*persist.PipelineNames, error) {
+	response, err := s.apiServer.ListPipelineNames(ctx, request)
+	return response, toGRPCError(err)
+}
+
+func (s *grpcAPIServer) DeletePipelineInfo(ctx context.Context, request *ppsclient.Pipeline) (*persist.WriteSummary, error) {
+	response, err := s.apiServer.DeletePipelineInfo(ctx, request)
+	return response, toGRPCError(err)
+}
+
+func (s *grpcAPIServer) DeletePipelineAndJobs(ctx context.Context, request *persist.DeletePipelineAndJobsRequest) (*persist.DeletePipelineAndJobsResponse, error) {
+	response, err := s.apiServer.DeletePipelineAndJobs(ctx, request)
+	return response, toGRPCError(err)
+}
+
+func (s *grpcAPIServer) SubscribePipelineInfos(request *persist.SubscribePipelineInfosRequest, server persist.API_SubscribePipelineInfosServer) error {
+	return toGRPCError(s.apiServer.SubscribePipelineInfos(request, server))
+}
+
+func (s *grpcAPIServer) WaitPipelineDeleted(ctx context.Context, request *persist.WaitPipelineDeletedRequest) (*google_protobuf.Empty, error) {
+	response, err := s.apiServer.WaitPipelineDeleted(ctx, request)
+	return response, toGRPCError(err)
+}
+
+func (s *grpcAPIServer) StartPod(ctx context.Context, request *ppsclient.Job) (*persist.JobInfo, error) {
+	response, err := s.apiServer.StartPod(ctx, request)
+	return response, toGRPCError(err)
+}
+
+func (s *grpcAPIServer) SucceedPod(ctx context.Context, request *ppsclient.Job) (*persist.JobInfo, error) {
+	response, err := s.apiServer.SucceedPod(ctx, request)
+	return response, toGRPCError(err)
+}
+
+func (s *grpcAPIServer) FailPod(ctx context.Context, request *ppsclient.Job) (*persist.JobInfo, error) {
+	response, err := s.apiServer.FailPod(ctx, request)
+	return response, toGRPCError(err)
+}
+
+func (s *grpcAPIServer) GetPodCounters(ctx context.Context, request *ppsclient.Job) (*persist.PodCounters, error) {
+	response, err := s.apiServer.GetPodCounters(ctx, request)
+	return response, toGRPCError(err)
+}
+
+func (s *grpcAPIServer) ResetPodCounters(ctx context.Context, request *ppsclient.Job) (*google_protobuf.Empty, error) {
+	response, err := s.apiServer.ResetPodCounters(ctx, request)
+	return response, toGRPCError(err)
+}
+
+func (s *grpcAPIServer) Ping(ctx context.Context, request *google_protobuf.Empty) (*persist.PingResponse, error) {
+	response, err := s.apiServer.Ping(ctx, request)
+	return response, toGRPCError(err)
+}
@@ -0,0 +1,949 @@
+package server
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pachyderm/pachyderm/src/client/pfs"
+	ppsclient "github.com/pachyderm/pachyderm/src/client/pps"
+	"github.com/pachyderm/pachyderm/src/server/pps/persist"
+
+	"go.pedge.io/lion/proto"
+	"go.pedge.io/pb/go/google/protobuf"
+	"go.pedge.io/pkg/time"
+	"go.pedge.io/proto/rpclog"
+	"go.pedge.io/proto/time"
+	"golang.org/x/net/context"
+)
+
+// memoryAPIServer keeps jobs and pipelines in maps, it validates requests
+// and filters the same way the rethink server's queries and indexes do, so
+// that tests can use it in place of a RethinkDB.
+type memoryAPIServer struct {
+	protorpclog.Logger
+	opts  Options
+	timer pkgtime.Timer
+	// jobInfos is keyed by JobID and pipelineInfos by PipelineName, the
+	// primary keys of the rethink tables.
+	jobInfos      map[string]*persist.JobInfo
+	pipelineInfos map[string]*persist.PipelineInfo
+	// changed is closed and replaced on every write, it's what blocking
+	// calls wait on in place of a changefeed.
+	changed  chan struct{}
+	watchers map[*pipelineWatcher]bool
+	// subscriptions counts running SubscribePipelineInfos and
+	// WaitPipelineDeleted calls, drain is closed to tell them to stop.
+	subscriptions sync.WaitGroup
+	drain         chan struct{}
+	draining      bool
+	lock          sync.Mutex
+}
+
+// pipelineWatcher holds the pipeline changes a SubscribePipelineInfos call
+// hasn't sent yet, up to buffer of them. behind is set if there were more.
+type pipelineWatcher struct {
+	shard   *persist.Shard
+	buffer  int
+	pending []*persist.PipelineInfoChange
+	behind  bool
+}
+
+func newMemoryAPIServer(opts *Options) *memoryAPIServer {
+	if opts == nil {
+		opts = &Options{}
+	}
+	a := &memoryAPIServer{
+		Logger:        protorpclog.NewLogger("pachyderm.ppsclient.persist.API"),
+		opts:          *opts,
+		timer:         pkgtime.NewSystemTimer(),
+		jobInfos:      make(map[string]*persist.JobInfo),
+		pipelineInfos: make(map[string]*persist.PipelineInfo),
+		changed:       make(chan struct{}),
+		watchers:      make(map[*pipelineWatcher]bool),
+		drain:         make(chan struct{}),
+	}
+	if opts.JobRetention != 0 {
+		go a.purgeJobInfos()
+	}
+	return a
+}
+
+// Close ends subscriptions without waiting for them.
+func (a *memoryAPIServer) Close() error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.stop()
+	return nil
+}
+
+// Drain stops new subscriptions, ends the running ones and waits for them to
+// return, or for ctx to be done.
+func (a *memoryAPIServer) Drain(ctx context.Context) error {
+	a.lock.Lock()
+	a.stop()
+	a.lock.Unlock()
+	done := make(chan struct{})
+	go func() {
+		a.subscriptions.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+	return ctx.Err()
+}
+
+// stop must be called with a.lock held.
+func (a *memoryAPIServer) stop() {
+	if !a.draining {
+		a.draining = true
+		close(a.drain)
+	}
+}
+
+func (a *memoryAPIServer) addSubscription() error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.draining {
+		return ErrDraining
+	}
+	a.subscriptions.Add(1)
+	return nil
+}
+
+// Timestamp cannot be set
+func (a *memoryAPIServer) CreateJobInfo(ctx context.Context, request *persist.JobInfo) (response *persist.JobInfo, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	if request.CreatedAt != nil {
+		return nil, newValidationError("CreatedAt", "request.CreatedAt should be unset")
+	}
+	request.CreatedAt = a.now()
+	request.UpdatedAt = request.CreatedAt
+	return a.createJobInfo(request)
+}
+
+// Timestamp must be set, and the server must allow imports
+func (a *memoryAPIServer) ImportJobInfo(ctx context.Context, request *persist.JobInfo) (response *persist.JobInfo, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	if !a.opts.AllowImport {
+		return nil, ErrImportNotAllowed
+	}
+	if request.CreatedAt == nil {
+		return nil, newValidationError("CreatedAt", "request.CreatedAt should be set")
+	}
+	if request.UpdatedAt == nil {
+		request.UpdatedAt = request.CreatedAt
+	}
+	return a.createJobInfo(request)
+}
+
+func (a *memoryAPIServer) createJobInfo(request *persist.JobInfo) (*persist.JobInfo, error) {
+	if request.JobID == "" {
+		return nil, newValidationError("JobID", "request.JobID should be set")
+	}
+	if request.CommitIndex != "" {
+		return nil, newValidationError("CommitIndex", "request.CommitIndex should be unset")
+	}
+	var err error
+	request.CommitIndex, err = jobCommitIndex(request)
+	if err != nil {
+		return nil, err
+	}
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if existing, ok := a.jobInfos[request.JobID]; ok {
+		if a.opts.IdempotentCreate && sameJobInfo(existing, request) {
+			return cloneJobInfo(existing), nil
+		}
+		return nil, ErrJobExists
+	}
+	a.jobInfos[request.JobID] = cloneJobInfo(request)
+	a.notify()
+	return request, nil
+}
+
+func (a *memoryAPIServer) InspectJob(ctx context.Context, request *ppsclient.InspectJobRequest) (response *persist.JobInfo, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	if request.Job == nil {
+		return nil, fmt.Errorf("request.Job cannot be nil")
+	}
+	for {
+		a.lock.Lock()
+		jobInfo, ok := a.jobInfos[request.Job.ID]
+		if ok {
+			jobInfo = cloneJobInfo(jobInfo)
+		}
+		changed := a.changed
+		a.lock.Unlock()
+		if !ok {
+			return nil, ErrJobNotFound
+		}
+		if !request.BlockState || jobInfo.State != ppsclient.JobState_JOB_STATE_RUNNING {
+			return jobInfo, nil
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (a *memoryAPIServer) GetJobInfos(ctx context.Context, request *persist.GetJobInfosRequest) (response *persist.GetJobInfosResponse, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	response = &persist.GetJobInfosResponse{}
+	for _, job := range request.Job {
+		if jobInfo, ok := a.jobInfos[job.ID]; ok {
+			response.JobInfo = append(response.JobInfo, cloneJobInfo(jobInfo))
+		} else {
+			response.Missing = append(response.Missing, job)
+		}
+	}
+	return response, nil
+}
+
+func (a *memoryAPIServer) ListJobInfos(ctx context.Context, request *ppsclient.ListJobRequest) (response *persist.JobInfos, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	jobInfos, err := a.listJobInfos(request)
+	if err != nil {
+		return nil, err
+	}
+	return &persist.JobInfos{
+		JobInfo:   jobInfos,
+		NextToken: nextSinceToken(request.SinceToken, jobInfos),
+	}, nil
+}
+
+// listJobInfos returns request's jobs latest to earliest, it's the in memory
+// version of the rethink server's listJobInfosQuery.
+func (a *memoryAPIServer) listJobInfos(request *ppsclient.ListJobRequest) ([]*persist.JobInfo, error) {
+	commitIndexVal, err := genCommitIndex(request.InputCommit)
+	if err != nil {
+		return nil, err
+	}
+	var since *google_protobuf.Timestamp
+	if request.SinceToken != "" {
+		sinceKey, err := parseSinceToken(request.SinceToken)
+		if err != nil {
+			return nil, err
+		}
+		since = &google_protobuf.Timestamp{Seconds: sinceKey[0].(int64), Nanos: sinceKey[1].(int32)}
+	}
+	pipelineNames := make(map[string]bool)
+	if request.Pipeline != nil {
+		pipelineNames[request.Pipeline.Name] = true
+	}
+	for _, pipeline := range request.Pipelines {
+		pipelineNames[pipeline.Name] = true
+	}
+	if request.PipelineVersion != 0 && len(pipelineNames) == 0 {
+		return nil, newValidationError("PipelineVersion", "request.PipelineVersion can only be set with request.Pipeline or request.Pipelines")
+	}
+	states := make(map[ppsclient.JobState]bool)
+	for _, state := range request.State {
+		states[state] = true
+	}
+	excludePipelineNames := make(map[string]bool)
+	for _, pipeline := range request.ExcludePipelines {
+		excludePipelineNames[pipeline.Name] = true
+	}
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	var result []*persist.JobInfo
+	for _, jobInfo := range a.jobInfos {
+		switch {
+		case len(pipelineNames) > 0 && !pipelineNames[jobInfo.PipelineName]:
+		case request.PipelineVersion != 0 && jobInfo.PipelineVersion != request.PipelineVersion:
+		case len(request.InputCommit) > 0 && jobInfo.CommitIndex != commitIndexVal:
+		case len(states) > 0 && !states[jobInfo.State]:
+		case request.CreatedAfter != nil && prototime.TimestampLess(jobInfo.CreatedAt, request.CreatedAfter):
+		case since != nil && !prototime.TimestampLess(since, jobInfo.UpdatedAt):
+		case request.HasOutput && jobInfo.OutputCommit == nil:
+		case excludePipelineNames[jobInfo.PipelineName]:
+		default:
+			result = append(result, jobInfo)
+		}
+	}
+	if request.LatestPerPipeline {
+		latest := make(map[string]*persist.JobInfo)
+		for _, jobInfo := range result {
+			if other, ok := latest[jobInfo.PipelineName]; !ok || prototime.TimestampLess(other.CreatedAt, jobInfo.CreatedAt) {
+				latest[jobInfo.PipelineName] = jobInfo
+			}
+		}
+		result = nil
+		for _, jobInfo := range latest {
+			result = append(result, jobInfo)
+		}
+	}
+	sortJobInfosByTimestampDesc(result)
+	for i, jobInfo := range result {
+		result[i] = cloneJobInfo(jobInfo)
+	}
+	return result, nil
+}
+
+func (a *memoryAPIServer) ListJobInfosStream(request *ppsclient.ListJobRequest, server persist.API_ListJobInfosStreamServer) (retErr error) {
+	defer func(start time.Time) { a.Log(request, nil, retErr, time.Since(start)) }(time.Now())
+	jobInfos, err := a.listJobInfos(request)
+	if err != nil {
+		return err
+	}
+	for _, jobInfo := range jobInfos {
+		if err := server.Send(jobInfo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *memoryAPIServer) ExportJobInfos(request *ppsclient.Pipeline, server persist.API_ExportJobInfosServer) (retErr error) {
+	defer func(start time.Time) { a.Log(request, nil, retErr, time.Since(start)) }(time.Now())
+	if request.Name == "" {
+		return newValidationError("Name", "request.Name should be set")
+	}
+	jobInfos, err := a.listJobInfos(&ppsclient.ListJobRequest{Pipeline: request})
+	if err != nil {
+		return err
+	}
+	ctx := server.Context()
+	for _, jobInfo := range jobInfos {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := server.Send(jobInfo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *memoryAPIServer) RecentJobInfos(ctx context.Context, request *persist.RecentJobInfosRequest) (response *persist.JobInfos, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	if request.N == 0 {
+		// a Limit of 0 would return nothing, which can't be what was meant
+		return nil, newValidationError("N", "request.N should be set")
+	}
+	jobInfos, err := a.listJobInfos(&ppsclient.ListJobRequest{})
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(jobInfos)) > request.N {
+		jobInfos = jobInfos[:request.N]
+	}
+	return &persist.JobInfos{JobInfo: jobInfos}, nil
+}
+
+func (a *memoryAPIServer) AggregateJobStates(ctx context.Context, request *ppsclient.Pipeline) (response *persist.JobStateCounts, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	response = &persist.JobStateCounts{Counts: make(map[string]uint64)}
+	for _, jobInfo := range a.jobInfos {
+		if request.Name == "" || jobInfo.PipelineName == request.Name {
+			response.Counts[jobInfo.State.String()]++
+		}
+	}
+	return response, nil
+}
+
+func (a *memoryAPIServer) GetJobInfosByCommitRange(ctx context.Context, request *persist.GetJobInfosByCommitRangeRequest) (response *persist.JobInfos, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	response = &persist.JobInfos{}
+	for _, jobInfo := range a.jobInfos {
+		if jobInfo.CommitIndex < request.StartCommitIndex {
+			continue
+		}
+		if request.EndCommitIndex != "" && jobInfo.CommitIndex >= request.EndCommitIndex {
+			continue
+		}
+		response.JobInfo = append(response.JobInfo, cloneJobInfo(jobInfo))
+	}
+	sort.Sort(jobInfosByCommitIndex(response.JobInfo))
+	return response, nil
+}
+
+func (a *memoryAPIServer) DeleteJobInfo(ctx context.Context, request *ppsclient.Job) (response *persist.WriteSummary, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	response = &persist.WriteSummary{}
+	if _, ok := a.jobInfos[request.ID]; ok {
+		delete(a.jobInfos, request.ID)
+		response.Deleted++
+		a.notify()
+	}
+	return response, nil
+}
+
+func (a *memoryAPIServer) DeleteJobInfosByCommit(ctx context.Context, request *persist.DeleteJobInfosByCommitRequest) (response *persist.WriteSummary, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	if len(request.Commits) == 0 {
+		return nil, newValidationError("Commits", "request.Commits should be set")
+	}
+	commitIndexVal, err := genCommitIndex(request.Commits)
+	if err != nil {
+		return nil, err
+	}
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	response = &persist.WriteSummary{}
+	for jobID, jobInfo := range a.jobInfos {
+		if jobInfo.CommitIndex == commitIndexVal {
+			delete(a.jobInfos, jobID)
+			response.Deleted++
+		}
+	}
+	if response.Deleted > 0 {
+		a.notify()
+	}
+	return response, nil
+}
+
+// purgeJobInfos is the in memory version of the rethink server's
+// purgeJobInfos.
+func (a *memoryAPIServer) purgeJobInfos() {
+	ticker := time.NewTicker(a.opts.purgeInterval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-a.drain:
+			return
+		}
+		createdBefore := prototime.TimeToTimestamp(a.timer.Now().Add(-a.opts.JobRetention))
+		a.lock.Lock()
+		var purged uint64
+		for jobID, jobInfo := range a.jobInfos {
+			if prototime.TimestampLess(jobInfo.CreatedAt, createdBefore) {
+				delete(a.jobInfos, jobID)
+				purged++
+			}
+		}
+		if purged > 0 {
+			a.notify()
+		}
+		a.lock.Unlock()
+		if purged > 0 {
+			protolion.Infof("purged %d jobs created before %s", purged, prototime.TimestampToTime(createdBefore))
+		}
+	}
+}
+
+func (a *memoryAPIServer) ReindexJobInfos(ctx context.Context, request *google_protobuf.Empty) (response *persist.ReindexJobInfosResponse, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	response = &persist.ReindexJobInfosResponse{}
+	for _, jobInfo := range a.jobInfos {
+		commitIndex, err := jobCommitIndex(jobInfo)
+		if err != nil {
+			return nil, err
+		}
+		if commitIndex == jobInfo.CommitIndex {
+			continue
+		}
+		jobInfo.CommitIndex = commitIndex
+		jobInfo.UpdatedAt = a.now()
+		response.Updated++
+	}
+	if response.Updated > 0 {
+		a.notify()
+	}
+	return response, nil
+}
+
+func (a *memoryAPIServer) CreateJobOutput(ctx context.Context, request *persist.JobOutput) (response *persist.WriteSummary, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	return a.updateJobInfo(request.JobID, func(jobInfo *persist.JobInfo) bool {
+		if proto.Equal(jobInfo.OutputCommit, request.OutputCommit) {
+			return false
+		}
+		jobInfo.OutputCommit = nil
+		if request.OutputCommit != nil {
+			jobInfo.OutputCommit = proto.Clone(request.OutputCommit).(*pfs.Commit)
+		}
+		return true
+	})
+}
+
+func (a *memoryAPIServer) CreateJobState(ctx context.Context, request *persist.JobState) (response *persist.WriteSummary, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	return a.updateJobInfo(request.JobID, func(jobInfo *persist.JobInfo) bool {
+		if jobInfo.State == request.State {
+			return false
+		}
+		jobInfo.State = request.State
+		return true
+	})
+}
+
+func (a *memoryAPIServer) TransitionJobState(ctx context.Context, request *persist.JobStateTransition) (response *google_protobuf.Empty, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	jobInfo, ok := a.jobInfos[request.JobID]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	if jobInfo.State != request.From {
+		return nil, ErrJobStateMismatch
+	}
+	jobInfo.State = request.To
+	jobInfo.UpdatedAt = a.now()
+	a.notify()
+	return google_protobuf.EmptyInstance, nil
+}
+
+func (a *memoryAPIServer) RestartJob(ctx context.Context, request *ppsclient.Job) (response *google_protobuf.Empty, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	jobInfo, ok := a.jobInfos[request.ID]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	jobInfo.OutputCommit = nil
+	jobInfo.State = ppsclient.JobState_JOB_STATE_RUNNING
+	jobInfo.PodsStarted = 0
+	jobInfo.PodsSucceeded = 0
+	jobInfo.PodsFailed = 0
+	jobInfo.Restarts++
+	jobInfo.UpdatedAt = a.now()
+	a.notify()
+	return google_protobuf.EmptyInstance, nil
+}
+
+// timestamp cannot be set
+func (a *memoryAPIServer) CreatePipelineInfo(ctx context.Context, request *persist.PipelineInfo) (response *persist.PipelineInfo, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	if request.PipelineName == "" {
+		return nil, newValidationError("PipelineName", "request.PipelineName should be set")
+	}
+	if request.CreatedAt != nil {
+		return nil, ErrTimestampSet
+	}
+	request.CreatedAt = a.now()
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if _, ok := a.pipelineInfos[request.PipelineName]; ok {
+		return nil, fmt.Errorf("pipeline %s already exists", request.PipelineName)
+	}
+	request.Version = 1
+	a.pipelineInfos[request.PipelineName] = clonePipelineInfo(request)
+	a.pipelineChanged(&persist.PipelineInfoChange{Pipeline: request})
+	return request, nil
+}
+
+func (a *memoryAPIServer) UpdatePipelineInfo(ctx context.Context, request *persist.PipelineInfo) (response *persist.PipelineInfo, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	if request.PipelineName == "" {
+		return nil, newValidationError("PipelineName", "request.PipelineName should be set")
+	}
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	pipelineInfo, ok := a.pipelineInfos[request.PipelineName]
+	if !ok {
+		return nil, ErrPipelineNotFound
+	}
+	if pipelineInfo.Version != request.Version {
+		return nil, ErrPipelineVersionMismatch
+	}
+	update := clonePipelineInfo(request)
+	update.CreatedAt = pipelineInfo.CreatedAt
+	update.Version++
+	a.pipelineInfos[request.PipelineName] = update
+	a.pipelineChanged(&persist.PipelineInfoChange{Pipeline: update})
+	return clonePipelineInfo(update), nil
+}
+
+func (a *memoryAPIServer) GetPipelineInfo(ctx context.Context, request *ppsclient.Pipeline) (response *persist.PipelineInfo, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	pipelineInfo, ok := a.pipelineInfos[request.Name]
+	if !ok {
+		return nil, ErrPipelineNotFound
+	}
+	return clonePipelineInfo(pipelineInfo), nil
+}
+
+func (a *memoryAPIServer) GetPipelineInfos(ctx context.Context, request *persist.GetPipelineInfosRequest) (response *persist.GetPipelineInfosResponse, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	response = &persist.GetPipelineInfosResponse{}
+	for _, pipeline := range request.Pipeline {
+		if pipelineInfo, ok := a.pipelineInfos[pipeline.Name]; ok {
+			response.PipelineInfo = append(response.PipelineInfo, clonePipelineInfo(pipelineInfo))
+		} else {
+			response.Missing = append(response.Missing, pipeline)
+		}
+	}
+	return response, nil
+}
+
+// ListPipelineInfos returns pipelines in name order unless request.OrderBy
+// says otherwise, rethink doesn't promise any order in that case.
+func (a *memoryAPIServer) ListPipelineInfos(ctx context.Context, request *persist.ListPipelineInfosRequest) (response *persist.PipelineInfos, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	paged := request.Limit != 0 || request.PageToken != ""
+	if paged && request.OrderBy != persist.PipelineInfosOrder_PIPELINE_INFOS_ORDER_NONE {
+		return nil, newValidationError("OrderBy", "request.OrderBy can't be set with request.Limit or request.PageToken")
+	}
+	if err := a.opts.validateShard(request.Shard); err != nil {
+		return nil, err
+	}
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	response = &persist.PipelineInfos{}
+	for _, pipelineInfo := range a.sortedPipelineInfos() {
+		switch {
+		case request.Shard != nil && pipelineInfo.Shard != request.Shard.Number:
+		case request.PageToken != "" && pipelineInfo.PipelineName <= request.PageToken:
+		case !strings.HasPrefix(pipelineInfo.PipelineName, request.NamePrefix):
+		default:
+			response.PipelineInfo = append(response.PipelineInfo, clonePipelineInfo(pipelineInfo))
+		}
+	}
+	switch request.OrderBy {
+	case persist.PipelineInfosOrder_PIPELINE_INFOS_ORDER_CREATED_AT_ASC:
+		sort.Sort(sort.Reverse(pipelineInfosByTimestampDesc(response.PipelineInfo)))
+	case persist.PipelineInfosOrder_PIPELINE_INFOS_ORDER_CREATED_AT_DESC:
+		sortPipelineInfosByTimestampDesc(response.PipelineInfo)
+	}
+	if request.Limit != 0 && uint64(len(response.PipelineInfo)) >= request.Limit {
+		response.PipelineInfo = response.PipelineInfo[:request.Limit]
+		// there may be more, if not the next page is just empty
+		response.NextPageToken = response.PipelineInfo[len(response.PipelineInfo)-1].PipelineName
+	}
+	return response, nil
+}
+
+func (a *memoryAPIServer) ListPipelineNames(ctx context.Context, request *persist.ListPipelineNamesRequest) (response *persist.PipelineNames, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	if err := a.opts.validateShard(request.Shard); err != nil {
+		return nil, err
+	}
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	response = &persist.PipelineNames{}
+	for _, pipelineInfo := range a.sortedPipelineInfos() {
+		if request.Shard == nil || pipelineInfo.Shard == request.Shard.Number {
+			response.Name = append(response.Name, pipelineInfo.PipelineName)
+		}
+	}
+	return response, nil
+}
+
+func (a *memoryAPIServer) DeletePipelineInfo(ctx context.Context, request *ppsclient.Pipeline) (response *persist.WriteSummary, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	response = &persist.WriteSummary{}
+	if pipelineInfo, ok := a.pipelineInfos[request.Name]; ok {
+		delete(a.pipelineInfos, request.Name)
+		response.Deleted++
+		a.pipelineChanged(&persist.PipelineInfoChange{Pipeline: pipelineInfo, Removed: true})
+	}
+	return response, nil
+}
+
+func (a *memoryAPIServer) DeletePipelineAndJobs(ctx context.Context, request *persist.DeletePipelineAndJobsRequest) (response *persist.DeletePipelineAndJobsResponse, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	if request.Pipeline == nil {
+		return nil, newValidationError("Pipeline", "request.Pipeline should be set")
+	}
+	if !request.Cascade {
+		return nil, newValidationError("Cascade", "request.Cascade should be set")
+	}
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	response = &persist.DeletePipelineAndJobsResponse{
+		PipelineSummary: &persist.WriteSummary{},
+		JobSummary:      &persist.WriteSummary{},
+	}
+	if pipelineInfo, ok := a.pipelineInfos[request.Pipeline.Name]; ok {
+		delete(a.pipelineInfos, request.Pipeline.Name)
+		response.PipelineSummary.Deleted++
+		a.pipelineChanged(&persist.PipelineInfoChange{Pipeline: pipelineInfo, Removed: true})
+	}
+	for jobID, jobInfo := range a.jobInfos {
+		if jobInfo.PipelineName == request.Pipeline.Name {
+			delete(a.jobInfos, jobID)
+			response.JobSummary.Deleted++
+		}
+	}
+	if response.JobSummary.Deleted > 0 {
+		a.notify()
+	}
+	return response, nil
+}
+
+func (a *memoryAPIServer) SubscribePipelineInfos(request *persist.SubscribePipelineInfosRequest, server persist.API_SubscribePipelineInfosServer) (retErr error) {
+	defer func(start time.Time) { a.Log(request, nil, retErr, time.Since(start)) }(time.Now())
+	if err := a.opts.validateShard(request.Shard); err != nil {
+		return err
+	}
+	if err := a.addSubscription(); err != nil {
+		return err
+	}
+	defer a.subscriptions.Done()
+	watcher := &pipelineWatcher{shard: request.Shard, buffer: a.opts.subscribeBufferSize()}
+	a.lock.Lock()
+	if request.IncludeInitial {
+		// like rethink's these count against the buffer
+		for _, pipelineInfo := range a.sortedPipelineInfos() {
+			watcher.add(&persist.PipelineInfoChange{Pipeline: pipelineInfo})
+		}
+	}
+	a.watchers[watcher] = true
+	a.lock.Unlock()
+	defer func() {
+		a.lock.Lock()
+		delete(a.watchers, watcher)
+		a.lock.Unlock()
+	}()
+
+	changes, errCh := a.watchPipelineInfos(watcher)
+	if request.CoalesceMilliseconds != 0 {
+		return coalescePipelineInfoChanges(changes, errCh, server, time.Duration(request.CoalesceMilliseconds)*time.Millisecond)
+	}
+	for pipelineInfoChange := range changes {
+		server.Send(pipelineInfoChange)
+	}
+	return <-errCh
+}
+
+// watchPipelineInfos sends watcher's changes on changes until the server is
+// drained or the watcher falls behind, then closes it. errCh then gets nil or
+// ErrSubscriberBehind, like the one from cursorPipelineInfoChanges.
+func (a *memoryAPIServer) watchPipelineInfos(watcher *pipelineWatcher) (<-chan *persist.PipelineInfoChange, <-chan error) {
+	changes := make(chan *persist.PipelineInfoChange)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(changes)
+		for {
+			a.lock.Lock()
+			pending := watcher.pending
+			watcher.pending = nil
+			behind := watcher.behind
+			changed := a.changed
+			a.lock.Unlock()
+			for _, pipelineInfoChange := range pending {
+				select {
+				case changes <- pipelineInfoChange:
+				case <-a.drain:
+					errCh <- nil
+					return
+				}
+			}
+			if behind {
+				errCh <- ErrSubscriberBehind
+				return
+			}
+			select {
+			case <-changed:
+			case <-a.drain:
+				errCh <- nil
+				return
+			}
+		}
+	}()
+	return changes, errCh
+}
+
+func (a *memoryAPIServer) WaitPipelineDeleted(ctx context.Context, request *persist.WaitPipelineDeletedRequest) (response *google_protobuf.Empty, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	if request.Pipeline == nil {
+		return nil, newValidationError("Pipeline", "request.Pipeline should be set")
+	}
+	if err := a.addSubscription(); err != nil {
+		return nil, err
+	}
+	defer a.subscriptions.Done()
+	var timeout <-chan time.Time
+	if request.TimeoutMilliseconds != 0 {
+		timer := time.NewTimer(time.Duration(request.TimeoutMilliseconds) * time.Millisecond)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	for {
+		a.lock.Lock()
+		_, ok := a.pipelineInfos[request.Pipeline.Name]
+		changed := a.changed
+		a.lock.Unlock()
+		if !ok {
+			return google_protobuf.EmptyInstance, nil
+		}
+		select {
+		case <-changed:
+		case <-timeout:
+			return nil, ErrTimeout
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-a.drain:
+			return nil, ErrDraining
+		}
+	}
+}
+
+func (a *memoryAPIServer) StartPod(ctx context.Context, request *ppsclient.Job) (response *persist.JobInfo, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	return a.shardOp(request, func(jobInfo *persist.JobInfo) { jobInfo.PodsStarted++ })
+}
+
+func (a *memoryAPIServer) SucceedPod(ctx context.Context, request *ppsclient.Job) (response *persist.JobInfo, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	return a.shardOp(request, func(jobInfo *persist.JobInfo) { jobInfo.PodsSucceeded++ })
+}
+
+func (a *memoryAPIServer) FailPod(ctx context.Context, request *ppsclient.Job) (response *persist.JobInfo, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	return a.shardOp(request, func(jobInfo *persist.JobInfo) { jobInfo.PodsFailed++ })
+}
+
+func (a *memoryAPIServer) GetPodCounters(ctx context.Context, request *ppsclient.Job) (response *persist.PodCounters, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	jobInfo, ok := a.jobInfos[request.ID]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	return &persist.PodCounters{
+		PodsStarted:   jobInfo.PodsStarted,
+		PodsSucceeded: jobInfo.PodsSucceeded,
+		PodsFailed:    jobInfo.PodsFailed,
+	}, nil
+}
+
+func (a *memoryAPIServer) ResetPodCounters(ctx context.Context, request *ppsclient.Job) (response *google_protobuf.Empty, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	if _, err := a.shardOp(request, func(jobInfo *persist.JobInfo) {
+		jobInfo.PodsStarted = 0
+		jobInfo.PodsSucceeded = 0
+		jobInfo.PodsFailed = 0
+	}); err != nil {
+		return nil, err
+	}
+	return google_protobuf.EmptyInstance, nil
+}
+
+// Ping's round trip is how long it waited for the lock.
+func (a *memoryAPIServer) Ping(ctx context.Context, request *google_protobuf.Empty) (response *persist.PingResponse, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	start := time.Now()
+	a.lock.Lock()
+	a.lock.Unlock()
+	return &persist.PingResponse{RoundTrip: prototime.DurationToProto(time.Since(start))}, nil
+}
+
+// shardOp applies op to the job's pod counters and returns the updated job.
+func (a *memoryAPIServer) shardOp(request *ppsclient.Job, op func(jobInfo *persist.JobInfo)) (*persist.JobInfo, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	jobInfo, ok := a.jobInfos[request.ID]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	op(jobInfo)
+	jobInfo.UpdatedAt = a.now()
+	a.notify()
+	return cloneJobInfo(jobInfo), nil
+}
+
+// updateJobInfo is updateExistingMessage for jobs, update returns false if
+// it didn't change the job, otherwise the job's UpdatedAt is set to now.
+func (a *memoryAPIServer) updateJobInfo(jobID string, update func(jobInfo *persist.JobInfo) bool) (*persist.WriteSummary, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	jobInfo, ok := a.jobInfos[jobID]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	if !update(jobInfo) {
+		return &persist.WriteSummary{Unchanged: 1}, nil
+	}
+	jobInfo.UpdatedAt = a.now()
+	a.notify()
+	return &persist.WriteSummary{Replaced: 1}, nil
+}
+
+// pipelineChanged queues change for the subscriptions that want it, a.lock
+// must be held.
+func (a *memoryAPIServer) pipelineChanged(change *persist.PipelineInfoChange) {
+	for watcher := range a.watchers {
+		watcher.add(change)
+	}
+	a.notify()
+}
+
+// notify wakes everything waiting on a.changed, a.lock must be held.
+func (a *memoryAPIServer) notify() {
+	close(a.changed)
+	a.changed = make(chan struct{})
+}
+
+// sortedPipelineInfos returns the pipelines in name order, a.lock must be
+// held.
+func (a *memoryAPIServer) sortedPipelineInfos() []*persist.PipelineInfo {
+	var names []string
+	for name := range a.pipelineInfos {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var result []*persist.PipelineInfo
+	for _, name := range names {
+		result = append(result, a.pipelineInfos[name])
+	}
+	return result
+}
+
+func (a *memoryAPIServer) now() *google_protobuf.Timestamp {
+	return prototime.TimeToTimestamp(a.timer.Now())
+}
+
+// add queues a copy of change if it's in the watcher's shard.
+func (w *pipelineWatcher) add(change *persist.PipelineInfoChange) {
+	if !w.inShard(change.Pipeline) || w.behind {
+		return
+	}
+	if len(w.pending) >= w.buffer {
+		w.behind = true
+		return
+	}
+	w.pending = append(w.pending, &persist.PipelineInfoChange{
+		Pipeline: clonePipelineInfo(change.Pipeline),
+		Removed:  change.Removed,
+	})
+}
+
+func (w *pipelineWatcher) inShard(pipelineInfo *persist.PipelineInfo) bool {
+	return w.shard == nil || pipelineInfo.Shard == w.shard.Number
+}
+
+func cloneJobInfo(jobInfo *persist.JobInfo) *persist.JobInfo {
+	return proto.Clone(jobInfo).(*persist.JobInfo)
+}
+
+func clonePipelineInfo(pipelineInfo *persist.PipelineInfo) *persist.PipelineInfo {
+	return proto.Clone(pipelineInfo).(*persist.PipelineInfo)
+}
+
+type jobInfosByCommitIndex []*persist.JobInfo
+
+func (s jobInfosByCommitIndex) Len() int          { return len(s) }
+func (s jobInfosByCommitIndex) Swap(i int, j int) { s[i], s[j] = s[j], s[i] }
+func (s jobInfosByCommitIndex) Less(i int, j int) bool {
+	return s[i].CommitIndex < s[j].CommitIndex
+}
@@ -2,7 +2,10 @@ package server
 
 import (
 	"fmt"
+	"regexp"
 	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/dancannon/gorethink"
@@ -11,6 +14,7 @@ import (
 	ppsclient "github.com/pachyderm/pachyderm/src/client/pps"
 	"github.com/pachyderm/pachyderm/src/server/pps/persist"
 
+	"go.pedge.io/lion/proto"
 	"go.pedge.io/pb/go/google/protobuf"
 	"go.pedge.io/pkg/time"
 	"go.pedge.io/proto/rpclog"
@@ -23,11 +27,22 @@ const (
 	pipelineNameIndex          Index = "PipelineName"
 	pipelineNameAndCommitIndex Index = "PipelineNameAndCommitIndex"
 	commitIndex                Index = "CommitIndex"
+	createdAtIndex             Index = "CreatedAt"
+	updatedAtIndex             Index = "UpdatedAt"
+	pipelineStateCreatedIndex  Index = "PipelineNameAndStateAndCreatedAt"
+	pipelineVersionIndex       Index = "PipelineNameAndPipelineVersion"
 
 	pipelineInfosTable Table = "PipelineInfos"
 	pipelineShardIndex Index = "Shard"
 
 	connectTimeoutSeconds = 5
+
+	// duplicatePrimaryKeyError prefixes the FirstError that rethink reports
+	// when an insert conflicts with an existing document.
+	duplicatePrimaryKeyError = "Duplicate primary key"
+	// valueNotFoundError is the error getMessageByPrimaryKey and
+	// waitMessageByPrimaryKey have rethink raise for a missing document.
+	valueNotFoundError = "value not found"
 )
 
 type Table string
@@ -40,6 +55,23 @@ var (
 		pipelineInfosTable,
 	}
 
+	// tableToIndexes lists the secondary indexes InitDBs creates, CheckDBs
+	// makes sure they all exist.
+	tableToIndexes = map[Table][]Index{
+		jobInfosTable: []Index{
+			pipelineNameIndex,
+			commitIndex,
+			pipelineNameAndCommitIndex,
+			createdAtIndex,
+			updatedAtIndex,
+			pipelineStateCreatedIndex,
+			pipelineVersionIndex,
+		},
+		pipelineInfosTable: []Index{
+			pipelineShardIndex,
+		},
+	}
+
 	tableToTableCreateOpts = map[Table][]gorethink.TableCreateOpts{
 		jobInfosTable: []gorethink.TableCreateOpts{
 			gorethink.TableCreateOpts{
@@ -56,35 +88,46 @@ var (
 
 // InitDBs prepares a RethinkDB instance to be used by the rethink server.
 // Rethink servers will error if they are pointed at databases that haven't had InitDBs run on them.
-func InitDBs(address string, databaseName string) error {
-	session, err := connect(address)
+// Several instances can share a database if they use different
+// opts.TablePrefix, so it's only an error if the tables already exist.
+func InitDBs(address string, databaseName string, opts *Options) error {
+	if opts == nil {
+		opts = &Options{}
+	}
+	session, err := connect(address, opts)
 	if err != nil {
 		return err
 	}
-	if _, err := gorethink.DBCreate(databaseName).RunWrite(session); err != nil {
+	databaseExists, err := hasDB(session, databaseName)
+	if err != nil {
 		return err
 	}
+	if !databaseExists {
+		if _, err := gorethink.DBCreate(databaseName).RunWrite(session); err != nil {
+			return err
+		}
+	}
 	for _, table := range tables {
 		tableCreateOpts, ok := tableToTableCreateOpts[table]
 		if ok {
-			if _, err := gorethink.DB(databaseName).TableCreate(table, tableCreateOpts...).RunWrite(session); err != nil {
+			if _, err := gorethink.DB(databaseName).TableCreate(opts.table(table), tableCreateOpts...).RunWrite(session); err != nil {
 				return err
 			}
 		} else {
-			if _, err := gorethink.DB(databaseName).TableCreate(table).RunWrite(session); err != nil {
+			if _, err := gorethink.DB(databaseName).TableCreate(opts.table(table)).RunWrite(session); err != nil {
 				return err
 			}
 		}
 	}
 
 	// Create indexes
-	if _, err := gorethink.DB(databaseName).Table(jobInfosTable).IndexCreate(pipelineNameIndex).RunWrite(session); err != nil {
+	if _, err := gorethink.DB(databaseName).Table(opts.table(jobInfosTable)).IndexCreate(pipelineNameIndex).RunWrite(session); err != nil {
 		return err
 	}
-	if _, err := gorethink.DB(databaseName).Table(jobInfosTable).IndexCreate(commitIndex).RunWrite(session); err != nil {
+	if _, err := gorethink.DB(databaseName).Table(opts.table(jobInfosTable)).IndexCreate(commitIndex).RunWrite(session); err != nil {
 		return err
 	}
-	if _, err := gorethink.DB(databaseName).Table(jobInfosTable).IndexCreateFunc(
+	if _, err := gorethink.DB(databaseName).Table(opts.table(jobInfosTable)).IndexCreateFunc(
 		pipelineNameAndCommitIndex,
 		func(row gorethink.Term) interface{} {
 			return []interface{}{
@@ -94,7 +137,46 @@ func InitDBs(address string, databaseName string) error {
 		}).RunWrite(session); err != nil {
 		return err
 	}
-	if _, err := gorethink.DB(databaseName).Table(pipelineInfosTable).IndexCreate(pipelineShardIndex).RunWrite(session); err != nil {
+	if _, err := gorethink.DB(databaseName).Table(opts.table(jobInfosTable)).IndexCreateFunc(
+		createdAtIndex,
+		func(row gorethink.Term) interface{} {
+			// CreatedAt is a Timestamp, which rethink can't index directly
+			return []interface{}{
+				row.Field("CreatedAt").Field("Seconds"),
+				row.Field("CreatedAt").Field("Nanos"),
+			}
+		}).RunWrite(session); err != nil {
+		return err
+	}
+	if _, err := gorethink.DB(databaseName).Table(opts.table(jobInfosTable)).IndexCreateFunc(
+		updatedAtIndex,
+		func(row gorethink.Term) interface{} {
+			return timestampKey(row.Field("UpdatedAt"))
+		}).RunWrite(session); err != nil {
+		return err
+	}
+	if _, err := gorethink.DB(databaseName).Table(opts.table(jobInfosTable)).IndexCreateFunc(
+		pipelineStateCreatedIndex,
+		func(row gorethink.Term) interface{} {
+			return []interface{}{
+				row.Field(pipelineNameIndex),
+				row.Field("State").Default(ppsclient.JobState_JOB_STATE_RUNNING),
+				timestampKey(row.Field("CreatedAt")),
+			}
+		}).RunWrite(session); err != nil {
+		return err
+	}
+	if _, err := gorethink.DB(databaseName).Table(opts.table(jobInfosTable)).IndexCreateFunc(
+		pipelineVersionIndex,
+		func(row gorethink.Term) interface{} {
+			return []interface{}{
+				row.Field(pipelineNameIndex),
+				row.Field("PipelineVersion").Default(0),
+			}
+		}).RunWrite(session); err != nil {
+		return err
+	}
+	if _, err := gorethink.DB(databaseName).Table(opts.table(pipelineInfosTable)).IndexCreate(pipelineShardIndex).RunWrite(session); err != nil {
 		return err
 	}
 
@@ -102,32 +184,30 @@ func InitDBs(address string, databaseName string) error {
 }
 
 // CheckDBs checks that we have all the tables/indices we need
-func CheckDBs(address string, databaseName string) error {
-	session, err := connect(address)
+func CheckDBs(address string, databaseName string, opts *Options) error {
+	if opts == nil {
+		opts = &Options{}
+	}
+	session, err := connect(address, opts)
 	if err != nil {
 		return err
 	}
 
 	for _, table := range tables {
-		if _, err := gorethink.DB(databaseName).Table(table).Wait().RunWrite(session); err != nil {
+		if _, err := gorethink.DB(databaseName).Table(opts.table(table)).Wait().RunWrite(session); err != nil {
 			return err
 		}
 	}
 
-	if _, err := gorethink.DB(databaseName).Table(jobInfosTable).IndexWait(pipelineNameIndex).RunWrite(session); err != nil {
-		return err
-	}
-
-	if _, err := gorethink.DB(databaseName).Table(jobInfosTable).IndexWait(commitIndex).RunWrite(session); err != nil {
-		return err
-	}
-
-	if _, err := gorethink.DB(databaseName).Table(jobInfosTable).IndexWait(pipelineNameAndCommitIndex).RunWrite(session); err != nil {
-		return err
-	}
-
-	if _, err := gorethink.DB(databaseName).Table(pipelineInfosTable).IndexWait(pipelineShardIndex).RunWrite(session); err != nil {
-		return err
+	for _, table := range tables {
+		if err := checkIndexes(session, databaseName, opts.table(table), tableToIndexes[table]); err != nil {
+			return err
+		}
+		for _, index := range tableToIndexes[table] {
+			if _, err := gorethink.DB(databaseName).Table(opts.table(table)).IndexWait(index).RunWrite(session); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
@@ -135,50 +215,152 @@ func CheckDBs(address string, databaseName string) error {
 
 type rethinkAPIServer struct {
 	protorpclog.Logger
-	session      *gorethink.Session
-	databaseName string
-	timer        pkgtime.Timer
+	session *gorethink.Session
+	// changesSession is used for changefeeds, which wait indefinitely so
+	// they can't share session's QueryTimeout.
+	changesSession *gorethink.Session
+	databaseName   string
+	opts           Options
+	timer          pkgtime.Timer
+	// subscriptions counts running SubscribePipelineInfos calls, drain is
+	// closed to tell them to stop.
+	subscriptions sync.WaitGroup
+	drain         chan struct{}
+	draining      bool
+	lock          sync.Mutex
 }
 
-func newRethinkAPIServer(address string, databaseName string) (*rethinkAPIServer, error) {
-	session, err := connect(address)
+func newRethinkAPIServer(address string, databaseName string, opts *Options) (*rethinkAPIServer, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	session, err := connectWithQueryTimeout(address, opts)
 	if err != nil {
 		return nil, err
 	}
-	return &rethinkAPIServer{
-		protorpclog.NewLogger("pachyderm.ppsclient.persist.API"),
-		session,
-		databaseName,
-		pkgtime.NewSystemTimer(),
-	}, nil
+	changesSession := session
+	if opts.QueryTimeout != 0 {
+		changesSession, err = connect(address, opts)
+		if err != nil {
+			session.Close()
+			return nil, err
+		}
+	}
+	a := &rethinkAPIServer{
+		Logger:         protorpclog.NewLogger("pachyderm.ppsclient.persist.API"),
+		session:        session,
+		changesSession: changesSession,
+		databaseName:   databaseName,
+		opts:           *opts,
+		timer:          pkgtime.NewSystemTimer(),
+		drain:          make(chan struct{}),
+	}
+	if opts.JobRetention != 0 {
+		go a.purgeJobInfos()
+	}
+	return a, nil
 }
 
 func (a *rethinkAPIServer) Close() error {
+	a.lock.Lock()
+	a.stop()
+	a.lock.Unlock()
+	if a.changesSession != a.session {
+		if err := a.changesSession.Close(); err != nil {
+			a.session.Close()
+			return err
+		}
+	}
 	return a.session.Close()
 }
 
+// Drain stops new subscriptions, ends the running ones and waits for them to
+// return, or for ctx to be done, before closing the session.
+func (a *rethinkAPIServer) Drain(ctx context.Context) error {
+	a.lock.Lock()
+	a.stop()
+	a.lock.Unlock()
+	done := make(chan struct{})
+	go func() {
+		a.subscriptions.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+	if err := a.Close(); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// stop must be called with a.lock held.
+func (a *rethinkAPIServer) stop() {
+	if !a.draining {
+		a.draining = true
+		close(a.drain)
+	}
+}
+
+func (a *rethinkAPIServer) addSubscription() error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if a.draining {
+		return ErrDraining
+	}
+	a.subscriptions.Add(1)
+	return nil
+}
+
 // Timestamp cannot be set
 func (a *rethinkAPIServer) CreateJobInfo(ctx context.Context, request *persist.JobInfo) (response *persist.JobInfo, err error) {
 	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
-	if request.JobID == "" {
-		return nil, fmt.Errorf("request.JobID should be set")
-	}
 	if request.CreatedAt != nil {
-		return nil, fmt.Errorf("request.CreatedAt should be unset")
+		return nil, newValidationError("CreatedAt", "request.CreatedAt should be unset")
 	}
-	if request.CommitIndex != "" {
-		return nil, fmt.Errorf("request.CommitIndex should be unset")
+	request.CreatedAt = a.now()
+	request.UpdatedAt = request.CreatedAt
+	return a.createJobInfo(ctx, request)
+}
+
+// Timestamp must be set, and the server must allow imports
+func (a *rethinkAPIServer) ImportJobInfo(ctx context.Context, request *persist.JobInfo) (response *persist.JobInfo, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	if !a.opts.AllowImport {
+		return nil, ErrImportNotAllowed
 	}
-	request.CreatedAt = prototime.TimeToTimestamp(time.Now())
-	var commits []*pfs.Commit
-	for _, input := range request.Inputs {
-		commits = append(commits, input.Commit)
+	if request.CreatedAt == nil {
+		return nil, newValidationError("CreatedAt", "request.CreatedAt should be set")
+	}
+	if request.UpdatedAt == nil {
+		request.UpdatedAt = request.CreatedAt
+	}
+	return a.createJobInfo(ctx, request)
+}
+
+func (a *rethinkAPIServer) createJobInfo(ctx context.Context, request *persist.JobInfo) (*persist.JobInfo, error) {
+	if request.JobID == "" {
+		return nil, newValidationError("JobID", "request.JobID should be set")
+	}
+	if request.CommitIndex != "" {
+		return nil, newValidationError("CommitIndex", "request.CommitIndex should be unset")
 	}
-	request.CommitIndex, err = genCommitIndex(commits)
+	var err error
+	request.CommitIndex, err = jobCommitIndex(request)
 	if err != nil {
 		return nil, err
 	}
-	if err := a.insertMessage(jobInfosTable, request); err != nil {
+	if err := a.insertUniqueMessage(ctx, jobInfosTable, request, ErrJobExists); err != nil {
+		if err == ErrJobExists && a.opts.IdempotentCreate {
+			existing := &persist.JobInfo{}
+			if err := a.getMessageByPrimaryKey(ctx, jobInfosTable, request.JobID, existing, ErrJobNotFound); err != nil {
+				return nil, err
+			}
+			if sameJobInfo(existing, request) {
+				return existing, nil
+			}
+		}
 		return nil, err
 	}
 	return request, nil
@@ -196,9 +378,11 @@ func (a *rethinkAPIServer) InspectJob(ctx context.Context, request *ppsclient.In
 		mustHaveFields = append(mustHaveFields, "State")
 	}
 	if err := a.waitMessageByPrimaryKey(
+		ctx,
 		jobInfosTable,
 		request.Job.ID,
 		jobInfo,
+		ErrJobNotFound,
 		func(jobInfo gorethink.Term) gorethink.Term {
 			if request.BlockState {
 				return jobInfo.Field("State").Ne(ppsclient.JobState_JOB_STATE_RUNNING)
@@ -211,105 +395,301 @@ func (a *rethinkAPIServer) InspectJob(ctx context.Context, request *ppsclient.In
 	return jobInfo, nil
 }
 
+func (a *rethinkAPIServer) GetJobInfos(ctx context.Context, request *persist.GetJobInfosRequest) (response *persist.GetJobInfosResponse, retErr error) {
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	var ids []interface{}
+	for _, job := range request.Job {
+		ids = append(ids, job.ID)
+	}
+	response = &persist.GetJobInfosResponse{}
+	if len(ids) == 0 {
+		return response, nil
+	}
+	cursor, err := a.run(ctx, a.getTerm(jobInfosTable).GetAll(ids...))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := cursor.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+	jobInfos := make(map[string]*persist.JobInfo)
+	for {
+		jobInfo := &persist.JobInfo{}
+		if !cursor.Next(jobInfo) {
+			break
+		}
+		jobInfos[jobInfo.JobID] = jobInfo
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	for _, job := range request.Job {
+		if jobInfo, ok := jobInfos[job.ID]; ok {
+			response.JobInfo = append(response.JobInfo, jobInfo)
+		} else {
+			response.Missing = append(response.Missing, job)
+		}
+	}
+	return response, nil
+}
+
 func (a *rethinkAPIServer) ListJobInfos(ctx context.Context, request *ppsclient.ListJobRequest) (response *persist.JobInfos, retErr error) {
 	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
-	query := a.getTerm(jobInfosTable)
-	commitIndexVal, err := genCommitIndex(request.InputCommit)
+	query, err := a.listJobInfosQuery(request)
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := a.run(ctx, query)
 	if err != nil {
 		return nil, err
 	}
-	if request.Pipeline != nil && len(request.InputCommit) > 0 {
+	defer func() {
+		if err := cursor.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+	result := &persist.JobInfos{}
+	for {
+		jobInfo := &persist.JobInfo{}
+		if !cursor.Next(jobInfo) {
+			break
+		}
+		result.JobInfo = append(result.JobInfo, jobInfo)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	result.NextToken = nextSinceToken(request.SinceToken, result.JobInfo)
+	// none of the indexes keep jobs newest first, sort to match the memory
+	// server
+	sortJobInfosByTimestampDesc(result.JobInfo)
+	return result, nil
+}
+
+// listJobInfosQuery returns the query for request's jobs, shared by
+// ListJobInfos and ListJobInfosStream. The jobs don't come back in any
+// particular order.
+func (a *rethinkAPIServer) listJobInfosQuery(request *ppsclient.ListJobRequest) (query gorethink.Term, err error) {
+	query = a.getTerm(jobInfosTable)
+	commitIndexVal, err := genCommitIndex(request.InputCommit)
+	if err != nil {
+		return query, err
+	}
+	var since []interface{}
+	if request.SinceToken != "" {
+		since, err = parseSinceToken(request.SinceToken)
+		if err != nil {
+			return query, err
+		}
+	}
+	var pipelineNames []interface{}
+	if request.Pipeline != nil {
+		pipelineNames = append(pipelineNames, request.Pipeline.Name)
+	}
+	for _, pipeline := range request.Pipelines {
+		pipelineNames = append(pipelineNames, pipeline.Name)
+	}
+	pipelineVersion := request.PipelineVersion
+	if pipelineVersion != 0 && len(pipelineNames) == 0 {
+		return query, newValidationError("PipelineVersion", "request.PipelineVersion can only be set with request.Pipeline or request.Pipelines")
+	}
+	states := request.State
+	var createdAfter interface{}
+	if request.CreatedAfter != nil {
+		createdAfter = []interface{}{request.CreatedAfter.Seconds, request.CreatedAfter.Nanos}
+	}
+	if len(pipelineNames) > 0 && len(states) > 0 && createdAfter != nil && len(request.InputCommit) == 0 {
+		// one range of the compound index per pipeline and state
+		var ranges []interface{}
+		for _, pipelineName := range pipelineNames {
+			for _, state := range states {
+				ranges = append(ranges, a.getTerm(jobInfosTable).Between(
+					[]interface{}{pipelineName, state, createdAfter},
+					[]interface{}{pipelineName, state, gorethink.MaxVal},
+					gorethink.BetweenOpts{Index: pipelineStateCreatedIndex},
+				))
+			}
+		}
+		query = gorethink.Union(ranges...)
+		states = nil
+		createdAfter = nil
+	} else if pipelineVersion != 0 && len(request.InputCommit) == 0 {
+		var keys []interface{}
+		for _, pipelineName := range pipelineNames {
+			keys = append(keys, gorethink.Expr([]interface{}{pipelineName, pipelineVersion}))
+		}
+		query = query.GetAllByIndex(
+			pipelineVersionIndex,
+			keys...,
+		)
+		pipelineVersion = 0
+	} else if len(pipelineNames) > 0 && len(request.InputCommit) > 0 {
+		var keys []interface{}
+		for _, pipelineName := range pipelineNames {
+			keys = append(keys, gorethink.Expr([]interface{}{pipelineName, commitIndexVal}))
+		}
 		query = query.GetAllByIndex(
 			pipelineNameAndCommitIndex,
-			gorethink.Expr([]interface{}{request.Pipeline.Name, commitIndexVal}),
+			keys...,
 		)
-	} else if request.Pipeline != nil {
+	} else if len(pipelineNames) > 0 {
 		query = query.GetAllByIndex(
 			pipelineNameIndex,
-			request.Pipeline.Name,
+			pipelineNames...,
 		)
 	} else if len(request.InputCommit) > 0 {
 		query = query.GetAllByIndex(
 			commitIndex,
 			gorethink.Expr(commitIndexVal),
 		)
+	} else if createdAfter != nil {
+		query = query.Between(createdAfter, gorethink.MaxVal, gorethink.BetweenOpts{
+			Index: createdAtIndex,
+		})
+		createdAfter = nil
+	} else if since != nil {
+		query = query.Between(since, gorethink.MaxVal, gorethink.BetweenOpts{
+			Index:     updatedAtIndex,
+			LeftBound: "open",
+		})
+		since = nil
+	}
+	if pipelineVersion != 0 {
+		query = query.Filter(func(jobInfo gorethink.Term) gorethink.Term {
+			return jobInfo.Field("PipelineVersion").Default(0).Eq(pipelineVersion)
+		})
 	}
-	cursor, err := query.Run(a.session)
+	if len(states) > 0 {
+		query = query.Filter(func(jobInfo gorethink.Term) gorethink.Term {
+			return gorethink.Expr(states).Contains(jobInfo.Field("State").Default(ppsclient.JobState_JOB_STATE_RUNNING))
+		})
+	}
+	if createdAfter != nil {
+		query = query.Filter(func(jobInfo gorethink.Term) gorethink.Term {
+			return timestampKey(jobInfo.Field("CreatedAt")).Ge(createdAfter).Default(false)
+		})
+	}
+	if since != nil {
+		// we've already used an index to select jobs
+		query = query.Filter(func(jobInfo gorethink.Term) gorethink.Term {
+			return timestampKey(jobInfo.Field("UpdatedAt")).Gt(since).Default(false)
+		})
+	}
+	if request.HasOutput {
+		query = query.Filter(gorethink.Row.HasFields("OutputCommit"))
+	}
+	for _, pipeline := range request.ExcludePipelines {
+		query = query.Filter(gorethink.Row.Field("PipelineName").Ne(pipeline.Name))
+	}
+	if request.LatestPerPipeline {
+		query = query.Group("PipelineName").Max(func(jobInfo gorethink.Term) gorethink.Term {
+			return gorethink.Expr([]interface{}{
+				jobInfo.Field("CreatedAt").Field("Seconds"),
+				jobInfo.Field("CreatedAt").Field("Nanos"),
+			})
+		}).Ungroup().Field("reduction")
+	}
+	return query, nil
+}
+
+func (a *rethinkAPIServer) ListJobInfosStream(request *ppsclient.ListJobRequest, server persist.API_ListJobInfosStreamServer) (retErr error) {
+	defer func(start time.Time) { a.Log(request, nil, retErr, time.Since(start)) }(time.Now())
+	query, err := a.listJobInfosQuery(request)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	cursor, err := a.run(server.Context(), query)
+	if err != nil {
+		return err
 	}
 	defer func() {
 		if err := cursor.Close(); err != nil && retErr == nil {
 			retErr = err
 		}
 	}()
-	result := &persist.JobInfos{}
 	for {
 		jobInfo := &persist.JobInfo{}
 		if !cursor.Next(jobInfo) {
 			break
 		}
-		result.JobInfo = append(result.JobInfo, jobInfo)
-	}
-	if err := cursor.Err(); err != nil {
-		return nil, err
+		if err := server.Send(jobInfo); err != nil {
+			return err
+		}
 	}
-	return result, nil
+	return cursor.Err()
 }
 
-func (a *rethinkAPIServer) DeleteJobInfo(ctx context.Context, request *ppsclient.Job) (response *google_protobuf.Empty, err error) {
-	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
-	if err := a.deleteMessageByPrimaryKey(jobInfosTable, request.ID); err != nil {
-		return nil, err
+func (a *rethinkAPIServer) ExportJobInfos(request *ppsclient.Pipeline, server persist.API_ExportJobInfosServer) (retErr error) {
+	defer func(start time.Time) { a.Log(request, nil, retErr, time.Since(start)) }(time.Now())
+	if request.Name == "" {
+		return newValidationError("Name", "request.Name should be set")
 	}
-	return google_protobuf.EmptyInstance, nil
-}
-
-func (a *rethinkAPIServer) CreateJobOutput(ctx context.Context, request *persist.JobOutput) (response *google_protobuf.Empty, err error) {
-	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
-	if err := a.updateMessage(jobInfosTable, request); err != nil {
-		return nil, err
+	// not ordered, ordering would make rethink read every job before
+	// returning the first
+	cursor, err := a.run(server.Context(), a.getTerm(jobInfosTable).GetAllByIndex(pipelineNameIndex, request.Name))
+	if err != nil {
+		return err
 	}
-	return google_protobuf.EmptyInstance, nil
-}
-
-func (a *rethinkAPIServer) CreateJobState(ctx context.Context, request *persist.JobState) (response *google_protobuf.Empty, err error) {
-	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
-	if err := a.updateMessage(jobInfosTable, request); err != nil {
-		return nil, err
+	defer func() {
+		if err := cursor.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+	for {
+		jobInfo := &persist.JobInfo{}
+		if !cursor.Next(jobInfo) {
+			break
+		}
+		if err := server.Send(jobInfo); err != nil {
+			return err
+		}
 	}
-	return google_protobuf.EmptyInstance, nil
+	return cursor.Err()
 }
 
-// timestamp cannot be set
-func (a *rethinkAPIServer) CreatePipelineInfo(ctx context.Context, request *persist.PipelineInfo) (response *persist.PipelineInfo, err error) {
-	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
-	if request.CreatedAt != nil {
-		return nil, ErrTimestampSet
+func (a *rethinkAPIServer) RecentJobInfos(ctx context.Context, request *persist.RecentJobInfosRequest) (response *persist.JobInfos, retErr error) {
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	if request.N == 0 {
+		// a Limit of 0 would return nothing, which can't be what was meant
+		return nil, newValidationError("N", "request.N should be set")
 	}
-	request.CreatedAt = a.now()
-	if err := a.insertMessage(pipelineInfosTable, request); err != nil {
+	query := a.getTerm(jobInfosTable).
+		OrderBy(gorethink.OrderByOpts{Index: gorethink.Desc(createdAtIndex)}).
+		Limit(request.N)
+	cursor, err := a.run(ctx, query)
+	if err != nil {
 		return nil, err
 	}
-	return request, nil
-}
-
-func (a *rethinkAPIServer) GetPipelineInfo(ctx context.Context, request *ppsclient.Pipeline) (response *persist.PipelineInfo, err error) {
-	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
-	pipelineInfo := &persist.PipelineInfo{}
-	if err := a.getMessageByPrimaryKey(pipelineInfosTable, request.Name, pipelineInfo); err != nil {
+	defer func() {
+		if err := cursor.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+	result := &persist.JobInfos{}
+	for {
+		jobInfo := &persist.JobInfo{}
+		if !cursor.Next(jobInfo) {
+			break
+		}
+		result.JobInfo = append(result.JobInfo, jobInfo)
+	}
+	if err := cursor.Err(); err != nil {
 		return nil, err
 	}
-	return pipelineInfo, nil
+	return result, nil
 }
 
-func (a *rethinkAPIServer) ListPipelineInfos(ctx context.Context, request *persist.ListPipelineInfosRequest) (response *persist.PipelineInfos, retErr error) {
+func (a *rethinkAPIServer) AggregateJobStates(ctx context.Context, request *ppsclient.Pipeline) (response *persist.JobStateCounts, retErr error) {
 	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
-	query := a.getTerm(pipelineInfosTable)
-	if request.Shard != nil {
-		query = query.GetAllByIndex(pipelineShardIndex, request.Shard.Number)
+	query := a.getTerm(jobInfosTable)
+	if request.Name != "" {
+		query = query.GetAllByIndex(pipelineNameIndex, request.Name)
 	}
-	cursor, err := query.Run(a.session)
+	cursor, err := a.run(ctx, query.Group(func(jobInfo gorethink.Term) gorethink.Term {
+		return jobInfo.Field("State").Default(ppsclient.JobState_JOB_STATE_RUNNING)
+	}).Count().Ungroup())
 	if err != nil {
 		return nil, err
 	}
@@ -318,28 +698,473 @@ func (a *rethinkAPIServer) ListPipelineInfos(ctx context.Context, request *persi
 			retErr = err
 		}
 	}()
-	result := &persist.PipelineInfos{}
+	response = &persist.JobStateCounts{Counts: make(map[string]uint64)}
 	for {
-		pipelineInfo := &persist.PipelineInfo{}
-		if !cursor.Next(pipelineInfo) {
+		var group struct {
+			State ppsclient.JobState `gorethink:"group"`
+			Count uint64             `gorethink:"reduction"`
+		}
+		if !cursor.Next(&group) {
 			break
 		}
-		result.PipelineInfo = append(result.PipelineInfo, pipelineInfo)
+		response.Counts[group.State.String()] = group.Count
 	}
 	if err := cursor.Err(); err != nil {
 		return nil, err
 	}
-	return result, nil
+	return response, nil
 }
 
-func (a *rethinkAPIServer) DeletePipelineInfo(ctx context.Context, request *ppsclient.Pipeline) (response *google_protobuf.Empty, err error) {
-	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
-	if err := a.deleteMessageByPrimaryKey(pipelineInfosTable, request.Name); err != nil {
-		return nil, err
+func (a *rethinkAPIServer) GetJobInfosByCommitRange(ctx context.Context, request *persist.GetJobInfosByCommitRangeRequest) (response *persist.JobInfos, retErr error) {
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	var end interface{} = gorethink.MaxVal
+	if request.EndCommitIndex != "" {
+		end = request.EndCommitIndex
+	}
+	query := a.getTerm(jobInfosTable).
+		Between(request.StartCommitIndex, end, gorethink.BetweenOpts{Index: commitIndex}).
+		OrderBy(gorethink.OrderByOpts{Index: commitIndex})
+	cursor, err := a.run(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := cursor.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+	result := &persist.JobInfos{}
+	for {
+		jobInfo := &persist.JobInfo{}
+		if !cursor.Next(jobInfo) {
+			break
+		}
+		result.JobInfo = append(result.JobInfo, jobInfo)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (a *rethinkAPIServer) DeleteJobInfo(ctx context.Context, request *ppsclient.Job) (response *persist.WriteSummary, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	return a.deleteMessageByPrimaryKey(ctx, jobInfosTable, request.ID)
+}
+
+func (a *rethinkAPIServer) DeleteJobInfosByCommit(ctx context.Context, request *persist.DeleteJobInfosByCommitRequest) (response *persist.WriteSummary, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	if len(request.Commits) == 0 {
+		return nil, newValidationError("Commits", "request.Commits should be set")
+	}
+	commitIndexVal, err := genCommitIndex(request.Commits)
+	if err != nil {
+		return nil, err
+	}
+	query := a.getTerm(jobInfosTable).
+		GetAllByIndex(commitIndex, commitIndexVal).
+		Delete()
+	writeResponse, err := a.runWrite(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return newWriteSummary(writeResponse), nil
+}
+
+func (a *rethinkAPIServer) ReindexJobInfos(ctx context.Context, request *google_protobuf.Empty) (response *persist.ReindexJobInfosResponse, retErr error) {
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	cursor, err := a.run(ctx, a.getTerm(jobInfosTable).Pluck("JobID", "Inputs", "CommitIndex", "SkipCommitIndex"))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := cursor.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+	response = &persist.ReindexJobInfosResponse{}
+	for {
+		jobInfo := &persist.JobInfo{}
+		if !cursor.Next(jobInfo) {
+			break
+		}
+		commitIndex, err := jobCommitIndex(jobInfo)
+		if err != nil {
+			return nil, err
+		}
+		if commitIndex == jobInfo.CommitIndex {
+			continue
+		}
+		if _, err := a.runWrite(ctx, a.getTerm(jobInfosTable).Get(jobInfo.JobID).Update(map[string]interface{}{
+			"CommitIndex": commitIndex,
+			"UpdatedAt":   a.now(),
+		})); err != nil {
+			return nil, err
+		}
+		response.Updated++
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// purgeJobInfos deletes the jobs past opts.JobRetention every
+// opts.PurgeInterval, until the server is closed.
+func (a *rethinkAPIServer) purgeJobInfos() {
+	ticker := time.NewTicker(a.opts.purgeInterval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-a.drain:
+			return
+		}
+		createdBefore := prototime.TimeToTimestamp(a.timer.Now().Add(-a.opts.JobRetention))
+		purged, err := a.purgeJobInfosBefore(context.Background(), createdBefore)
+		if err != nil {
+			protolion.Errorf("error purging jobs created before %s: %s", prototime.TimestampToTime(createdBefore), err.Error())
+		}
+		if purged > 0 {
+			protolion.Infof("purged %d jobs created before %s", purged, prototime.TimestampToTime(createdBefore))
+		}
+	}
+}
+
+// purgeJobInfosBefore deletes the jobs created before createdBefore, oldest
+// first, purgeBatchSize at a time. It returns how many it deleted, even if
+// it failed part way.
+func (a *rethinkAPIServer) purgeJobInfosBefore(ctx context.Context, createdBefore *google_protobuf.Timestamp) (uint64, error) {
+	var purged uint64
+	for {
+		query := a.getTerm(jobInfosTable).
+			Between(
+				gorethink.MinVal,
+				[]interface{}{createdBefore.Seconds, createdBefore.Nanos},
+				gorethink.BetweenOpts{Index: createdAtIndex},
+			).
+			OrderBy(gorethink.OrderByOpts{Index: createdAtIndex}).
+			Limit(purgeBatchSize).
+			Delete()
+		response, err := a.runWrite(ctx, query)
+		if err != nil {
+			return purged, err
+		}
+		purged += uint64(response.Deleted)
+		if response.Deleted < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+func (a *rethinkAPIServer) CreateJobOutput(ctx context.Context, request *persist.JobOutput) (response *persist.WriteSummary, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	return a.updateExistingMessage(ctx, jobInfosTable, request.JobID, request, ErrJobNotFound)
+}
+
+func (a *rethinkAPIServer) CreateJobState(ctx context.Context, request *persist.JobState) (response *persist.WriteSummary, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	return a.updateExistingMessage(ctx, jobInfosTable, request.JobID, request, ErrJobNotFound)
+}
+
+func (a *rethinkAPIServer) TransitionJobState(ctx context.Context, request *persist.JobStateTransition) (response *google_protobuf.Empty, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	writeResponse, err := a.runWrite(ctx, a.getTerm(jobInfosTable).Get(request.JobID).Update(func(jobInfo gorethink.Term) gorethink.Term {
+		return gorethink.Branch(
+			jobInfo.Field("State").Default(ppsclient.JobState_JOB_STATE_RUNNING).Eq(request.From),
+			map[string]interface{}{"State": request.To, "UpdatedAt": a.now()},
+			gorethink.Error("job state mismatch"),
+		)
+	}))
+	if writeResponse.Skipped > 0 {
+		return nil, ErrJobNotFound
+	}
+	// the only thing in the update that can error is the mismatch
+	if writeResponse.Errors > 0 {
+		return nil, ErrJobStateMismatch
+	}
+	if err != nil {
+		return nil, err
+	}
+	return google_protobuf.EmptyInstance, nil
+}
+
+func (a *rethinkAPIServer) RestartJob(ctx context.Context, request *ppsclient.Job) (response *google_protobuf.Empty, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	writeResponse, err := a.runWrite(ctx, a.getTerm(jobInfosTable).Get(request.ID).Replace(func(jobInfo gorethink.Term) gorethink.Term {
+		return gorethink.Branch(
+			jobInfo.Eq(nil),
+			gorethink.Error("job not found"),
+			jobInfo.Without("OutputCommit").Merge(map[string]interface{}{
+				"State":         ppsclient.JobState_JOB_STATE_RUNNING,
+				"PodsStarted":   0,
+				"PodsSucceeded": 0,
+				"PodsFailed":    0,
+				"Restarts":      jobInfo.Field("Restarts").Default(0).Add(1),
+				"UpdatedAt":     a.now(),
+			}),
+		)
+	}))
+	// the only thing in the replace that can error is the missing job
+	if writeResponse.Errors > 0 {
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		return nil, err
 	}
 	return google_protobuf.EmptyInstance, nil
 }
 
+// timestamp cannot be set
+func (a *rethinkAPIServer) CreatePipelineInfo(ctx context.Context, request *persist.PipelineInfo) (response *persist.PipelineInfo, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	if request.PipelineName == "" {
+		return nil, newValidationError("PipelineName", "request.PipelineName should be set")
+	}
+	if request.CreatedAt != nil {
+		return nil, ErrTimestampSet
+	}
+	request.CreatedAt = a.now()
+	request.Version = 1
+	if err := a.insertMessage(ctx, pipelineInfosTable, request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+func (a *rethinkAPIServer) UpdatePipelineInfo(ctx context.Context, request *persist.PipelineInfo) (response *persist.PipelineInfo, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	if request.PipelineName == "" {
+		return nil, newValidationError("PipelineName", "request.PipelineName should be set")
+	}
+	update := proto.Clone(request).(*persist.PipelineInfo)
+	update.CreatedAt = nil
+	update.Version = request.Version + 1
+	cursor, err := a.run(ctx, a.getTerm(pipelineInfosTable).Get(request.PipelineName).Replace(func(pipelineInfo gorethink.Term) gorethink.Term {
+		return gorethink.Branch(
+			pipelineInfo.Eq(nil),
+			gorethink.Error("pipeline not found"),
+			gorethink.Branch(
+				pipelineInfo.Field("Version").Default(0).Eq(request.Version),
+				gorethink.Expr(update).Merge(map[string]interface{}{
+					"CreatedAt": pipelineInfo.Field("CreatedAt"),
+				}),
+				gorethink.Error("pipeline version mismatch"),
+			),
+		)
+	}, gorethink.ReplaceOpts{
+		ReturnChanges: true,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := cursor.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+	var writeResponse struct {
+		Errors     int                  `gorethink:"errors"`
+		FirstError string               `gorethink:"first_error"`
+		Changes    []PipelineChangeFeed `gorethink:"changes"`
+	}
+	if err := cursor.One(&writeResponse); err != nil {
+		return nil, err
+	}
+	switch {
+	case writeResponse.Errors == 0 && len(writeResponse.Changes) == 1:
+		return writeResponse.Changes[0].NewVal, nil
+	case strings.Contains(writeResponse.FirstError, "pipeline not found"):
+		return nil, ErrPipelineNotFound
+	case strings.Contains(writeResponse.FirstError, "pipeline version mismatch"):
+		return nil, ErrPipelineVersionMismatch
+	default:
+		return nil, fmt.Errorf("pachyderm.pps.persist.server: updating pipeline %s: %s", request.PipelineName, writeResponse.FirstError)
+	}
+}
+
+func (a *rethinkAPIServer) GetPipelineInfo(ctx context.Context, request *ppsclient.Pipeline) (response *persist.PipelineInfo, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	pipelineInfo := &persist.PipelineInfo{}
+	if err := a.getMessageByPrimaryKey(ctx, pipelineInfosTable, request.Name, pipelineInfo, ErrPipelineNotFound); err != nil {
+		return nil, err
+	}
+	return pipelineInfo, nil
+}
+
+func (a *rethinkAPIServer) GetPipelineInfos(ctx context.Context, request *persist.GetPipelineInfosRequest) (response *persist.GetPipelineInfosResponse, retErr error) {
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	var names []interface{}
+	for _, pipeline := range request.Pipeline {
+		names = append(names, pipeline.Name)
+	}
+	response = &persist.GetPipelineInfosResponse{}
+	if len(names) == 0 {
+		return response, nil
+	}
+	cursor, err := a.run(ctx, a.getTerm(pipelineInfosTable).GetAll(names...))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := cursor.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+	pipelineInfos := make(map[string]*persist.PipelineInfo)
+	for {
+		pipelineInfo := &persist.PipelineInfo{}
+		if !cursor.Next(pipelineInfo) {
+			break
+		}
+		pipelineInfos[pipelineInfo.PipelineName] = pipelineInfo
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	for _, pipeline := range request.Pipeline {
+		if pipelineInfo, ok := pipelineInfos[pipeline.Name]; ok {
+			response.PipelineInfo = append(response.PipelineInfo, pipelineInfo)
+		} else {
+			response.Missing = append(response.Missing, pipeline)
+		}
+	}
+	return response, nil
+}
+
+func (a *rethinkAPIServer) ListPipelineInfos(ctx context.Context, request *persist.ListPipelineInfosRequest) (response *persist.PipelineInfos, retErr error) {
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	paged := request.Limit != 0 || request.PageToken != ""
+	if paged && request.OrderBy != persist.PipelineInfosOrder_PIPELINE_INFOS_ORDER_NONE {
+		return nil, newValidationError("OrderBy", "request.OrderBy can't be set with request.Limit or request.PageToken")
+	}
+	if err := a.opts.validateShard(request.Shard); err != nil {
+		return nil, err
+	}
+	query := a.getTerm(pipelineInfosTable)
+	if request.Shard != nil {
+		query = query.GetAllByIndex(pipelineShardIndex, request.Shard.Number)
+		if request.PageToken != "" {
+			query = query.Filter(gorethink.Row.Field("PipelineName").Gt(request.PageToken))
+		}
+	} else if request.PageToken != "" {
+		// page tokens are the name of the last pipeline on the page
+		query = query.Between(request.PageToken, gorethink.MaxVal, gorethink.BetweenOpts{
+			LeftBound: "open",
+		})
+	}
+	if request.NamePrefix != "" {
+		query = query.Filter(gorethink.Row.Field("PipelineName").Match("^" + regexp.QuoteMeta(request.NamePrefix)))
+	}
+	createdAt := func(pipelineInfo gorethink.Term) gorethink.Term {
+		return timestampKey(pipelineInfo.Field("CreatedAt"))
+	}
+	switch request.OrderBy {
+	case persist.PipelineInfosOrder_PIPELINE_INFOS_ORDER_CREATED_AT_ASC:
+		query = query.OrderBy(gorethink.Asc(createdAt))
+	case persist.PipelineInfosOrder_PIPELINE_INFOS_ORDER_CREATED_AT_DESC:
+		query = query.OrderBy(gorethink.Desc(createdAt))
+	}
+	if paged {
+		query = query.OrderBy("PipelineName")
+		if request.Limit != 0 {
+			query = query.Limit(request.Limit)
+		}
+	}
+	cursor, err := a.run(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := cursor.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+	result := &persist.PipelineInfos{}
+	for {
+		pipelineInfo := &persist.PipelineInfo{}
+		if !cursor.Next(pipelineInfo) {
+			break
+		}
+		result.PipelineInfo = append(result.PipelineInfo, pipelineInfo)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	if request.Limit != 0 && uint64(len(result.PipelineInfo)) == request.Limit {
+		// there may be more, if not the next page is just empty
+		result.NextPageToken = result.PipelineInfo[len(result.PipelineInfo)-1].PipelineName
+	}
+	return result, nil
+}
+
+func (a *rethinkAPIServer) ListPipelineNames(ctx context.Context, request *persist.ListPipelineNamesRequest) (response *persist.PipelineNames, retErr error) {
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	if err := a.opts.validateShard(request.Shard); err != nil {
+		return nil, err
+	}
+	query := a.getTerm(pipelineInfosTable)
+	if request.Shard != nil {
+		query = query.GetAllByIndex(pipelineShardIndex, request.Shard.Number)
+	}
+	// only the names are sent back, rethink doesn't send whole documents
+	cursor, err := a.run(ctx, query.Field("PipelineName"))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := cursor.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+	response = &persist.PipelineNames{}
+	for {
+		var name string
+		if !cursor.Next(&name) {
+			break
+		}
+		response.Name = append(response.Name, name)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	sort.Strings(response.Name)
+	return response, nil
+}
+
+func (a *rethinkAPIServer) DeletePipelineInfo(ctx context.Context, request *ppsclient.Pipeline) (response *persist.WriteSummary, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	return a.deleteMessageByPrimaryKey(ctx, pipelineInfosTable, request.Name)
+}
+
+// DeletePipelineAndJobs isn't atomic across the two tables, if it fails
+// after deleting the pipeline info, calling it again deletes the jobs that
+// are left.
+func (a *rethinkAPIServer) DeletePipelineAndJobs(ctx context.Context, request *persist.DeletePipelineAndJobsRequest) (response *persist.DeletePipelineAndJobsResponse, err error) {
+	defer func(start time.Time) { a.Log(request, response, err, time.Since(start)) }(time.Now())
+	if request.Pipeline == nil {
+		return nil, newValidationError("Pipeline", "request.Pipeline should be set")
+	}
+	if !request.Cascade {
+		return nil, newValidationError("Cascade", "request.Cascade should be set")
+	}
+	pipelineSummary, err := a.deleteMessageByPrimaryKey(ctx, pipelineInfosTable, request.Pipeline.Name)
+	if err != nil {
+		return nil, err
+	}
+	query := a.getTerm(jobInfosTable).
+		GetAllByIndex(pipelineNameIndex, request.Pipeline.Name).
+		Delete()
+	writeResponse, err := a.runWrite(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &persist.DeletePipelineAndJobsResponse{
+		PipelineSummary: pipelineSummary,
+		JobSummary:      newWriteSummary(writeResponse),
+	}, nil
+}
+
 type PipelineChangeFeed struct {
 	OldVal *persist.PipelineInfo `gorethink:"old_val,omitempty"`
 	NewVal *persist.PipelineInfo `gorethink:"new_val,omitempty"`
@@ -347,6 +1172,13 @@ type PipelineChangeFeed struct {
 
 func (a *rethinkAPIServer) SubscribePipelineInfos(request *persist.SubscribePipelineInfosRequest, server persist.API_SubscribePipelineInfosServer) (retErr error) {
 	defer func(start time.Time) { a.Log(request, nil, retErr, time.Since(start)) }(time.Now())
+	if err := a.opts.validateShard(request.Shard); err != nil {
+		return err
+	}
+	if err := a.addSubscription(); err != nil {
+		return err
+	}
+	defer a.subscriptions.Done()
 	query := a.getTerm(pipelineInfosTable)
 	if request.Shard != nil {
 		query = query.GetAllByIndex(pipelineShardIndex, request.Shard.Number)
@@ -354,27 +1186,174 @@ func (a *rethinkAPIServer) SubscribePipelineInfos(request *persist.SubscribePipe
 
 	cursor, err := query.Changes(gorethink.ChangesOpts{
 		IncludeInitial: request.IncludeInitial,
-	}).Run(a.session)
+	}).Run(a.changesSession)
 	if err != nil {
 		return err
 	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-a.drain:
+			// this makes cursor.Next below return false
+			_ = cursor.Close()
+		case <-server.Context().Done():
+			_ = cursor.Close()
+		case <-done:
+		}
+	}()
 
-	var change PipelineChangeFeed
-	for cursor.Next(&change) {
-		if change.NewVal != nil {
-			server.Send(&persist.PipelineInfoChange{
-				Pipeline: change.NewVal,
-			})
-		} else if change.OldVal != nil {
-			server.Send(&persist.PipelineInfoChange{
-				Pipeline: change.OldVal,
-				Removed:  true,
-			})
-		} else {
-			return fmt.Errorf("neither old_val nor new_val was present in the changefeed; this is likely a bug")
+	changes, errCh := cursorPipelineInfoChanges(cursor, a.opts.subscribeBufferSize())
+	if request.CoalesceMilliseconds != 0 {
+		return coalescePipelineInfoChanges(changes, errCh, server, time.Duration(request.CoalesceMilliseconds)*time.Millisecond)
+	}
+	for pipelineInfoChange := range changes {
+		server.Send(pipelineInfoChange)
+	}
+	return <-errCh
+}
+
+func (a *rethinkAPIServer) WaitPipelineDeleted(ctx context.Context, request *persist.WaitPipelineDeletedRequest) (response *google_protobuf.Empty, retErr error) {
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	if request.Pipeline == nil {
+		return nil, newValidationError("Pipeline", "request.Pipeline should be set")
+	}
+	if err := a.addSubscription(); err != nil {
+		return nil, err
+	}
+	defer a.subscriptions.Done()
+	// The initial value has a null new_val if the pipeline is already gone,
+	// after that so does its removal.
+	cursor, err := a.getTerm(pipelineInfosTable).
+		Get(request.Pipeline.Name).
+		Changes(gorethink.ChangesOpts{
+			IncludeInitial: true,
+		}).
+		Filter(gorethink.Row.Field("new_val").Eq(nil)).
+		Run(a.changesSession)
+	if err != nil {
+		return nil, err
+	}
+	var timeout <-chan time.Time
+	if request.TimeoutMilliseconds != 0 {
+		timer := time.NewTimer(time.Duration(request.TimeoutMilliseconds) * time.Millisecond)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	done := make(chan bool, 1)
+	go func() {
+		var change PipelineChangeFeed
+		done <- cursor.Next(&change)
+	}()
+	select {
+	case deleted := <-done:
+		if err := cursor.Close(); err != nil {
+			return nil, err
+		}
+		if !deleted {
+			if err := cursor.Err(); err != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("changefeed for pipeline %s ended; this is likely a bug", request.Pipeline.Name)
+		}
+		return google_protobuf.EmptyInstance, nil
+	case <-timeout:
+		retErr = ErrTimeout
+	case <-ctx.Done():
+		retErr = ctx.Err()
+	case <-a.drain:
+		retErr = ErrDraining
+	}
+	// this makes cursor.Next above return
+	_ = cursor.Close()
+	<-done
+	return nil, retErr
+}
+
+// cursorPipelineInfoChanges reads the changefeed in cursor into changes,
+// which is closed once the cursor ends, errCh then gets its error. changes
+// holds up to buffer changes, reading the cursor doesn't wait for a slow
+// subscriber so rethink doesn't queue them instead, if it fills up the
+// cursor is closed and errCh gets ErrSubscriberBehind.
+func cursorPipelineInfoChanges(cursor *gorethink.Cursor, buffer int) (<-chan *persist.PipelineInfoChange, <-chan error) {
+	changes := make(chan *persist.PipelineInfoChange, buffer)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(changes)
+		for {
+			var change PipelineChangeFeed
+			if !cursor.Next(&change) {
+				errCh <- cursor.Err()
+				return
+			}
+			pipelineInfoChange, err := newPipelineInfoChange(&change)
+			if err != nil {
+				_ = cursor.Close()
+				errCh <- err
+				return
+			}
+			select {
+			case changes <- pipelineInfoChange:
+			default:
+				_ = cursor.Close()
+				errCh <- ErrSubscriberBehind
+				return
+			}
+		}
+	}()
+	return changes, errCh
+}
+
+// coalescePipelineInfoChanges sends changes to server, but holds them for
+// window first so that several changes to one pipeline are only sent once,
+// with the pipeline's latest state. Once changes is closed it returns the
+// error from errCh.
+func coalescePipelineInfoChanges(changes <-chan *persist.PipelineInfoChange, errCh <-chan error, server persist.API_SubscribePipelineInfosServer, window time.Duration) error {
+	pending := make(map[string]*persist.PipelineInfoChange)
+	// order is the order in which pipelines first changed in this window
+	var order []string
+	var flush <-chan time.Time
+	send := func() {
+		for _, pipelineName := range order {
+			server.Send(pending[pipelineName])
+		}
+		pending = make(map[string]*persist.PipelineInfoChange)
+		order = nil
+		flush = nil
+	}
+	for {
+		select {
+		case pipelineInfoChange, ok := <-changes:
+			if !ok {
+				send()
+				return <-errCh
+			}
+			pipelineName := pipelineInfoChange.Pipeline.PipelineName
+			if _, ok := pending[pipelineName]; !ok {
+				order = append(order, pipelineName)
+			}
+			pending[pipelineName] = pipelineInfoChange
+			if flush == nil {
+				flush = time.After(window)
+			}
+		case <-flush:
+			send()
 		}
 	}
-	return cursor.Err()
+}
+
+func newPipelineInfoChange(change *PipelineChangeFeed) (*persist.PipelineInfoChange, error) {
+	if change.NewVal != nil {
+		return &persist.PipelineInfoChange{
+			Pipeline: change.NewVal,
+		}, nil
+	} else if change.OldVal != nil {
+		return &persist.PipelineInfoChange{
+			Pipeline: change.OldVal,
+			Removed:  true,
+		}, nil
+	}
+	return nil, fmt.Errorf("neither old_val nor new_val was present in the changefeed; this is likely a bug")
 }
 
 func (a *rethinkAPIServer) StartPod(ctx context.Context, request *ppsclient.Job) (response *persist.JobInfo, retErr error) {
@@ -392,15 +1371,84 @@ func (a *rethinkAPIServer) FailPod(ctx context.Context, request *ppsclient.Job)
 	return a.shardOp(ctx, request, "PodsFailed")
 }
 
+func (a *rethinkAPIServer) GetPodCounters(ctx context.Context, request *ppsclient.Job) (response *persist.PodCounters, retErr error) {
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	cursor, err := a.run(ctx, a.getTerm(jobInfosTable).Get(request.ID))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := cursor.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+	jobInfo := &persist.JobInfo{}
+	if err := cursor.One(jobInfo); err != nil {
+		if err == gorethink.ErrEmptyResult {
+			return nil, ErrJobNotFound
+		}
+		return nil, err
+	}
+	return &persist.PodCounters{
+		PodsStarted:   jobInfo.PodsStarted,
+		PodsSucceeded: jobInfo.PodsSucceeded,
+		PodsFailed:    jobInfo.PodsFailed,
+	}, nil
+}
+
+func (a *rethinkAPIServer) ResetPodCounters(ctx context.Context, request *ppsclient.Job) (response *google_protobuf.Empty, retErr error) {
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	writeResponse, err := a.runWrite(ctx, a.getTerm(jobInfosTable).Get(request.ID).Update(map[string]interface{}{
+		"PodsStarted":   0,
+		"PodsSucceeded": 0,
+		"PodsFailed":    0,
+		"UpdatedAt":     a.now(),
+	}))
+	if err != nil {
+		return nil, err
+	}
+	if writeResponse.Skipped > 0 {
+		return nil, ErrJobNotFound
+	}
+	return google_protobuf.EmptyInstance, nil
+}
+
+// Ping doesn't read any tables, so the round trip is mostly the network and
+// rethink's query queue.
+func (a *rethinkAPIServer) Ping(ctx context.Context, request *google_protobuf.Empty) (response *persist.PingResponse, retErr error) {
+	defer func(start time.Time) { a.Log(request, response, retErr, time.Since(start)) }(time.Now())
+	start := time.Now()
+	cursor, err := a.run(ctx, gorethink.Now())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := cursor.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+	var now time.Time
+	if err := cursor.One(&now); err != nil {
+		return nil, err
+	}
+	return &persist.PingResponse{RoundTrip: prototime.DurationToProto(time.Since(start))}, nil
+}
+
 func (a *rethinkAPIServer) shardOp(ctx context.Context, request *ppsclient.Job, field string) (response *persist.JobInfo, retErr error) {
-	cursor, err := a.getTerm(jobInfosTable).Get(request.ID).Update(map[string]interface{}{
-		field: gorethink.Row.Field(field).Add(1).Default(0),
+	cursor, err := a.run(ctx, a.getTerm(jobInfosTable).Get(request.ID).Update(map[string]interface{}{
+		field:       gorethink.Row.Field(field).Add(1).Default(0),
+		"UpdatedAt": a.now(),
 	}, gorethink.UpdateOpts{
 		ReturnChanges: true,
-	}).Field("changes").Field("new_val").Run(a.session)
+	}).Field("changes").Field("new_val"))
 	if err != nil {
 		return nil, err
 	}
+	defer func() {
+		if err := cursor.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
 
 	var jobInfo persist.JobInfo
 	success := cursor.Next(&jobInfo)
@@ -411,49 +1459,93 @@ func (a *rethinkAPIServer) shardOp(ctx context.Context, request *ppsclient.Job,
 	return &jobInfo, nil
 }
 
-func (a *rethinkAPIServer) insertMessage(table Table, message proto.Message) error {
-	_, err := a.getTerm(table).Insert(message).RunWrite(a.session)
+func (a *rethinkAPIServer) insertMessage(ctx context.Context, table Table, message proto.Message) error {
+	_, err := a.runWrite(ctx, a.getTerm(table).Insert(message))
 	return err
 }
 
-func (a *rethinkAPIServer) updateMessage(table Table, message proto.Message) error {
-	_, err := a.getTerm(table).Insert(message, gorethink.InsertOpts{Conflict: "update"}).RunWrite(a.session)
+// insertUniqueMessage is like insertMessage but returns errExists if table
+// already has a message with the same primary key.
+func (a *rethinkAPIServer) insertUniqueMessage(ctx context.Context, table Table, message proto.Message, errExists error) error {
+	response, err := a.runWrite(ctx, a.getTerm(table).Insert(message, gorethink.InsertOpts{Conflict: "error"}))
+	if response.Errors > 0 && strings.HasPrefix(response.FirstError, duplicatePrimaryKeyError) {
+		return errExists
+	}
 	return err
 }
 
-func (a *rethinkAPIServer) getMessageByPrimaryKey(table Table, key interface{}, message proto.Message) error {
-	cursor, err := a.getTerm(table).Get(key).Default(gorethink.Error("value not found")).Run(a.session)
+// updateExistingMessage merges message into the document with primary key
+// key, returning errNotFound rather than creating the document if it doesn't
+// exist. If message changes the document its UpdatedAt is set to now.
+func (a *rethinkAPIServer) updateExistingMessage(ctx context.Context, table Table, key interface{}, message proto.Message, errNotFound error) (*persist.WriteSummary, error) {
+	response, err := a.runWrite(ctx, a.getTerm(table).Get(key).Update(func(document gorethink.Term) gorethink.Term {
+		return gorethink.Branch(
+			document.Merge(message).Eq(document),
+			map[string]interface{}{},
+			gorethink.Expr(message).Merge(map[string]interface{}{"UpdatedAt": a.now()}),
+		)
+	}))
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if cursor.Next(message) {
-		return cursor.Err()
+	if response.Skipped > 0 {
+		return nil, errNotFound
 	}
-	return nil
+	return newWriteSummary(response), nil
 }
 
-func (a *rethinkAPIServer) deleteMessageByPrimaryKey(table Table, value interface{}) (retErr error) {
-	_, err := a.getTerm(table).Get(value).Delete().RunWrite(a.session)
-	return err
+// getMessageByPrimaryKey reads the document at key into message, or returns
+// errNotFound if there isn't one.
+func (a *rethinkAPIServer) getMessageByPrimaryKey(ctx context.Context, table Table, key interface{}, message proto.Message, errNotFound error) (retErr error) {
+	cursor, err := a.run(ctx, a.getTerm(table).Get(key).Default(gorethink.Error(valueNotFoundError)))
+	if err != nil {
+		return notFoundError(err, errNotFound)
+	}
+	defer func() {
+		if err := cursor.Close(); err != nil && retErr == nil {
+			retErr = err
+		}
+	}()
+	cursor.Next(message)
+	return notFoundError(cursor.Err(), errNotFound)
+}
+
+func (a *rethinkAPIServer) deleteMessageByPrimaryKey(ctx context.Context, table Table, value interface{}) (*persist.WriteSummary, error) {
+	response, err := a.runWrite(ctx, a.getTerm(table).Get(value).Delete())
+	if err != nil {
+		return nil, err
+	}
+	return newWriteSummary(response), nil
+}
+
+func newWriteSummary(response gorethink.WriteResponse) *persist.WriteSummary {
+	return &persist.WriteSummary{
+		Inserted:  uint64(response.Inserted),
+		Replaced:  uint64(response.Replaced),
+		Deleted:   uint64(response.Deleted),
+		Unchanged: uint64(response.Unchanged),
+	}
 }
 
 func (a *rethinkAPIServer) waitMessageByPrimaryKey(
+	ctx context.Context,
 	table Table,
 	key interface{},
 	message proto.Message,
+	errNotFound error,
 	predicate func(term gorethink.Term) gorethink.Term,
 ) (retErr error) {
 	term := a.getTerm(table).
 		Get(key).
-		Default(gorethink.Error("value not found")).
+		Default(gorethink.Error(valueNotFoundError)).
 		Changes(gorethink.ChangesOpts{
 			IncludeInitial: true,
 		}).
 		Field("new_val").
 		Filter(predicate)
-	cursor, err := term.Run(a.session)
+	cursor, err := runCursor(ctx, a.changesSession, term)
 	if err != nil {
-		return err
+		return notFoundError(err, errNotFound)
 	}
 	defer func() {
 		if err := cursor.Close(); err != nil && retErr == nil {
@@ -461,24 +1553,252 @@ func (a *rethinkAPIServer) waitMessageByPrimaryKey(
 		}
 	}()
 	cursor.Next(message)
-	return cursor.Err()
+	return notFoundError(cursor.Err(), errNotFound)
+}
+
+// notFoundError returns errNotFound in place of err if err is the
+// valueNotFoundError rethink raises for a missing document.
+func notFoundError(err error, errNotFound error) error {
+	if err != nil && strings.Contains(err.Error(), valueNotFoundError) {
+		return errNotFound
+	}
+	return err
+}
+
+// run runs term on a.session. Besides opts.QueryTimeout the query is bounded
+// by ctx, see runCursor.
+func (a *rethinkAPIServer) run(ctx context.Context, term gorethink.Term) (*cursor, error) {
+	return runCursor(ctx, a.session, term)
+}
+
+// runWrite is like run for writes. Once rethink has a write ctx can't take
+// it back, so ctx is only checked before it's sent.
+func (a *rethinkAPIServer) runWrite(ctx context.Context, term gorethink.Term) (gorethink.WriteResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return gorethink.WriteResponse{}, err
+	}
+	return term.RunWrite(a.session)
+}
+
+// runCursor runs term on session, returning ctx's error rather than waiting
+// on rethink once ctx is done. The vendored driver can't pass ctx on, so
+// the query itself only stops when the cursor is closed, which the returned
+// cursor does once ctx is done.
+func runCursor(ctx context.Context, session *gorethink.Session, term gorethink.Term) (*cursor, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	type result struct {
+		cursor *gorethink.Cursor
+		err    error
+	}
+	results := make(chan result, 1)
+	go func() {
+		c, err := term.Run(session)
+		results <- result{c, err}
+	}()
+	select {
+	case r := <-results:
+		if r.err != nil {
+			return nil, r.err
+		}
+		return newCursor(ctx, r.cursor), nil
+	case <-ctx.Done():
+		go func() {
+			if r := <-results; r.err == nil {
+				_ = r.cursor.Close()
+			}
+		}()
+		return nil, ctx.Err()
+	}
+}
+
+// cursor is a gorethink.Cursor that's closed once ctx is done, so that
+// reading it returns, Err and One return ctx's error then. It has to be
+// closed to stop watching ctx.
+type cursor struct {
+	*gorethink.Cursor
+	ctx       context.Context
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newCursor(ctx context.Context, c *gorethink.Cursor) *cursor {
+	result := &cursor{
+		Cursor: c,
+		ctx:    ctx,
+		done:   make(chan struct{}),
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			// this makes Next return false
+			_ = c.Close()
+		case <-result.done:
+		}
+	}()
+	return result
+}
+
+func (c *cursor) Close() error {
+	c.closeOnce.Do(func() { close(c.done) })
+	return c.Cursor.Close()
+}
+
+func (c *cursor) Err() error {
+	if err := c.ctx.Err(); err != nil {
+		return err
+	}
+	return c.Cursor.Err()
+}
+
+func (c *cursor) One(result interface{}) error {
+	err := c.Cursor.One(result)
+	if ctxErr := c.ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
 }
 
 func (a *rethinkAPIServer) getTerm(table Table) gorethink.Term {
-	return gorethink.DB(a.databaseName).Table(table)
+	return gorethink.DB(a.databaseName).Table(a.opts.table(table))
 }
 
 func (a *rethinkAPIServer) now() *google_protobuf.Timestamp {
 	return prototime.TimeToTimestamp(a.timer.Now())
 }
 
-func connect(address string) (*gorethink.Session, error) {
+// checkIndexes returns an error naming any of indexes that table doesn't have,
+// which happens when the database was initialized by an older version.
+func checkIndexes(session *gorethink.Session, databaseName string, table Table, indexes []Index) error {
+	cursor, err := gorethink.DB(databaseName).Table(table).IndexList().Run(session)
+	if err != nil {
+		return err
+	}
+	var existing []string
+	if err := cursor.All(&existing); err != nil {
+		return err
+	}
+	existingSet := make(map[string]bool)
+	for _, index := range existing {
+		existingSet[index] = true
+	}
+	var missing []string
+	for _, index := range indexes {
+		if !existingSet[string(index)] {
+			missing = append(missing, string(index))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("table %s in database %s is missing indexes %s, it was probably initialized by an older version",
+			table, databaseName, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func hasDB(session *gorethink.Session, databaseName string) (bool, error) {
+	cursor, err := gorethink.DBList().Contains(databaseName).Run(session)
+	if err != nil {
+		return false, err
+	}
+	var result bool
+	if err := cursor.One(&result); err != nil {
+		return false, err
+	}
+	return result, nil
+}
+
+func connect(address string, opts *Options) (*gorethink.Session, error) {
 	return gorethink.Connect(gorethink.ConnectOpts{
-		Address: address,
-		Timeout: connectTimeoutSeconds * time.Second,
+		Address:   address,
+		Timeout:   connectTimeoutSeconds * time.Second,
+		TLSConfig: opts.TLSConfig,
+		AuthKey:   opts.AuthKey,
 	})
 }
 
+// connectWithQueryTimeout is like connect, but reads and writes on the
+// connection time out after opts.QueryTimeout. The vendored driver can't
+// take a context per query, so this is what bounds how long a query holds
+// its connection, see runCursor for the RPC's ctx.
+func connectWithQueryTimeout(address string, opts *Options) (*gorethink.Session, error) {
+	return gorethink.Connect(gorethink.ConnectOpts{
+		Address:      address,
+		Timeout:      connectTimeoutSeconds * time.Second,
+		ReadTimeout:  opts.QueryTimeout,
+		WriteTimeout: opts.QueryTimeout,
+		TLSConfig:    opts.TLSConfig,
+		AuthKey:      opts.AuthKey,
+	})
+}
+
+// timestampKey is how Timestamp fields are indexed and compared, rethink
+// can't do either with the objects directly.
+func timestampKey(timestamp gorethink.Term) gorethink.Term {
+	return gorethink.Expr([]interface{}{
+		timestamp.Field("Seconds"),
+		timestamp.Field("Nanos"),
+	})
+}
+
+// Since tokens are the UpdatedAt of the latest job a client has seen, as
+// "seconds.nanos".
+func parseSinceToken(token string) ([]interface{}, error) {
+	var seconds int64
+	var nanos int32
+	if _, err := fmt.Sscanf(token, "%d.%d", &seconds, &nanos); err != nil {
+		return nil, newValidationError("SinceToken", fmt.Sprintf("request.SinceToken %q is invalid", token))
+	}
+	return []interface{}{seconds, nanos}, nil
+}
+
+// nextSinceToken returns the token for the latest UpdatedAt in jobInfos, or
+// token if none of them are newer.
+func nextSinceToken(token string, jobInfos []*persist.JobInfo) string {
+	var latest *google_protobuf.Timestamp
+	for _, jobInfo := range jobInfos {
+		updatedAt := jobInfo.UpdatedAt
+		if updatedAt == nil {
+			continue
+		}
+		if latest == nil || updatedAt.Seconds > latest.Seconds ||
+			(updatedAt.Seconds == latest.Seconds && updatedAt.Nanos > latest.Nanos) {
+			latest = updatedAt
+		}
+	}
+	if latest == nil {
+		return token
+	}
+	return fmt.Sprintf("%d.%09d", latest.Seconds, latest.Nanos)
+}
+
+// sameJobInfo returns true if i and j are the same apart from their CreatedAt
+// and UpdatedAt, which CreateJobInfo sets itself, see
+// Options.IdempotentCreate.
+func sameJobInfo(i *persist.JobInfo, j *persist.JobInfo) bool {
+	i = proto.Clone(i).(*persist.JobInfo)
+	j = proto.Clone(j).(*persist.JobInfo)
+	i.CreatedAt, i.UpdatedAt = nil, nil
+	j.CreatedAt, j.UpdatedAt = nil, nil
+	return proto.Equal(i, j)
+}
+
+// jobCommitIndex returns the commit index of jobInfo's inputs, or "" if
+// jobInfo.SkipCommitIndex is set.
+func jobCommitIndex(jobInfo *persist.JobInfo) (string, error) {
+	if jobInfo.SkipCommitIndex {
+		return "", nil
+	}
+	var commits []*pfs.Commit
+	for _, input := range jobInfo.Inputs {
+		commits = append(commits, input.Commit)
+	}
+	return genCommitIndex(commits)
+}
+
+// genCommitIndex sorts the commits' truncated IDs before joining them, so the
+// index doesn't depend on input order and ranges of it can be queried, see
+// GetJobInfosByCommitRange.
 func genCommitIndex(commits []*pfs.Commit) (string, error) {
 	var commitIDs []string
 	for _, commit := range commits {
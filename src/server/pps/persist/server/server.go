@@ -1,22 +1,157 @@
 package server
 
 import (
+	"crypto/tls"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/pachyderm/pachyderm/src/server/pps/persist"
+	"golang.org/x/net/context"
 )
 
 var (
-	ErrIDSet        = errors.New("pachyderm.pps.persist.server: ID set")
-	ErrIDNotSet     = errors.New("pachyderm.pps.persist.server: ID not set")
-	ErrTimestampSet = errors.New("pachyderm.pps.persist.server: Timestamp set")
+	ErrIDSet            = errors.New("pachyderm.pps.persist.server: ID set")
+	ErrIDNotSet         = errors.New("pachyderm.pps.persist.server: ID not set")
+	ErrTimestampSet     = newValidationError("CreatedAt", "request.CreatedAt should be unset")
+	ErrJobExists        = errors.New("pachyderm.pps.persist.server: Job exists")
+	ErrJobNotFound      = errors.New("pachyderm.pps.persist.server: Job not found")
+	ErrJobStateMismatch = errors.New("pachyderm.pps.persist.server: Job state mismatch")
+	ErrPipelineNotFound = errors.New("pachyderm.pps.persist.server: Pipeline not found")
+	// ErrPipelineVersionMismatch is returned by UpdatePipelineInfo when the
+	// pipeline was updated since the caller read it.
+	ErrPipelineVersionMismatch = errors.New("pachyderm.pps.persist.server: Pipeline version mismatch")
+	ErrDraining                = errors.New("pachyderm.pps.persist.server: draining")
+	ErrTimeout                 = errors.New("pachyderm.pps.persist.server: timed out")
+	ErrImportNotAllowed        = errors.New("pachyderm.pps.persist.server: import not allowed")
+	// ErrSubscriberBehind ends a SubscribePipelineInfos whose subscriber
+	// fell more than Options.SubscribeBufferSize changes behind, it should
+	// subscribe again with IncludeInitial to get the latest state.
+	ErrSubscriberBehind = errors.New("pachyderm.pps.persist.server: subscriber fell behind")
 )
 
+// ValidationError is returned when a field of a request is invalid. Field is
+// the name of the field in the request message, so callers can handle it (e.g.
+// as codes.InvalidArgument) without parsing Message. NewGRPCAPIServer sends
+// it to gRPC clients as codes.InvalidArgument.
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func newValidationError(field string, message string) error {
+	return &ValidationError{Field: field, Message: message}
+}
+
 type APIServer interface {
 	persist.APIServer
 	Close() error
+	// Drain is a graceful Close, it ends subscriptions and waits for them to
+	// return, or for ctx to be done, before closing.
+	Drain(ctx context.Context) error
+}
+
+// Options configure how the rethink server lays out its tables, the same
+// Options must be passed to InitDBs, CheckDBs and NewRethinkAPIServer. nil
+// means the defaults.
+type Options struct {
+	// TablePrefix is prepended to every table name, so that several
+	// instances can share one database.
+	TablePrefix string
+	// TLSConfig, if set, is used to connect to RethinkDB over TLS.
+	TLSConfig *tls.Config
+	// AuthKey is sent when connecting to a RethinkDB that requires one. The
+	// vendored driver predates RethinkDB users, so there's no username or
+	// password.
+	AuthKey string
+	// AllowImport enables ImportJobInfo, which keeps the caller's CreatedAt
+	// so that job history can be backfilled. It's off by default because
+	// imported jobs can claim any creation time.
+	AllowImport bool
+	// IdempotentCreate makes CreateJobInfo and ImportJobInfo of a JobID
+	// that already exists succeed, returning the existing job, if it's the
+	// same as the one being created apart from CreatedAt and UpdatedAt. So
+	// a create that timed out can be retried. Jobs that differ still get
+	// ErrJobExists.
+	IdempotentCreate bool
+	// QueryTimeout, if set, bounds how long the server waits on rethink for
+	// each query, changefeeds aren't affected. InitDBs and CheckDBs ignore
+	// it. Whether or not it's set an RPC stops waiting once its ctx is
+	// done.
+	QueryTimeout time.Duration
+	// SubscribeBufferSize is how many pipeline changes SubscribePipelineInfos
+	// holds for a subscriber that isn't keeping up, once they're full the
+	// subscription ends with ErrSubscriberBehind rather than holding more.
+	// With IncludeInitial the current pipelines count too, so it should be
+	// well above the number of pipelines. 0 means
+	// defaultSubscribeBufferSize.
+	SubscribeBufferSize int
+	// NumShards is the number of shards pipelines are hashed into,
+	// ListPipelineInfos and SubscribePipelineInfos reject shard numbers past
+	// it. 0 means shard numbers aren't checked.
+	NumShards uint64
+	// JobRetention, if set, makes the server delete the jobs that were
+	// created more than JobRetention ago, it checks every PurgeInterval. 0
+	// keeps jobs forever.
+	JobRetention time.Duration
+	// PurgeInterval is how often jobs past JobRetention are deleted, 0 means
+	// defaultPurgeInterval.
+	PurgeInterval time.Duration
+}
+
+const (
+	// defaultSubscribeBufferSize is used when Options.SubscribeBufferSize
+	// isn't set.
+	defaultSubscribeBufferSize = 4096
+	// defaultPurgeInterval is used when Options.PurgeInterval isn't set.
+	defaultPurgeInterval = time.Hour
+	// purgeBatchSize is how many jobs are deleted per write when purging,
+	// so a purge doesn't hold up other writes to the table for long.
+	purgeBatchSize = 1000
+)
+
+func (o *Options) table(table Table) Table {
+	return Table(o.TablePrefix) + table
+}
+
+func (o *Options) subscribeBufferSize() int {
+	if o.SubscribeBufferSize != 0 {
+		return o.SubscribeBufferSize
+	}
+	return defaultSubscribeBufferSize
+}
+
+func (o *Options) purgeInterval() time.Duration {
+	if o.PurgeInterval != 0 {
+		return o.PurgeInterval
+	}
+	return defaultPurgeInterval
+}
+
+// validateShard returns a validation error if shard is past o.NumShards, it
+// would otherwise match no pipelines.
+func (o *Options) validateShard(shard *persist.Shard) error {
+	if shard == nil || o.NumShards == 0 || shard.Number < o.NumShards {
+		return nil
+	}
+	return newValidationError(
+		"Shard",
+		fmt.Sprintf("request.Shard.Number should be less than %d, got %d", o.NumShards, shard.Number),
+	)
+}
+
+func NewRethinkAPIServer(address string, databaseName string, opts *Options) (APIServer, error) {
+	return newRethinkAPIServer(address, databaseName, opts)
 }
 
-func NewRethinkAPIServer(address string, databaseName string) (APIServer, error) {
-	return newRethinkAPIServer(address, databaseName)
+// NewMemoryAPIServer returns a server that keeps everything in memory, for
+// tests that would otherwise need a RethinkDB. Of opts only AllowImport,
+// IdempotentCreate, SubscribeBufferSize, NumShards, JobRetention and
+// PurgeInterval are used.
+func NewMemoryAPIServer(opts *Options) APIServer {
+	return newMemoryAPIServer(opts)
 }
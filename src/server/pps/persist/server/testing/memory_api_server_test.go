@@ -0,0 +1,248 @@
+package testing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pachyderm/pachyderm/src/client/pkg/require"
+	"github.com/pachyderm/pachyderm/src/client/pkg/uuid"
+	"github.com/pachyderm/pachyderm/src/server/pps/persist"
+	"github.com/pachyderm/pachyderm/src/server/pps/persist/server"
+	"go.pedge.io/proto/time"
+	"golang.org/x/net/context"
+)
+
+// The memory server runs the same tests as the rethink server, except for
+// the ones about rethink itself (table prefixes, query timeouts, indexes).
+
+func TestMemoryBasicRethink(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testBasicRethink)
+}
+
+func TestMemoryBlock(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testBlock)
+}
+
+func TestMemoryGetPipelineInfos(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testGetPipelineInfos)
+}
+
+func TestMemoryCreateJobInfoTwice(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testCreateJobInfoTwice)
+}
+
+func TestMemoryListPipelineInfosNamePrefix(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testListPipelineInfosNamePrefix)
+}
+
+func TestMemoryRecentJobInfos(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testRecentJobInfos)
+}
+
+func TestMemoryUpdateMissingJob(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testUpdateMissingJob)
+}
+
+func TestMemoryTransitionJobState(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testTransitionJobState)
+}
+
+func TestMemoryListJobInfosHasOutput(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testListJobInfosHasOutput)
+}
+
+func TestMemoryPodCounters(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testPodCounters)
+}
+
+func TestMemoryCoalescePipelineInfoChanges(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testCoalescePipelineInfoChanges)
+}
+
+func TestMemoryListJobInfosPipelines(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testListJobInfosPipelines)
+}
+
+func TestMemoryValidationErrors(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testValidationErrors)
+}
+
+func TestMemoryListJobInfosLatestPerPipeline(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testListJobInfosLatestPerPipeline)
+}
+
+func TestMemoryWaitPipelineDeleted(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testWaitPipelineDeleted)
+}
+
+func TestMemoryListJobInfosSinceToken(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testListJobInfosSinceToken)
+}
+
+func TestMemoryReindexJobInfos(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testReindexJobInfos)
+}
+
+func TestMemoryWriteSummaries(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testWriteSummaries)
+}
+
+func TestMemoryListJobInfosStream(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testListJobInfosStream)
+}
+
+func TestMemoryDeleteJobInfosByCommit(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testDeleteJobInfosByCommit)
+}
+
+func TestMemoryGetJobInfosByCommitRange(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testGetJobInfosByCommitRange)
+}
+
+func TestMemoryListPipelineInfosOrderBy(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testListPipelineInfosOrderBy)
+}
+
+func TestMemoryUpdatedAt(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testUpdatedAt)
+}
+
+func TestMemoryListJobInfosExcludePipelines(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testListJobInfosExcludePipelines)
+}
+
+func TestMemoryRestartJob(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testRestartJob)
+}
+
+func TestMemoryGetJobInfos(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testGetJobInfos)
+}
+
+func TestMemoryListJobInfosStateAndCreatedAfter(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testListJobInfosStateAndCreatedAfter)
+}
+
+func TestMemoryListPipelineInfosPages(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testListPipelineInfosPages)
+}
+
+func TestMemoryAggregateJobStates(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testAggregateJobStates)
+}
+
+func TestMemoryListJobInfosPipelineVersion(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testListJobInfosPipelineVersion)
+}
+
+func TestMemoryPing(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testPing)
+}
+
+func TestMemoryUpdatePipelineInfo(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testUpdatePipelineInfo)
+}
+
+func TestMemoryExportJobInfos(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testExportJobInfos)
+}
+
+func TestMemorySkipCommitIndex(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testSkipCommitIndex)
+}
+
+func TestMemoryListPipelineNames(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testListPipelineNames)
+}
+
+func TestMemoryDeletePipelineAndJobs(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testDeletePipelineAndJobs)
+}
+
+func TestMemoryNotFound(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testNotFound)
+}
+
+func TestMemoryListJobInfosOrder(t *testing.T) {
+	RunTestWithMemoryAPIServer(t, testListJobInfosOrder)
+}
+
+func TestMemoryImportJobInfo(t *testing.T) {
+	apiServer := server.NewMemoryAPIServer(&server.Options{AllowImport: true})
+	createdAt := prototime.TimeToTimestamp(time.Now().Add(-time.Hour))
+	jobInfo, err := apiServer.ImportJobInfo(
+		context.Background(),
+		&persist.JobInfo{
+			JobID:     uuid.NewWithoutDashes(),
+			CreatedAt: createdAt,
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, createdAt, jobInfo.UpdatedAt)
+	_, err = apiServer.ImportJobInfo(
+		context.Background(),
+		&persist.JobInfo{JobID: uuid.NewWithoutDashes()},
+	)
+	require.YesError(t, err)
+
+	_, err = server.NewMemoryAPIServer(nil).ImportJobInfo(
+		context.Background(),
+		&persist.JobInfo{
+			JobID:     uuid.NewWithoutDashes(),
+			CreatedAt: createdAt,
+		},
+	)
+	require.Equal(t, server.ErrImportNotAllowed, err)
+}
+
+func TestMemoryDrain(t *testing.T) {
+	apiServer := server.NewMemoryAPIServer(nil)
+	_, err := apiServer.CreatePipelineInfo(
+		context.Background(),
+		&persist.PipelineInfo{
+			PipelineName: "foo",
+		},
+	)
+	require.NoError(t, err)
+	subscribeServer := &subscribePipelineInfosServer{changes: make(chan *persist.PipelineInfoChange, 1)}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- apiServer.SubscribePipelineInfos(
+			&persist.SubscribePipelineInfosRequest{IncludeInitial: true},
+			subscribeServer,
+		)
+	}()
+	<-subscribeServer.changes
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	require.NoError(t, apiServer.Drain(ctx))
+	require.NoError(t, <-errCh)
+	require.Equal(t, server.ErrDraining, apiServer.SubscribePipelineInfos(
+		&persist.SubscribePipelineInfosRequest{},
+		subscribeServer,
+	))
+}
+
+func TestMemorySubscriberBehind(t *testing.T) {
+	testSubscriberBehind(t, server.NewMemoryAPIServer(&server.Options{SubscribeBufferSize: 1}))
+}
+
+func TestMemoryShardValidation(t *testing.T) {
+	testShardValidation(t, server.NewMemoryAPIServer(&server.Options{NumShards: 2}))
+}
+
+func TestMemoryCreateJobInfoRetry(t *testing.T) {
+	testCreateJobInfoRetry(t, server.NewMemoryAPIServer(&server.Options{IdempotentCreate: true}))
+}
+
+func TestMemoryJobRetention(t *testing.T) {
+	apiServer := server.NewMemoryAPIServer(&server.Options{
+		AllowImport:   true,
+		JobRetention:  time.Hour,
+		PurgeInterval: 10 * time.Millisecond,
+	})
+	defer func() {
+		require.NoError(t, apiServer.Close())
+	}()
+	testJobRetention(t, apiServer)
+}
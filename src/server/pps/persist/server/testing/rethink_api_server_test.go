@@ -1,25 +1,468 @@
 package testing
 
 import (
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/dancannon/gorethink"
+	"github.com/golang/protobuf/proto"
 	"github.com/pachyderm/pachyderm/src/client"
 	pfsclient "github.com/pachyderm/pachyderm/src/client/pfs"
 	"github.com/pachyderm/pachyderm/src/client/pkg/require"
 	"github.com/pachyderm/pachyderm/src/client/pkg/uuid"
 	ppsclient "github.com/pachyderm/pachyderm/src/client/pps"
 	"github.com/pachyderm/pachyderm/src/server/pps/persist"
+	"github.com/pachyderm/pachyderm/src/server/pps/persist/server"
+	"go.pedge.io/pb/go/google/protobuf"
+	"go.pedge.io/proto/time"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 )
 
 func TestBasicRethink(t *testing.T) {
 	t.Skip()
-	RunTestWithRethinkAPIServer(t, testBasicRethink)
+	RunTestWithRethinkAPIServer(t, nil, testBasicRethink)
 }
 
 func TestBlock(t *testing.T) {
 	t.Skip()
-	RunTestWithRethinkAPIServer(t, testBlock)
+	RunTestWithRethinkAPIServer(t, nil, testBlock)
+}
+
+func TestGetPipelineInfos(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testGetPipelineInfos)
+}
+
+func TestCreateJobInfoTwice(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testCreateJobInfoTwice)
+}
+
+func TestListPipelineInfosNamePrefix(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testListPipelineInfosNamePrefix)
+}
+
+func TestRecentJobInfos(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testRecentJobInfos)
+}
+
+func TestUpdateMissingJob(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testUpdateMissingJob)
+}
+
+func TestTransitionJobState(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testTransitionJobState)
+}
+
+func TestListJobInfosHasOutput(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testListJobInfosHasOutput)
+}
+
+func TestPodCounters(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testPodCounters)
+}
+
+func TestTablePrefix(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping test because of short mode.")
+	}
+	address := "0.0.0.0:28015"
+	databaseName := uuid.NewWithoutDashes()
+	var apiServers []server.APIServer
+	for _, prefix := range []string{"tenant1_", "tenant2_"} {
+		opts := &server.Options{TablePrefix: prefix}
+		require.NoError(t, server.InitDBs(address, databaseName, opts))
+		require.NoError(t, server.CheckDBs(address, databaseName, opts))
+		apiServer, err := server.NewRethinkAPIServer(address, databaseName, opts)
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, apiServer.Close())
+		}()
+		apiServers = append(apiServers, apiServer)
+	}
+	_, err := apiServers[0].CreatePipelineInfo(
+		context.Background(),
+		&persist.PipelineInfo{
+			PipelineName: "foo",
+		},
+	)
+	require.NoError(t, err)
+	_, err = apiServers[1].GetPipelineInfo(
+		context.Background(),
+		&ppsclient.Pipeline{Name: "foo"},
+	)
+	require.YesError(t, err)
+}
+
+func TestDrain(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping test because of short mode.")
+	}
+	apiServer, err := NewTestRethinkAPIServer(nil)
+	require.NoError(t, err)
+	_, err = apiServer.CreatePipelineInfo(
+		context.Background(),
+		&persist.PipelineInfo{
+			PipelineName: "foo",
+		},
+	)
+	require.NoError(t, err)
+	subscribeServer := &subscribePipelineInfosServer{changes: make(chan *persist.PipelineInfoChange, 1)}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- apiServer.SubscribePipelineInfos(
+			&persist.SubscribePipelineInfosRequest{IncludeInitial: true},
+			subscribeServer,
+		)
+	}()
+	// once we've seen the initial value the subscription is running
+	<-subscribeServer.changes
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	require.NoError(t, apiServer.Drain(ctx))
+	require.NoError(t, <-errCh)
+	require.Equal(t, server.ErrDraining, apiServer.SubscribePipelineInfos(
+		&persist.SubscribePipelineInfosRequest{},
+		subscribeServer,
+	))
+}
+
+func TestCoalescePipelineInfoChanges(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testCoalescePipelineInfoChanges)
+}
+
+type subscribePipelineInfosServer struct {
+	grpc.ServerStream
+	changes chan *persist.PipelineInfoChange
+}
+
+func (s *subscribePipelineInfosServer) Send(change *persist.PipelineInfoChange) error {
+	select {
+	case s.changes <- change:
+	default:
+	}
+	return nil
+}
+
+func TestListJobInfosPipelines(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testListJobInfosPipelines)
+}
+
+func TestValidationErrors(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testValidationErrors)
+}
+
+func TestListJobInfosLatestPerPipeline(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testListJobInfosLatestPerPipeline)
+}
+
+func TestWaitPipelineDeleted(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testWaitPipelineDeleted)
+}
+
+func TestListJobInfosSinceToken(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testListJobInfosSinceToken)
+}
+
+func TestReindexJobInfos(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testReindexJobInfos)
+}
+
+func TestWriteSummaries(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testWriteSummaries)
+}
+
+func TestListJobInfosStream(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testListJobInfosStream)
+}
+
+type listJobInfosStreamServer struct {
+	grpc.ServerStream
+	jobInfos []*persist.JobInfo
+}
+
+func (s *listJobInfosStreamServer) Send(jobInfo *persist.JobInfo) error {
+	s.jobInfos = append(s.jobInfos, jobInfo)
+	return nil
+}
+
+func TestImportJobInfo(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping test because of short mode.")
+	}
+	apiServer, err := NewTestRethinkAPIServer(&server.Options{AllowImport: true})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, apiServer.Close())
+	}()
+
+	createdAt := prototime.TimeToTimestamp(time.Now().Add(-time.Hour))
+	_, err = apiServer.ImportJobInfo(
+		context.Background(),
+		&persist.JobInfo{
+			JobID:     uuid.NewWithoutDashes(),
+			CreatedAt: createdAt,
+		},
+	)
+	require.NoError(t, err)
+	jobInfos, err := apiServer.ListJobInfos(context.Background(), &ppsclient.ListJobRequest{})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(jobInfos.JobInfo))
+	require.Equal(t, createdAt, jobInfos.JobInfo[0].CreatedAt)
+	require.Equal(t, createdAt, jobInfos.JobInfo[0].UpdatedAt)
+
+	// imports still need a timestamp, and creates still can't set one
+	_, err = apiServer.ImportJobInfo(
+		context.Background(),
+		&persist.JobInfo{JobID: uuid.NewWithoutDashes()},
+	)
+	require.YesError(t, err)
+	_, err = apiServer.CreateJobInfo(
+		context.Background(),
+		&persist.JobInfo{
+			JobID:     uuid.NewWithoutDashes(),
+			CreatedAt: createdAt,
+		},
+	)
+	require.YesError(t, err)
+
+	defaultAPIServer, err := NewTestRethinkAPIServer(nil)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, defaultAPIServer.Close())
+	}()
+	_, err = defaultAPIServer.ImportJobInfo(
+		context.Background(),
+		&persist.JobInfo{
+			JobID:     uuid.NewWithoutDashes(),
+			CreatedAt: createdAt,
+		},
+	)
+	require.Equal(t, server.ErrImportNotAllowed, err)
+}
+
+func TestDeleteJobInfosByCommit(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testDeleteJobInfosByCommit)
+}
+
+func TestGetJobInfosByCommitRange(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testGetJobInfosByCommitRange)
+}
+
+func TestListPipelineInfosOrderBy(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testListPipelineInfosOrderBy)
+}
+
+func TestUpdatedAt(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testUpdatedAt)
+}
+
+func TestListJobInfosExcludePipelines(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testListJobInfosExcludePipelines)
+}
+
+func TestRestartJob(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testRestartJob)
+}
+
+func TestGetJobInfos(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testGetJobInfos)
+}
+
+func TestListJobInfosStateAndCreatedAfter(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testListJobInfosStateAndCreatedAfter)
+}
+
+func TestListPipelineInfosPages(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testListPipelineInfosPages)
+}
+
+func TestAggregateJobStates(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testAggregateJobStates)
+}
+
+func TestListJobInfosPipelineVersion(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testListJobInfosPipelineVersion)
+}
+
+func TestPing(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testPing)
+}
+
+func TestUpdatePipelineInfo(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testUpdatePipelineInfo)
+}
+
+func TestExportJobInfos(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testExportJobInfos)
+}
+
+type exportJobInfosServer struct {
+	grpc.ServerStream
+	ctx      context.Context
+	jobInfos []*persist.JobInfo
+}
+
+func (s *exportJobInfosServer) Context() context.Context {
+	return s.ctx
+}
+
+func (s *exportJobInfosServer) Send(jobInfo *persist.JobInfo) error {
+	s.jobInfos = append(s.jobInfos, jobInfo)
+	return nil
+}
+
+func TestSkipCommitIndex(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testSkipCommitIndex)
+}
+
+func TestListPipelineNames(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testListPipelineNames)
+}
+
+func TestDeletePipelineAndJobs(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testDeletePipelineAndJobs)
+}
+
+func TestNotFound(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testNotFound)
+}
+
+func TestListJobInfosOrder(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, testListJobInfosOrder)
+}
+
+func TestSubscriberBehind(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, &server.Options{SubscribeBufferSize: 1}, testSubscriberBehind)
+}
+
+func TestShardValidation(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, &server.Options{NumShards: 2}, testShardValidation)
+}
+
+func TestJobRetention(t *testing.T) {
+	RunTestWithRethinkAPIServer(
+		t,
+		&server.Options{
+			AllowImport:   true,
+			JobRetention:  time.Hour,
+			PurgeInterval: 10 * time.Millisecond,
+		},
+		testJobRetention,
+	)
+}
+
+func TestCreateJobInfoRetry(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, &server.Options{IdempotentCreate: true}, testCreateJobInfoRetry)
+}
+
+// blockingSubscribeServer is a subscriber that doesn't take any changes
+// until release is closed, sending gets a value once the first one is sent.
+type blockingSubscribeServer struct {
+	grpc.ServerStream
+	sending chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingSubscribeServer) Send(change *persist.PipelineInfoChange) error {
+	select {
+	case s.sending <- struct{}{}:
+	default:
+	}
+	<-s.release
+	return nil
+}
+
+func TestQueryTimeout(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping test because of short mode.")
+	}
+	apiServer, err := NewTestRethinkAPIServer(&server.Options{QueryTimeout: 100 * time.Millisecond})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, apiServer.Close())
+	}()
+	_, err = apiServer.CreatePipelineInfo(
+		context.Background(),
+		&persist.PipelineInfo{
+			PipelineName: "foo",
+		},
+	)
+	require.NoError(t, err)
+	// changefeeds outlive the query timeout
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := apiServer.WaitPipelineDeleted(
+			context.Background(),
+			&persist.WaitPipelineDeletedRequest{
+				Pipeline: &ppsclient.Pipeline{Name: "foo"},
+			},
+		)
+		errCh <- err
+	}()
+	time.Sleep(500 * time.Millisecond)
+	_, err = apiServer.DeletePipelineInfo(context.Background(), &ppsclient.Pipeline{Name: "foo"})
+	require.NoError(t, err)
+	require.NoError(t, <-errCh)
+
+	// other queries don't, every query takes longer than a nanosecond
+	databaseName, err := newTestDatabase(nil)
+	require.NoError(t, err)
+	slowServer, err := server.NewRethinkAPIServer(testAddress, databaseName, &server.Options{QueryTimeout: time.Nanosecond})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, slowServer.Close())
+	}()
+	_, err = slowServer.Ping(context.Background(), google_protobuf.EmptyInstance)
+	require.YesError(t, err)
+}
+
+func TestQueryContext(t *testing.T) {
+	RunTestWithRethinkAPIServer(t, nil, func(t *testing.T, apiServer persist.APIServer) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := apiServer.CreateJobInfo(ctx, &persist.JobInfo{
+			JobID: uuid.NewWithoutDashes(),
+		})
+		require.Equal(t, context.Canceled, err)
+		_, err = apiServer.ListJobInfos(ctx, &ppsclient.ListJobRequest{})
+		require.Equal(t, context.Canceled, err)
+
+		// a running job never gets past BlockState, so only ctx ends this
+		jobInfo, err := apiServer.CreateJobInfo(context.Background(), &persist.JobInfo{
+			JobID: uuid.NewWithoutDashes(),
+		})
+		require.NoError(t, err)
+		ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		_, err = apiServer.InspectJob(ctx, &ppsclient.InspectJobRequest{
+			Job:        &ppsclient.Job{ID: jobInfo.JobID},
+			BlockState: true,
+		})
+		require.Equal(t, context.DeadlineExceeded, err)
+	})
+}
+
+func TestCheckDBsMissingIndex(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping test because of short mode.")
+	}
+	address := "0.0.0.0:28015"
+	databaseName := uuid.NewWithoutDashes()
+	require.NoError(t, server.InitDBs(address, databaseName, nil))
+	require.NoError(t, server.CheckDBs(address, databaseName, nil))
+	session, err := gorethink.Connect(gorethink.ConnectOpts{Address: address})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, session.Close())
+	}()
+	_, err = gorethink.DB(databaseName).Table("JobInfos").IndexDrop("UpdatedAt").RunWrite(session)
+	require.NoError(t, err)
+	err = server.CheckDBs(address, databaseName, nil)
+	require.YesError(t, err)
+	require.True(t, strings.Contains(err.Error(), "UpdatedAt"))
 }
 
 func testBasicRethink(t *testing.T, apiServer persist.APIServer) {
@@ -129,3 +572,1476 @@ func testBlock(t *testing.T, apiServer persist.APIServer) {
 	)
 	require.NoError(t, err)
 }
+
+func testGetPipelineInfos(t *testing.T, apiServer persist.APIServer) {
+	for _, name := range []string{"foo", "bar"} {
+		_, err := apiServer.CreatePipelineInfo(
+			context.Background(),
+			&persist.PipelineInfo{
+				PipelineName: name,
+			},
+		)
+		require.NoError(t, err)
+	}
+	response, err := apiServer.GetPipelineInfos(
+		context.Background(),
+		&persist.GetPipelineInfosRequest{
+			Pipeline: []*ppsclient.Pipeline{{Name: "bar"}, {Name: "buzz"}, {Name: "foo"}},
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(response.PipelineInfo))
+	require.Equal(t, "bar", response.PipelineInfo[0].PipelineName)
+	require.Equal(t, "foo", response.PipelineInfo[1].PipelineName)
+	require.Equal(t, 1, len(response.Missing))
+	require.Equal(t, "buzz", response.Missing[0].Name)
+}
+
+func testCreateJobInfoTwice(t *testing.T, apiServer persist.APIServer) {
+	jobInfo := &persist.JobInfo{
+		JobID:        uuid.NewWithoutDashes(),
+		PipelineName: "foo",
+		Inputs: []*ppsclient.JobInput{
+			{Commit: client.NewCommit("bar", uuid.NewWithoutDashes())},
+		},
+	}
+	_, err := apiServer.CreateJobInfo(context.Background(), proto.Clone(jobInfo).(*persist.JobInfo))
+	require.NoError(t, err)
+	_, err = apiServer.CreateJobInfo(context.Background(), proto.Clone(jobInfo).(*persist.JobInfo))
+	require.Equal(t, server.ErrJobExists, err)
+}
+
+func testListPipelineInfosNamePrefix(t *testing.T, apiServer persist.APIServer) {
+	for _, name := range []string{"team-a-foo", "team-a-bar", "team-b-foo", "team.a-foo"} {
+		_, err := apiServer.CreatePipelineInfo(
+			context.Background(),
+			&persist.PipelineInfo{
+				PipelineName: name,
+			},
+		)
+		require.NoError(t, err)
+	}
+	pipelineInfos, err := apiServer.ListPipelineInfos(
+		context.Background(),
+		&persist.ListPipelineInfosRequest{
+			NamePrefix: "team-a-",
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(pipelineInfos.PipelineInfo))
+	for _, pipelineInfo := range pipelineInfos.PipelineInfo {
+		require.True(t, strings.HasPrefix(pipelineInfo.PipelineName, "team-a-"))
+	}
+	// the prefix is literal, not a regex
+	pipelineInfos, err = apiServer.ListPipelineInfos(
+		context.Background(),
+		&persist.ListPipelineInfosRequest{
+			NamePrefix: "team.",
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(pipelineInfos.PipelineInfo))
+}
+
+func testRecentJobInfos(t *testing.T, apiServer persist.APIServer) {
+	var jobIDs []string
+	for i := 0; i < 5; i++ {
+		jobInfo, err := apiServer.CreateJobInfo(
+			context.Background(),
+			&persist.JobInfo{
+				JobID:        uuid.NewWithoutDashes(),
+				PipelineName: fmt.Sprintf("pipeline%d", i),
+				Inputs: []*ppsclient.JobInput{
+					{Commit: client.NewCommit("bar", uuid.NewWithoutDashes())},
+				},
+			},
+		)
+		require.NoError(t, err)
+		jobIDs = append(jobIDs, jobInfo.JobID)
+	}
+	jobInfos, err := apiServer.RecentJobInfos(
+		context.Background(),
+		&persist.RecentJobInfosRequest{N: 3},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(jobInfos.JobInfo))
+	for i, jobInfo := range jobInfos.JobInfo {
+		require.Equal(t, jobIDs[len(jobIDs)-1-i], jobInfo.JobID)
+	}
+
+	_, err = apiServer.RecentJobInfos(context.Background(), &persist.RecentJobInfosRequest{})
+	requireValidationError(t, "N", err)
+}
+
+func testUpdateMissingJob(t *testing.T, apiServer persist.APIServer) {
+	jobID := uuid.NewWithoutDashes()
+	_, err := apiServer.CreateJobOutput(
+		context.Background(),
+		&persist.JobOutput{
+			JobID:        jobID,
+			OutputCommit: client.NewCommit("foo", uuid.NewWithoutDashes()),
+		},
+	)
+	require.Equal(t, server.ErrJobNotFound, err)
+	_, err = apiServer.CreateJobState(
+		context.Background(),
+		&persist.JobState{
+			JobID: jobID,
+			State: ppsclient.JobState_JOB_STATE_SUCCESS,
+		},
+	)
+	require.Equal(t, server.ErrJobNotFound, err)
+	_, err = apiServer.InspectJob(
+		context.Background(),
+		&ppsclient.InspectJobRequest{
+			Job: &ppsclient.Job{ID: jobID},
+		},
+	)
+	require.YesError(t, err)
+}
+
+func testTransitionJobState(t *testing.T, apiServer persist.APIServer) {
+	jobInfo, err := apiServer.CreateJobInfo(
+		context.Background(),
+		&persist.JobInfo{
+			JobID:        uuid.NewWithoutDashes(),
+			PipelineName: "foo",
+			Inputs: []*ppsclient.JobInput{
+				{Commit: client.NewCommit("bar", uuid.NewWithoutDashes())},
+			},
+		},
+	)
+	require.NoError(t, err)
+	_, err = apiServer.TransitionJobState(
+		context.Background(),
+		&persist.JobStateTransition{
+			JobID: jobInfo.JobID,
+			From:  ppsclient.JobState_JOB_STATE_RUNNING,
+			To:    ppsclient.JobState_JOB_STATE_SUCCESS,
+		},
+	)
+	require.NoError(t, err)
+	// the job is no longer running so this shouldn't apply
+	_, err = apiServer.TransitionJobState(
+		context.Background(),
+		&persist.JobStateTransition{
+			JobID: jobInfo.JobID,
+			From:  ppsclient.JobState_JOB_STATE_RUNNING,
+			To:    ppsclient.JobState_JOB_STATE_FAILURE,
+		},
+	)
+	require.Equal(t, server.ErrJobStateMismatch, err)
+	jobInfo, err = apiServer.InspectJob(
+		context.Background(),
+		&ppsclient.InspectJobRequest{
+			Job: &ppsclient.Job{ID: jobInfo.JobID},
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, ppsclient.JobState_JOB_STATE_SUCCESS, jobInfo.State)
+	_, err = apiServer.TransitionJobState(
+		context.Background(),
+		&persist.JobStateTransition{
+			JobID: uuid.NewWithoutDashes(),
+		},
+	)
+	require.Equal(t, server.ErrJobNotFound, err)
+}
+
+func testListJobInfosHasOutput(t *testing.T, apiServer persist.APIServer) {
+	var jobIDs []string
+	for i := 0; i < 2; i++ {
+		jobInfo, err := apiServer.CreateJobInfo(
+			context.Background(),
+			&persist.JobInfo{
+				JobID:        uuid.NewWithoutDashes(),
+				PipelineName: "foo",
+				Inputs: []*ppsclient.JobInput{
+					{Commit: client.NewCommit("bar", uuid.NewWithoutDashes())},
+				},
+			},
+		)
+		require.NoError(t, err)
+		jobIDs = append(jobIDs, jobInfo.JobID)
+	}
+	_, err := apiServer.CreateJobOutput(
+		context.Background(),
+		&persist.JobOutput{
+			JobID:        jobIDs[0],
+			OutputCommit: client.NewCommit("foo", uuid.NewWithoutDashes()),
+		},
+	)
+	require.NoError(t, err)
+	jobInfos, err := apiServer.ListJobInfos(
+		context.Background(),
+		&ppsclient.ListJobRequest{
+			Pipeline:  &ppsclient.Pipeline{Name: "foo"},
+			HasOutput: true,
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(jobInfos.JobInfo))
+	require.Equal(t, jobIDs[0], jobInfos.JobInfo[0].JobID)
+	jobInfos, err = apiServer.ListJobInfos(
+		context.Background(),
+		&ppsclient.ListJobRequest{
+			Pipeline: &ppsclient.Pipeline{Name: "foo"},
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(jobInfos.JobInfo))
+}
+
+func testPodCounters(t *testing.T, apiServer persist.APIServer) {
+	jobInfo, err := apiServer.CreateJobInfo(
+		context.Background(),
+		&persist.JobInfo{
+			JobID:        uuid.NewWithoutDashes(),
+			PipelineName: "foo",
+			Inputs: []*ppsclient.JobInput{
+				{Commit: client.NewCommit("bar", uuid.NewWithoutDashes())},
+			},
+		},
+	)
+	require.NoError(t, err)
+	job := &ppsclient.Job{ID: jobInfo.JobID}
+	for i := 0; i < 3; i++ {
+		_, err = apiServer.StartPod(context.Background(), job)
+		require.NoError(t, err)
+	}
+	_, err = apiServer.SucceedPod(context.Background(), job)
+	require.NoError(t, err)
+	_, err = apiServer.FailPod(context.Background(), job)
+	require.NoError(t, err)
+	podCounters, err := apiServer.GetPodCounters(context.Background(), job)
+	require.NoError(t, err)
+	require.Equal(t, &persist.PodCounters{PodsStarted: 3, PodsSucceeded: 1, PodsFailed: 1}, podCounters)
+	_, err = apiServer.ResetPodCounters(context.Background(), job)
+	require.NoError(t, err)
+	podCounters, err = apiServer.GetPodCounters(context.Background(), job)
+	require.NoError(t, err)
+	require.Equal(t, &persist.PodCounters{}, podCounters)
+
+	missing := &ppsclient.Job{ID: uuid.NewWithoutDashes()}
+	_, err = apiServer.GetPodCounters(context.Background(), missing)
+	require.Equal(t, server.ErrJobNotFound, err)
+	_, err = apiServer.ResetPodCounters(context.Background(), missing)
+	require.Equal(t, server.ErrJobNotFound, err)
+}
+
+func testListJobInfosPipelines(t *testing.T, apiServer persist.APIServer) {
+	var jobIDs []string
+	for _, pipelineName := range []string{"foo", "bar", "buzz"} {
+		jobInfo, err := apiServer.CreateJobInfo(
+			context.Background(),
+			&persist.JobInfo{
+				JobID:        uuid.NewWithoutDashes(),
+				PipelineName: pipelineName,
+				Inputs: []*ppsclient.JobInput{
+					{Commit: client.NewCommit("fizz", uuid.NewWithoutDashes())},
+				},
+			},
+		)
+		require.NoError(t, err)
+		jobIDs = append(jobIDs, jobInfo.JobID)
+	}
+	jobInfos, err := apiServer.ListJobInfos(
+		context.Background(),
+		&ppsclient.ListJobRequest{
+			Pipeline:  &ppsclient.Pipeline{Name: "foo"},
+			Pipelines: []*ppsclient.Pipeline{{Name: "buzz"}},
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(jobInfos.JobInfo))
+	// latest to earliest
+	require.Equal(t, jobIDs[2], jobInfos.JobInfo[0].JobID)
+	require.Equal(t, jobIDs[0], jobInfos.JobInfo[1].JobID)
+}
+
+func testCoalescePipelineInfoChanges(t *testing.T, apiServer persist.APIServer) {
+	_, err := apiServer.CreatePipelineInfo(
+		context.Background(),
+		&persist.PipelineInfo{
+			PipelineName: "foo",
+		},
+	)
+	require.NoError(t, err)
+	subscribeServer := &subscribePipelineInfosServer{changes: make(chan *persist.PipelineInfoChange, 10)}
+	go func() {
+		_ = apiServer.SubscribePipelineInfos(
+			&persist.SubscribePipelineInfosRequest{
+				IncludeInitial:       true,
+				CoalesceMilliseconds: 500,
+			},
+			subscribeServer,
+		)
+	}()
+	// once we've seen the initial value the subscription is running
+	require.Equal(t, "foo", (<-subscribeServer.changes).Pipeline.PipelineName)
+	// bar flaps, but we should only hear about its final state
+	for i := 0; i < 3; i++ {
+		_, err = apiServer.CreatePipelineInfo(
+			context.Background(),
+			&persist.PipelineInfo{
+				PipelineName: "bar",
+			},
+		)
+		require.NoError(t, err)
+		_, err = apiServer.DeletePipelineInfo(
+			context.Background(),
+			&ppsclient.Pipeline{Name: "bar"},
+		)
+		require.NoError(t, err)
+	}
+	change := <-subscribeServer.changes
+	require.Equal(t, "bar", change.Pipeline.PipelineName)
+	require.True(t, change.Removed)
+	select {
+	case change := <-subscribeServer.changes:
+		t.Fatalf("unexpected change: %v", change)
+	case <-time.After(time.Second):
+	}
+}
+
+func testValidationErrors(t *testing.T, apiServer persist.APIServer) {
+	_, err := apiServer.CreateJobInfo(context.Background(), &persist.JobInfo{})
+	require.YesError(t, err)
+	requireValidationError(t, "JobID", err)
+	require.Equal(t, "request.JobID should be set", err.Error())
+
+	_, err = apiServer.CreatePipelineInfo(
+		context.Background(),
+		&persist.PipelineInfo{
+			PipelineName: "foo",
+			CreatedAt:    prototime.TimeToTimestamp(time.Now()),
+		},
+	)
+	require.YesError(t, err)
+	requireValidationError(t, "CreatedAt", err)
+	require.Equal(t, "request.CreatedAt should be unset", err.Error())
+
+	_, err = apiServer.CreatePipelineInfo(context.Background(), &persist.PipelineInfo{})
+	require.YesError(t, err)
+	requireValidationError(t, "PipelineName", err)
+	require.Equal(t, "request.PipelineName should be set", err.Error())
+
+	// gRPC clients get both the field and the code
+	_, err = server.NewGRPCAPIServer(apiServer).CreateJobInfo(context.Background(), &persist.JobInfo{})
+	require.Equal(t, codes.InvalidArgument, grpc.Code(err))
+	require.Equal(t, "JobID: request.JobID should be set", grpc.ErrorDesc(err))
+}
+
+// requireValidationError checks that err is the error the servers return for
+// an invalid field of a request.
+func requireValidationError(t *testing.T, field string, err error) {
+	validationErr, ok := err.(*server.ValidationError)
+	require.True(t, ok)
+	require.Equal(t, field, validationErr.Field)
+}
+
+func testListJobInfosLatestPerPipeline(t *testing.T, apiServer persist.APIServer) {
+	latest := make(map[string]string)
+	for i := 0; i < 3; i++ {
+		for _, pipelineName := range []string{"foo", "bar"} {
+			jobInfo, err := apiServer.CreateJobInfo(
+				context.Background(),
+				&persist.JobInfo{
+					JobID:        uuid.NewWithoutDashes(),
+					PipelineName: pipelineName,
+					Inputs: []*ppsclient.JobInput{
+						{Commit: client.NewCommit("fizz", uuid.NewWithoutDashes())},
+					},
+				},
+			)
+			require.NoError(t, err)
+			latest[pipelineName] = jobInfo.JobID
+		}
+	}
+	jobInfos, err := apiServer.ListJobInfos(
+		context.Background(),
+		&ppsclient.ListJobRequest{
+			LatestPerPipeline: true,
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(jobInfos.JobInfo))
+	// bar's job was created last
+	require.Equal(t, latest["bar"], jobInfos.JobInfo[0].JobID)
+	require.Equal(t, "bar", jobInfos.JobInfo[0].PipelineName)
+	require.Equal(t, latest["foo"], jobInfos.JobInfo[1].JobID)
+	require.Equal(t, 1, len(jobInfos.JobInfo[1].Inputs))
+}
+
+func testWaitPipelineDeleted(t *testing.T, apiServer persist.APIServer) {
+	// already gone
+	_, err := apiServer.WaitPipelineDeleted(
+		context.Background(),
+		&persist.WaitPipelineDeletedRequest{
+			Pipeline: &ppsclient.Pipeline{Name: "foo"},
+		},
+	)
+	require.NoError(t, err)
+	_, err = apiServer.WaitPipelineDeleted(context.Background(), &persist.WaitPipelineDeletedRequest{})
+	requireValidationError(t, "Pipeline", err)
+
+	_, err = apiServer.CreatePipelineInfo(
+		context.Background(),
+		&persist.PipelineInfo{
+			PipelineName: "foo",
+		},
+	)
+	require.NoError(t, err)
+	_, err = apiServer.WaitPipelineDeleted(
+		context.Background(),
+		&persist.WaitPipelineDeletedRequest{
+			Pipeline:            &ppsclient.Pipeline{Name: "foo"},
+			TimeoutMilliseconds: 100,
+		},
+	)
+	require.Equal(t, server.ErrTimeout, err)
+
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := apiServer.WaitPipelineDeleted(
+			context.Background(),
+			&persist.WaitPipelineDeletedRequest{
+				Pipeline: &ppsclient.Pipeline{Name: "foo"},
+			},
+		)
+		errCh <- err
+	}()
+	select {
+	case err := <-errCh:
+		t.Fatalf("returned before the pipeline was deleted: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+	_, err = apiServer.DeletePipelineInfo(
+		context.Background(),
+		&ppsclient.Pipeline{Name: "foo"},
+	)
+	require.NoError(t, err)
+	select {
+	case err := <-errCh:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for WaitPipelineDeleted")
+	}
+}
+
+func testListJobInfosSinceToken(t *testing.T, apiServer persist.APIServer) {
+	createJob := func() string {
+		jobInfo, err := apiServer.CreateJobInfo(
+			context.Background(),
+			&persist.JobInfo{
+				JobID:        uuid.NewWithoutDashes(),
+				PipelineName: "foo",
+				Inputs: []*ppsclient.JobInput{
+					{Commit: client.NewCommit("fizz", uuid.NewWithoutDashes())},
+				},
+			},
+		)
+		require.NoError(t, err)
+		return jobInfo.JobID
+	}
+	first := createJob()
+	jobInfos, err := apiServer.ListJobInfos(context.Background(), &ppsclient.ListJobRequest{})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(jobInfos.JobInfo))
+	token := jobInfos.NextToken
+	require.NotEqual(t, "", token)
+
+	second := createJob()
+	_, err = apiServer.CreateJobState(
+		context.Background(),
+		&persist.JobState{
+			JobID: first,
+			State: ppsclient.JobState_JOB_STATE_SUCCESS,
+		},
+	)
+	require.NoError(t, err)
+	createJob()
+	for _, request := range []*ppsclient.ListJobRequest{
+		{SinceToken: token},
+		{SinceToken: token, Pipeline: &ppsclient.Pipeline{Name: "foo"}},
+	} {
+		jobInfos, err = apiServer.ListJobInfos(context.Background(), request)
+		require.NoError(t, err)
+		require.Equal(t, 3, len(jobInfos.JobInfo))
+		jobIDs := make(map[string]bool)
+		for _, jobInfo := range jobInfos.JobInfo {
+			jobIDs[jobInfo.JobID] = true
+		}
+		require.True(t, jobIDs[first])
+		require.True(t, jobIDs[second])
+	}
+
+	token = jobInfos.NextToken
+	jobInfos, err = apiServer.ListJobInfos(context.Background(), &ppsclient.ListJobRequest{SinceToken: token})
+	require.NoError(t, err)
+	require.Equal(t, 0, len(jobInfos.JobInfo))
+	require.Equal(t, token, jobInfos.NextToken)
+
+	_, err = apiServer.ListJobInfos(context.Background(), &ppsclient.ListJobRequest{SinceToken: "foo"})
+	require.YesError(t, err)
+}
+
+func testReindexJobInfos(t *testing.T, apiServer persist.APIServer) {
+	commit := client.NewCommit("fizz", uuid.NewWithoutDashes())
+	_, err := apiServer.CreateJobInfo(
+		context.Background(),
+		&persist.JobInfo{
+			JobID:        uuid.NewWithoutDashes(),
+			PipelineName: "foo",
+			Inputs:       []*ppsclient.JobInput{{Commit: commit}},
+		},
+	)
+	require.NoError(t, err)
+	// the index was generated on create, so there's nothing to do
+	response, err := apiServer.ReindexJobInfos(context.Background(), google_protobuf.EmptyInstance)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), response.Updated)
+	jobInfos, err := apiServer.ListJobInfos(
+		context.Background(),
+		&ppsclient.ListJobRequest{
+			InputCommit: []*pfsclient.Commit{commit},
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(jobInfos.JobInfo))
+}
+
+func testWriteSummaries(t *testing.T, apiServer persist.APIServer) {
+	jobInfo, err := apiServer.CreateJobInfo(
+		context.Background(),
+		&persist.JobInfo{
+			JobID:        uuid.NewWithoutDashes(),
+			PipelineName: "foo",
+			Inputs: []*ppsclient.JobInput{
+				{Commit: client.NewCommit("fizz", uuid.NewWithoutDashes())},
+			},
+		},
+	)
+	require.NoError(t, err)
+	jobState := &persist.JobState{
+		JobID: jobInfo.JobID,
+		State: ppsclient.JobState_JOB_STATE_SUCCESS,
+	}
+	summary, err := apiServer.CreateJobState(context.Background(), jobState)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), summary.Replaced)
+	// the same state again is a no-op
+	summary, err = apiServer.CreateJobState(context.Background(), jobState)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), summary.Replaced)
+	require.Equal(t, uint64(1), summary.Unchanged)
+
+	summary, err = apiServer.DeleteJobInfo(context.Background(), &ppsclient.Job{ID: jobInfo.JobID})
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), summary.Deleted)
+	summary, err = apiServer.DeleteJobInfo(context.Background(), &ppsclient.Job{ID: jobInfo.JobID})
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), summary.Deleted)
+}
+
+func testListJobInfosStream(t *testing.T, apiServer persist.APIServer) {
+	jobIDs := make(map[string]bool)
+	for _, pipelineName := range []string{"foo", "foo", "bar"} {
+		jobInfo, err := apiServer.CreateJobInfo(
+			context.Background(),
+			&persist.JobInfo{
+				JobID:        uuid.NewWithoutDashes(),
+				PipelineName: pipelineName,
+				Inputs: []*ppsclient.JobInput{
+					{Commit: client.NewCommit("fizz", uuid.NewWithoutDashes())},
+				},
+			},
+		)
+		require.NoError(t, err)
+		if pipelineName == "foo" {
+			jobIDs[jobInfo.JobID] = true
+		}
+	}
+	stream := &listJobInfosStreamServer{}
+	require.NoError(t, apiServer.ListJobInfosStream(
+		&ppsclient.ListJobRequest{
+			Pipeline: &ppsclient.Pipeline{Name: "foo"},
+		},
+		stream,
+	))
+	require.Equal(t, 2, len(stream.jobInfos))
+	for _, jobInfo := range stream.jobInfos {
+		require.True(t, jobIDs[jobInfo.JobID])
+	}
+}
+
+func testDeleteJobInfosByCommit(t *testing.T, apiServer persist.APIServer) {
+	commit := client.NewCommit("fizz", uuid.NewWithoutDashes())
+	otherCommit := client.NewCommit("buzz", uuid.NewWithoutDashes())
+	for _, inputCommit := range []*pfsclient.Commit{commit, commit, otherCommit} {
+		_, err := apiServer.CreateJobInfo(
+			context.Background(),
+			&persist.JobInfo{
+				JobID:        uuid.NewWithoutDashes(),
+				PipelineName: "foo",
+				Inputs: []*ppsclient.JobInput{
+					{Commit: inputCommit},
+				},
+			},
+		)
+		require.NoError(t, err)
+	}
+	summary, err := apiServer.DeleteJobInfosByCommit(
+		context.Background(),
+		&persist.DeleteJobInfosByCommitRequest{Commits: []*pfsclient.Commit{commit}},
+	)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), summary.Deleted)
+	summary, err = apiServer.DeleteJobInfosByCommit(
+		context.Background(),
+		&persist.DeleteJobInfosByCommitRequest{Commits: []*pfsclient.Commit{commit}},
+	)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), summary.Deleted)
+
+	jobInfos, err := apiServer.ListJobInfos(context.Background(), &ppsclient.ListJobRequest{})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(jobInfos.JobInfo))
+	require.Equal(t, otherCommit.ID, jobInfos.JobInfo[0].Inputs[0].Commit.ID)
+}
+
+func testGetJobInfosByCommitRange(t *testing.T, apiServer persist.APIServer) {
+	for _, commitID := range []string{"cccccccccc", "aaaaaaaaaa", "bbbbbbbbbb"} {
+		_, err := apiServer.CreateJobInfo(
+			context.Background(),
+			&persist.JobInfo{
+				JobID:        uuid.NewWithoutDashes(),
+				PipelineName: "foo",
+				Inputs: []*ppsclient.JobInput{
+					{Commit: client.NewCommit("fizz", commitID+uuid.NewWithoutDashes())},
+				},
+			},
+		)
+		require.NoError(t, err)
+	}
+	jobInfos, err := apiServer.GetJobInfosByCommitRange(
+		context.Background(),
+		&persist.GetJobInfosByCommitRangeRequest{
+			StartCommitIndex: "bbbbbbbbbb",
+			EndCommitIndex:   "cccccccccc",
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(jobInfos.JobInfo))
+	require.Equal(t, "bbbbbbbbbb", jobInfos.JobInfo[0].CommitIndex)
+
+	jobInfos, err = apiServer.GetJobInfosByCommitRange(
+		context.Background(),
+		&persist.GetJobInfosByCommitRangeRequest{StartCommitIndex: "aaaaaaaaab"},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(jobInfos.JobInfo))
+	require.Equal(t, "bbbbbbbbbb", jobInfos.JobInfo[0].CommitIndex)
+	require.Equal(t, "cccccccccc", jobInfos.JobInfo[1].CommitIndex)
+}
+
+func testListPipelineInfosOrderBy(t *testing.T, apiServer persist.APIServer) {
+	names := []string{"foo", "bar", "baz"}
+	for i, name := range names {
+		_, err := apiServer.CreatePipelineInfo(
+			context.Background(),
+			&persist.PipelineInfo{
+				PipelineName: name,
+				Shard:        uint64(i % 2),
+			},
+		)
+		require.NoError(t, err)
+		// make sure each pipeline gets a distinct timestamp
+		time.Sleep(time.Millisecond)
+	}
+	pipelineInfos, err := apiServer.ListPipelineInfos(
+		context.Background(),
+		&persist.ListPipelineInfosRequest{
+			OrderBy: persist.PipelineInfosOrder_PIPELINE_INFOS_ORDER_CREATED_AT_ASC,
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(pipelineInfos.PipelineInfo))
+	for i, pipelineInfo := range pipelineInfos.PipelineInfo {
+		require.Equal(t, names[i], pipelineInfo.PipelineName)
+	}
+	pipelineInfos, err = apiServer.ListPipelineInfos(
+		context.Background(),
+		&persist.ListPipelineInfosRequest{
+			OrderBy: persist.PipelineInfosOrder_PIPELINE_INFOS_ORDER_CREATED_AT_DESC,
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(pipelineInfos.PipelineInfo))
+	for i, pipelineInfo := range pipelineInfos.PipelineInfo {
+		require.Equal(t, names[len(names)-1-i], pipelineInfo.PipelineName)
+	}
+	// ordering composes with the shard filter
+	pipelineInfos, err = apiServer.ListPipelineInfos(
+		context.Background(),
+		&persist.ListPipelineInfosRequest{
+			Shard:   &persist.Shard{Number: 0},
+			OrderBy: persist.PipelineInfosOrder_PIPELINE_INFOS_ORDER_CREATED_AT_DESC,
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(pipelineInfos.PipelineInfo))
+	require.Equal(t, "baz", pipelineInfos.PipelineInfo[0].PipelineName)
+	require.Equal(t, "foo", pipelineInfos.PipelineInfo[1].PipelineName)
+}
+
+func testUpdatedAt(t *testing.T, apiServer persist.APIServer) {
+	jobInfo, err := apiServer.CreateJobInfo(
+		context.Background(),
+		&persist.JobInfo{
+			JobID:        uuid.NewWithoutDashes(),
+			PipelineName: "foo",
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, jobInfo.CreatedAt, jobInfo.UpdatedAt)
+	job := &ppsclient.Job{ID: jobInfo.JobID}
+	updatedAt := jobInfo.UpdatedAt
+	requireUpdated := func() {
+		jobInfo, err := apiServer.InspectJob(context.Background(), &ppsclient.InspectJobRequest{Job: job})
+		require.NoError(t, err)
+		require.True(t, prototime.TimestampLess(updatedAt, jobInfo.UpdatedAt))
+		updatedAt = jobInfo.UpdatedAt
+	}
+	for _, write := range []func() error{
+		func() error {
+			_, err := apiServer.StartPod(context.Background(), job)
+			return err
+		},
+		func() error {
+			_, err := apiServer.CreateJobState(context.Background(), &persist.JobState{
+				JobID: job.ID,
+				State: ppsclient.JobState_JOB_STATE_SUCCESS,
+			})
+			return err
+		},
+		func() error {
+			_, err := apiServer.CreateJobOutput(context.Background(), &persist.JobOutput{
+				JobID:        job.ID,
+				OutputCommit: client.NewCommit("bar", uuid.NewWithoutDashes()),
+			})
+			return err
+		},
+	} {
+		time.Sleep(time.Millisecond)
+		require.NoError(t, write())
+		requireUpdated()
+	}
+}
+
+func testListJobInfosExcludePipelines(t *testing.T, apiServer persist.APIServer) {
+	for _, pipelineName := range []string{"foo", "bar", "bookkeeping"} {
+		_, err := apiServer.CreateJobInfo(
+			context.Background(),
+			&persist.JobInfo{
+				JobID:        uuid.NewWithoutDashes(),
+				PipelineName: pipelineName,
+			},
+		)
+		require.NoError(t, err)
+	}
+	jobInfos, err := apiServer.ListJobInfos(
+		context.Background(),
+		&ppsclient.ListJobRequest{
+			ExcludePipelines: []*ppsclient.Pipeline{{Name: "bookkeeping"}},
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(jobInfos.JobInfo))
+	for _, jobInfo := range jobInfos.JobInfo {
+		require.True(t, jobInfo.PipelineName != "bookkeeping")
+	}
+	// composes with the pipelines filter
+	jobInfos, err = apiServer.ListJobInfos(
+		context.Background(),
+		&ppsclient.ListJobRequest{
+			Pipelines:        []*ppsclient.Pipeline{{Name: "foo"}, {Name: "bar"}},
+			ExcludePipelines: []*ppsclient.Pipeline{{Name: "bar"}},
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(jobInfos.JobInfo))
+	require.Equal(t, "foo", jobInfos.JobInfo[0].PipelineName)
+}
+
+func testRestartJob(t *testing.T, apiServer persist.APIServer) {
+	_, err := apiServer.RestartJob(context.Background(), &ppsclient.Job{ID: "nonexistent"})
+	require.Equal(t, server.ErrJobNotFound, err)
+
+	jobInfo, err := apiServer.CreateJobInfo(
+		context.Background(),
+		&persist.JobInfo{
+			JobID:        uuid.NewWithoutDashes(),
+			PipelineName: "foo",
+		},
+	)
+	require.NoError(t, err)
+	job := &ppsclient.Job{ID: jobInfo.JobID}
+	_, err = apiServer.StartPod(context.Background(), job)
+	require.NoError(t, err)
+	_, err = apiServer.FailPod(context.Background(), job)
+	require.NoError(t, err)
+	_, err = apiServer.CreateJobOutput(context.Background(), &persist.JobOutput{
+		JobID:        job.ID,
+		OutputCommit: client.NewCommit("bar", uuid.NewWithoutDashes()),
+	})
+	require.NoError(t, err)
+	_, err = apiServer.CreateJobState(context.Background(), &persist.JobState{
+		JobID: job.ID,
+		State: ppsclient.JobState_JOB_STATE_FAILURE,
+	})
+	require.NoError(t, err)
+
+	_, err = apiServer.RestartJob(context.Background(), job)
+	require.NoError(t, err)
+	jobInfo, err = apiServer.InspectJob(context.Background(), &ppsclient.InspectJobRequest{Job: job})
+	require.NoError(t, err)
+	require.Equal(t, ppsclient.JobState_JOB_STATE_RUNNING, jobInfo.State)
+	require.Equal(t, uint64(0), jobInfo.PodsStarted)
+	require.Equal(t, uint64(0), jobInfo.PodsFailed)
+	require.True(t, jobInfo.OutputCommit == nil)
+	require.Equal(t, "foo", jobInfo.PipelineName)
+	require.Equal(t, uint64(1), jobInfo.Restarts)
+
+	_, err = apiServer.RestartJob(context.Background(), job)
+	require.NoError(t, err)
+	jobInfo, err = apiServer.InspectJob(context.Background(), &ppsclient.InspectJobRequest{Job: job})
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), jobInfo.Restarts)
+}
+
+func testGetJobInfos(t *testing.T, apiServer persist.APIServer) {
+	var jobs []*ppsclient.Job
+	for i := 0; i < 2; i++ {
+		jobInfo, err := apiServer.CreateJobInfo(
+			context.Background(),
+			&persist.JobInfo{
+				JobID:        uuid.NewWithoutDashes(),
+				PipelineName: "foo",
+			},
+		)
+		require.NoError(t, err)
+		jobs = append(jobs, &ppsclient.Job{ID: jobInfo.JobID})
+	}
+	missing := &ppsclient.Job{ID: uuid.NewWithoutDashes()}
+	response, err := apiServer.GetJobInfos(
+		context.Background(),
+		&persist.GetJobInfosRequest{
+			Job: []*ppsclient.Job{jobs[1], missing, jobs[0]},
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(response.JobInfo))
+	require.Equal(t, jobs[1].ID, response.JobInfo[0].JobID)
+	require.Equal(t, jobs[0].ID, response.JobInfo[1].JobID)
+	require.Equal(t, 1, len(response.Missing))
+	require.Equal(t, missing.ID, response.Missing[0].ID)
+}
+
+func testListJobInfosStateAndCreatedAfter(t *testing.T, apiServer persist.APIServer) {
+	var jobInfos []*persist.JobInfo
+	for _, job := range []struct {
+		pipelineName string
+		state        ppsclient.JobState
+	}{
+		{"foo", ppsclient.JobState_JOB_STATE_FAILURE},
+		{"foo", ppsclient.JobState_JOB_STATE_FAILURE},
+		{"foo", ppsclient.JobState_JOB_STATE_SUCCESS},
+		{"bar", ppsclient.JobState_JOB_STATE_FAILURE},
+	} {
+		jobInfo, err := apiServer.CreateJobInfo(
+			context.Background(),
+			&persist.JobInfo{
+				JobID:        uuid.NewWithoutDashes(),
+				PipelineName: job.pipelineName,
+			},
+		)
+		require.NoError(t, err)
+		_, err = apiServer.CreateJobState(context.Background(), &persist.JobState{
+			JobID: jobInfo.JobID,
+			State: job.state,
+		})
+		require.NoError(t, err)
+		jobInfos = append(jobInfos, jobInfo)
+	}
+	failed := []ppsclient.JobState{ppsclient.JobState_JOB_STATE_FAILURE}
+	// pipeline, state and time together use the compound index
+	response, err := apiServer.ListJobInfos(
+		context.Background(),
+		&ppsclient.ListJobRequest{
+			Pipeline:     &ppsclient.Pipeline{Name: "foo"},
+			State:        failed,
+			CreatedAfter: jobInfos[1].CreatedAt,
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(response.JobInfo))
+	require.Equal(t, jobInfos[1].JobID, response.JobInfo[0].JobID)
+	// narrower combinations filter what the other indexes return
+	response, err = apiServer.ListJobInfos(
+		context.Background(),
+		&ppsclient.ListJobRequest{
+			Pipeline: &ppsclient.Pipeline{Name: "foo"},
+			State:    failed,
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(response.JobInfo))
+	for _, jobInfo := range response.JobInfo {
+		require.Equal(t, ppsclient.JobState_JOB_STATE_FAILURE, jobInfo.State)
+	}
+	response, err = apiServer.ListJobInfos(
+		context.Background(),
+		&ppsclient.ListJobRequest{
+			State:        failed,
+			CreatedAfter: jobInfos[1].CreatedAt,
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(response.JobInfo))
+	require.Equal(t, jobInfos[3].JobID, response.JobInfo[0].JobID)
+	require.Equal(t, jobInfos[1].JobID, response.JobInfo[1].JobID)
+}
+
+func testListPipelineInfosPages(t *testing.T, apiServer persist.APIServer) {
+	names := []string{"a", "b", "c", "d", "e"}
+	for i, name := range names {
+		_, err := apiServer.CreatePipelineInfo(
+			context.Background(),
+			&persist.PipelineInfo{
+				PipelineName: name,
+				Shard:        uint64(i % 2),
+			},
+		)
+		require.NoError(t, err)
+	}
+	listPages := func(shard *persist.Shard) []string {
+		var result []string
+		request := &persist.ListPipelineInfosRequest{Shard: shard, Limit: 2}
+		for {
+			pipelineInfos, err := apiServer.ListPipelineInfos(context.Background(), request)
+			require.NoError(t, err)
+			require.True(t, len(pipelineInfos.PipelineInfo) <= 2)
+			for _, pipelineInfo := range pipelineInfos.PipelineInfo {
+				result = append(result, pipelineInfo.PipelineName)
+			}
+			if pipelineInfos.NextPageToken == "" {
+				return result
+			}
+			request.PageToken = pipelineInfos.NextPageToken
+		}
+	}
+	require.Equal(t, names, listPages(nil))
+	require.Equal(t, []string{"a", "c", "e"}, listPages(&persist.Shard{Number: 0}))
+
+	_, err := apiServer.ListPipelineInfos(
+		context.Background(),
+		&persist.ListPipelineInfosRequest{
+			Limit:   2,
+			OrderBy: persist.PipelineInfosOrder_PIPELINE_INFOS_ORDER_CREATED_AT_ASC,
+		},
+	)
+	require.YesError(t, err)
+}
+
+func testAggregateJobStates(t *testing.T, apiServer persist.APIServer) {
+	for _, job := range []struct {
+		pipelineName string
+		state        ppsclient.JobState
+	}{
+		{"foo", ppsclient.JobState_JOB_STATE_RUNNING},
+		{"foo", ppsclient.JobState_JOB_STATE_FAILURE},
+		{"foo", ppsclient.JobState_JOB_STATE_FAILURE},
+		{"bar", ppsclient.JobState_JOB_STATE_SUCCESS},
+	} {
+		jobInfo, err := apiServer.CreateJobInfo(
+			context.Background(),
+			&persist.JobInfo{
+				JobID:        uuid.NewWithoutDashes(),
+				PipelineName: job.pipelineName,
+			},
+		)
+		require.NoError(t, err)
+		_, err = apiServer.CreateJobState(context.Background(), &persist.JobState{
+			JobID: jobInfo.JobID,
+			State: job.state,
+		})
+		require.NoError(t, err)
+	}
+	counts, err := apiServer.AggregateJobStates(context.Background(), &ppsclient.Pipeline{})
+	require.NoError(t, err)
+	require.Equal(t, map[string]uint64{
+		ppsclient.JobState_JOB_STATE_RUNNING.String(): 1,
+		ppsclient.JobState_JOB_STATE_FAILURE.String(): 2,
+		ppsclient.JobState_JOB_STATE_SUCCESS.String(): 1,
+	}, counts.Counts)
+	counts, err = apiServer.AggregateJobStates(context.Background(), &ppsclient.Pipeline{Name: "foo"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]uint64{
+		ppsclient.JobState_JOB_STATE_RUNNING.String(): 1,
+		ppsclient.JobState_JOB_STATE_FAILURE.String(): 2,
+	}, counts.Counts)
+}
+
+// testSubscriberBehind expects apiServer to have a SubscribeBufferSize of 1.
+func testSubscriberBehind(t *testing.T, apiServer persist.APIServer) {
+	subscribeServer := &blockingSubscribeServer{
+		sending: make(chan struct{}, 1),
+		release: make(chan struct{}),
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- apiServer.SubscribePipelineInfos(
+			&persist.SubscribePipelineInfosRequest{},
+			subscribeServer,
+		)
+	}()
+	createPipeline := func(i int) {
+		_, err := apiServer.CreatePipelineInfo(
+			context.Background(),
+			&persist.PipelineInfo{
+				PipelineName: fmt.Sprintf("pipeline%d", i),
+			},
+		)
+		require.NoError(t, err)
+	}
+	// create pipelines until the subscription is running and stuck sending
+	i := 0
+	for sending := false; !sending; i++ {
+		createPipeline(i)
+		select {
+		case <-subscribeServer.sending:
+			sending = true
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	for j := 0; j < 5; j++ {
+		createPipeline(i + j)
+	}
+	close(subscribeServer.release)
+	select {
+	case err := <-errCh:
+		require.Equal(t, server.ErrSubscriberBehind, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the subscription to end")
+	}
+
+	// the current pipelines count against the buffer too
+	subscribeServer = &blockingSubscribeServer{
+		sending: make(chan struct{}, 1),
+		release: make(chan struct{}),
+	}
+	go func() {
+		errCh <- apiServer.SubscribePipelineInfos(
+			&persist.SubscribePipelineInfosRequest{IncludeInitial: true},
+			subscribeServer,
+		)
+	}()
+	<-subscribeServer.sending
+	// give the server time to read the rest of them
+	time.Sleep(100 * time.Millisecond)
+	close(subscribeServer.release)
+	select {
+	case err := <-errCh:
+		require.Equal(t, server.ErrSubscriberBehind, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the subscription to end")
+	}
+}
+
+func testDeletePipelineAndJobs(t *testing.T, apiServer persist.APIServer) {
+	for _, pipelineName := range []string{"foo", "bar"} {
+		_, err := apiServer.CreatePipelineInfo(
+			context.Background(),
+			&persist.PipelineInfo{
+				PipelineName: pipelineName,
+			},
+		)
+		require.NoError(t, err)
+	}
+	for _, pipelineName := range []string{"foo", "foo", "bar"} {
+		_, err := apiServer.CreateJobInfo(
+			context.Background(),
+			&persist.JobInfo{
+				JobID:        uuid.NewWithoutDashes(),
+				PipelineName: pipelineName,
+			},
+		)
+		require.NoError(t, err)
+	}
+
+	_, err := apiServer.DeletePipelineAndJobs(
+		context.Background(),
+		&persist.DeletePipelineAndJobsRequest{Pipeline: &ppsclient.Pipeline{Name: "foo"}},
+	)
+	require.YesError(t, err)
+	requireValidationError(t, "Cascade", err)
+	_, err = apiServer.GetPipelineInfo(context.Background(), &ppsclient.Pipeline{Name: "foo"})
+	require.NoError(t, err)
+
+	response, err := apiServer.DeletePipelineAndJobs(
+		context.Background(),
+		&persist.DeletePipelineAndJobsRequest{
+			Pipeline: &ppsclient.Pipeline{Name: "foo"},
+			Cascade:  true,
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), response.PipelineSummary.Deleted)
+	require.Equal(t, uint64(2), response.JobSummary.Deleted)
+	_, err = apiServer.GetPipelineInfo(context.Background(), &ppsclient.Pipeline{Name: "foo"})
+	require.YesError(t, err)
+
+	jobInfos, err := apiServer.ListJobInfos(context.Background(), &ppsclient.ListJobRequest{})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(jobInfos.JobInfo))
+	require.Equal(t, "bar", jobInfos.JobInfo[0].PipelineName)
+	_, err = apiServer.GetPipelineInfo(context.Background(), &ppsclient.Pipeline{Name: "bar"})
+	require.NoError(t, err)
+
+	response, err = apiServer.DeletePipelineAndJobs(
+		context.Background(),
+		&persist.DeletePipelineAndJobsRequest{
+			Pipeline: &ppsclient.Pipeline{Name: "foo"},
+			Cascade:  true,
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), response.PipelineSummary.Deleted)
+	require.Equal(t, uint64(0), response.JobSummary.Deleted)
+}
+
+// testShardValidation expects a server with NumShards set to 2.
+func testShardValidation(t *testing.T, apiServer persist.APIServer) {
+	_, err := apiServer.CreatePipelineInfo(
+		context.Background(),
+		&persist.PipelineInfo{
+			PipelineName: "foo",
+			Shard:        1,
+		},
+	)
+	require.NoError(t, err)
+	pipelineInfos, err := apiServer.ListPipelineInfos(
+		context.Background(),
+		&persist.ListPipelineInfosRequest{Shard: &persist.Shard{Number: 1}},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(pipelineInfos.PipelineInfo))
+
+	_, err = apiServer.ListPipelineInfos(
+		context.Background(),
+		&persist.ListPipelineInfosRequest{Shard: &persist.Shard{Number: 2}},
+	)
+	requireValidationError(t, "Shard", err)
+	require.Equal(t, "request.Shard.Number should be less than 2, got 2", err.Error())
+
+	err = apiServer.SubscribePipelineInfos(
+		&persist.SubscribePipelineInfosRequest{Shard: &persist.Shard{Number: 2}},
+		&subscribePipelineInfosServer{changes: make(chan *persist.PipelineInfoChange, 1)},
+	)
+	requireValidationError(t, "Shard", err)
+
+	_, err = apiServer.ListPipelineNames(
+		context.Background(),
+		&persist.ListPipelineNamesRequest{Shard: &persist.Shard{Number: 2}},
+	)
+	requireValidationError(t, "Shard", err)
+}
+
+func testListJobInfosPipelineVersion(t *testing.T, apiServer persist.APIServer) {
+	var jobInfos []*persist.JobInfo
+	for _, job := range []struct {
+		pipelineName    string
+		pipelineVersion uint64
+	}{
+		{"foo", 1},
+		{"foo", 2},
+		{"foo", 2},
+		{"bar", 2},
+		// jobs from before versions were recorded
+		{"foo", 0},
+	} {
+		jobInfo, err := apiServer.CreateJobInfo(
+			context.Background(),
+			&persist.JobInfo{
+				JobID:           uuid.NewWithoutDashes(),
+				PipelineName:    job.pipelineName,
+				PipelineVersion: job.pipelineVersion,
+			},
+		)
+		require.NoError(t, err)
+		jobInfos = append(jobInfos, jobInfo)
+	}
+	jobIDs := func(request *ppsclient.ListJobRequest) []string {
+		response, err := apiServer.ListJobInfos(context.Background(), request)
+		require.NoError(t, err)
+		var result []string
+		for _, jobInfo := range response.JobInfo {
+			result = append(result, jobInfo.JobID)
+		}
+		return result
+	}
+	// latest to earliest
+	require.Equal(t, []string{jobInfos[2].JobID, jobInfos[1].JobID}, jobIDs(&ppsclient.ListJobRequest{
+		Pipeline:        &ppsclient.Pipeline{Name: "foo"},
+		PipelineVersion: 2,
+	}))
+	require.Equal(t, []string{jobInfos[0].JobID}, jobIDs(&ppsclient.ListJobRequest{
+		Pipeline:        &ppsclient.Pipeline{Name: "foo"},
+		PipelineVersion: 1,
+	}))
+	require.Equal(t, 3, len(jobIDs(&ppsclient.ListJobRequest{
+		Pipelines:       []*ppsclient.Pipeline{{Name: "foo"}, {Name: "bar"}},
+		PipelineVersion: 2,
+	})))
+	require.Equal(t, 4, len(jobIDs(&ppsclient.ListJobRequest{
+		Pipeline: &ppsclient.Pipeline{Name: "foo"},
+	})))
+
+	_, err := apiServer.ListJobInfos(context.Background(), &ppsclient.ListJobRequest{PipelineVersion: 2})
+	require.YesError(t, err)
+	requireValidationError(t, "PipelineVersion", err)
+}
+
+func testPing(t *testing.T, apiServer persist.APIServer) {
+	response, err := apiServer.Ping(context.Background(), google_protobuf.EmptyInstance)
+	require.NoError(t, err)
+	require.NotNil(t, response.RoundTrip)
+	require.True(t, prototime.DurationFromProto(response.RoundTrip) >= 0)
+}
+
+func testUpdatePipelineInfo(t *testing.T, apiServer persist.APIServer) {
+	_, err := apiServer.UpdatePipelineInfo(
+		context.Background(),
+		&persist.PipelineInfo{PipelineName: "foo", Version: 1},
+	)
+	require.Equal(t, server.ErrPipelineNotFound, err)
+
+	created, err := apiServer.CreatePipelineInfo(
+		context.Background(),
+		&persist.PipelineInfo{
+			PipelineName: "foo",
+			Parallelism:  1,
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), created.Version)
+
+	pipelineInfo, err := apiServer.GetPipelineInfo(context.Background(), &ppsclient.Pipeline{Name: "foo"})
+	require.NoError(t, err)
+	pipelineInfo.Parallelism = 2
+	updated, err := apiServer.UpdatePipelineInfo(context.Background(), pipelineInfo)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), updated.Version)
+	require.Equal(t, uint64(2), updated.Parallelism)
+	require.Equal(t, created.CreatedAt.Seconds, updated.CreatedAt.Seconds)
+	require.Equal(t, created.CreatedAt.Nanos, updated.CreatedAt.Nanos)
+
+	// pipelineInfo still has the version from before the update
+	pipelineInfo.Parallelism = 3
+	_, err = apiServer.UpdatePipelineInfo(context.Background(), pipelineInfo)
+	require.Equal(t, server.ErrPipelineVersionMismatch, err)
+	pipelineInfo, err = apiServer.GetPipelineInfo(context.Background(), &ppsclient.Pipeline{Name: "foo"})
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), pipelineInfo.Version)
+	require.Equal(t, uint64(2), pipelineInfo.Parallelism)
+}
+
+func testExportJobInfos(t *testing.T, apiServer persist.APIServer) {
+	jobIDs := make(map[string]bool)
+	for _, pipelineName := range []string{"foo", "foo", "foo", "bar"} {
+		jobInfo, err := apiServer.CreateJobInfo(
+			context.Background(),
+			&persist.JobInfo{
+				JobID:        uuid.NewWithoutDashes(),
+				PipelineName: pipelineName,
+				Inputs: []*ppsclient.JobInput{
+					{Commit: client.NewCommit("fizz", uuid.NewWithoutDashes())},
+				},
+			},
+		)
+		require.NoError(t, err)
+		if pipelineName == "foo" {
+			jobIDs[jobInfo.JobID] = true
+		}
+	}
+	stream := &exportJobInfosServer{ctx: context.Background()}
+	require.NoError(t, apiServer.ExportJobInfos(&ppsclient.Pipeline{Name: "foo"}, stream))
+	require.Equal(t, 3, len(stream.jobInfos))
+	for _, jobInfo := range stream.jobInfos {
+		require.True(t, jobIDs[jobInfo.JobID])
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	stream = &exportJobInfosServer{ctx: ctx}
+	require.Equal(t, context.Canceled, apiServer.ExportJobInfos(&ppsclient.Pipeline{Name: "foo"}, stream))
+
+	err := apiServer.ExportJobInfos(&ppsclient.Pipeline{}, &exportJobInfosServer{ctx: context.Background()})
+	requireValidationError(t, "Name", err)
+}
+
+func testSkipCommitIndex(t *testing.T, apiServer persist.APIServer) {
+	commit := client.NewCommit("fizz", uuid.NewWithoutDashes())
+	var indexed string
+	for _, skip := range []bool{false, true} {
+		jobInfo, err := apiServer.CreateJobInfo(
+			context.Background(),
+			&persist.JobInfo{
+				JobID:           uuid.NewWithoutDashes(),
+				PipelineName:    "foo",
+				Inputs:          []*ppsclient.JobInput{{Commit: commit}},
+				SkipCommitIndex: skip,
+			},
+		)
+		require.NoError(t, err)
+		if skip {
+			require.Equal(t, "", jobInfo.CommitIndex)
+		} else {
+			require.NotEqual(t, "", jobInfo.CommitIndex)
+			indexed = jobInfo.JobID
+		}
+	}
+	jobInfos, err := apiServer.ListJobInfos(
+		context.Background(),
+		&ppsclient.ListJobRequest{
+			InputCommit: []*pfsclient.Commit{commit},
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(jobInfos.JobInfo))
+	require.Equal(t, indexed, jobInfos.JobInfo[0].JobID)
+
+	// reindexing leaves the skipped job alone
+	response, err := apiServer.ReindexJobInfos(context.Background(), google_protobuf.EmptyInstance)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), response.Updated)
+}
+
+// testJobRetention needs a server with AllowImport, a JobRetention of an
+// hour and a short PurgeInterval.
+func testJobRetention(t *testing.T, apiServer persist.APIServer) {
+	old, err := apiServer.ImportJobInfo(
+		context.Background(),
+		&persist.JobInfo{
+			JobID:     uuid.NewWithoutDashes(),
+			CreatedAt: prototime.TimeToTimestamp(time.Now().Add(-2 * time.Hour)),
+		},
+	)
+	require.NoError(t, err)
+	recent, err := apiServer.CreateJobInfo(
+		context.Background(),
+		&persist.JobInfo{
+			JobID: uuid.NewWithoutDashes(),
+		},
+	)
+	require.NoError(t, err)
+	request := &persist.GetJobInfosRequest{
+		Job: []*ppsclient.Job{{ID: old.JobID}, {ID: recent.JobID}},
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		response, err := apiServer.GetJobInfos(context.Background(), request)
+		require.NoError(t, err)
+		if len(response.Missing) > 0 {
+			require.Equal(t, 1, len(response.Missing))
+			require.Equal(t, old.JobID, response.Missing[0].ID)
+			require.Equal(t, recent.JobID, response.JobInfo[0].JobID)
+			return
+		}
+		require.True(t, time.Now().Before(deadline), "old job wasn't purged")
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// testCreateJobInfoRetry needs a server with IdempotentCreate.
+func testCreateJobInfoRetry(t *testing.T, apiServer persist.APIServer) {
+	jobInfo := &persist.JobInfo{
+		JobID:        uuid.NewWithoutDashes(),
+		PipelineName: "foo",
+		Inputs: []*ppsclient.JobInput{
+			{Commit: client.NewCommit("bar", uuid.NewWithoutDashes())},
+		},
+	}
+	created, err := apiServer.CreateJobInfo(context.Background(), proto.Clone(jobInfo).(*persist.JobInfo))
+	require.NoError(t, err)
+	retried, err := apiServer.CreateJobInfo(context.Background(), proto.Clone(jobInfo).(*persist.JobInfo))
+	require.NoError(t, err)
+	require.Equal(t, created.CreatedAt, retried.CreatedAt)
+	require.Equal(t, created.CommitIndex, retried.CommitIndex)
+
+	jobInfo.PipelineName = "buzz"
+	_, err = apiServer.CreateJobInfo(context.Background(), proto.Clone(jobInfo).(*persist.JobInfo))
+	require.Equal(t, server.ErrJobExists, err)
+}
+
+func testListPipelineNames(t *testing.T, apiServer persist.APIServer) {
+	for i, name := range []string{"foo", "bar", "baz"} {
+		_, err := apiServer.CreatePipelineInfo(
+			context.Background(),
+			&persist.PipelineInfo{
+				PipelineName: name,
+				Shard:        uint64(i % 2),
+			},
+		)
+		require.NoError(t, err)
+	}
+	names, err := apiServer.ListPipelineNames(context.Background(), &persist.ListPipelineNamesRequest{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"bar", "baz", "foo"}, names.Name)
+	names, err = apiServer.ListPipelineNames(
+		context.Background(),
+		&persist.ListPipelineNamesRequest{Shard: &persist.Shard{Number: 0}},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []string{"baz", "foo"}, names.Name)
+}
+
+func testNotFound(t *testing.T, apiServer persist.APIServer) {
+	_, err := apiServer.InspectJob(
+		context.Background(),
+		&ppsclient.InspectJobRequest{Job: &ppsclient.Job{ID: uuid.NewWithoutDashes()}},
+	)
+	require.Equal(t, server.ErrJobNotFound, err)
+	_, err = apiServer.GetPipelineInfo(context.Background(), &ppsclient.Pipeline{Name: "foo"})
+	require.Equal(t, server.ErrPipelineNotFound, err)
+}
+
+func testListJobInfosOrder(t *testing.T, apiServer persist.APIServer) {
+	var jobIDs []string
+	for i := 0; i < 3; i++ {
+		jobInfo, err := apiServer.CreateJobInfo(
+			context.Background(),
+			&persist.JobInfo{
+				JobID:        uuid.NewWithoutDashes(),
+				PipelineName: "foo",
+			},
+		)
+		require.NoError(t, err)
+		// newest first
+		jobIDs = append([]string{jobInfo.JobID}, jobIDs...)
+		time.Sleep(time.Millisecond)
+	}
+	for _, request := range []*ppsclient.ListJobRequest{
+		{},
+		{Pipeline: &ppsclient.Pipeline{Name: "foo"}},
+	} {
+		jobInfos, err := apiServer.ListJobInfos(context.Background(), request)
+		require.NoError(t, err)
+		var ids []string
+		for _, jobInfo := range jobInfos.JobInfo {
+			ids = append(ids, jobInfo.JobID)
+		}
+		require.Equal(t, jobIDs, ids)
+	}
+}
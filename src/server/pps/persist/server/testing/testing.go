@@ -9,12 +9,17 @@ import (
 	"github.com/pachyderm/pachyderm/src/server/pps/persist/server"
 )
 
-func RunTestWithRethinkAPIServer(t *testing.T, testFunc func(t *testing.T, persistAPIServer persist.APIServer)) {
+// testAddress is the RethinkDB the tests run against.
+const testAddress = "0.0.0.0:28015"
+
+// RunTestWithRethinkAPIServer runs testFunc against a rethink server, created
+// with opts, on a database of its own. opts can be nil.
+func RunTestWithRethinkAPIServer(t *testing.T, opts *server.Options, testFunc func(t *testing.T, persistAPIServer persist.APIServer)) {
 	if testing.Short() {
 		t.Skip("Skipping test because of short mode.")
 	}
 
-	apiServer, err := NewTestRethinkAPIServer()
+	apiServer, err := NewTestRethinkAPIServer(opts)
 	require.NoError(t, err)
 	defer func() {
 		require.NoError(t, apiServer.Close())
@@ -22,11 +27,33 @@ func RunTestWithRethinkAPIServer(t *testing.T, testFunc func(t *testing.T, persi
 	testFunc(t, apiServer)
 }
 
-func NewTestRethinkAPIServer() (server.APIServer, error) {
-	address := "0.0.0.0:28015"
-	databaseName := uuid.NewWithoutDashes()
-	if err := server.InitDBs(address, databaseName); err != nil {
+// RunTestWithMemoryAPIServer is like RunTestWithRethinkAPIServer but runs
+// testFunc against the in memory server, so it needs no RethinkDB and runs
+// in short mode too.
+func RunTestWithMemoryAPIServer(t *testing.T, testFunc func(t *testing.T, persistAPIServer persist.APIServer)) {
+	apiServer := server.NewMemoryAPIServer(nil)
+	defer func() {
+		require.NoError(t, apiServer.Close())
+	}()
+	testFunc(t, apiServer)
+}
+
+// NewTestRethinkAPIServer creates a rethink server with opts on a new
+// database. opts can be nil.
+func NewTestRethinkAPIServer(opts *server.Options) (server.APIServer, error) {
+	databaseName, err := newTestDatabase(opts)
+	if err != nil {
 		return nil, err
 	}
-	return server.NewRethinkAPIServer(address, databaseName)
+	return server.NewRethinkAPIServer(testAddress, databaseName, opts)
+}
+
+// newTestDatabase creates a database with the tables opts asks for and
+// returns its name.
+func newTestDatabase(opts *server.Options) (string, error) {
+	databaseName := uuid.NewWithoutDashes()
+	if err := server.InitDBs(testAddress, databaseName, opts); err != nil {
+		return "", err
+	}
+	return databaseName, nil
 }
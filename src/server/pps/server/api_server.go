@@ -19,6 +19,7 @@ import (
 	"github.com/pachyderm/pachyderm/src/server/pkg/metrics"
 	ppsserver "github.com/pachyderm/pachyderm/src/server/pps"
 	"github.com/pachyderm/pachyderm/src/server/pps/persist"
+	persist_server "github.com/pachyderm/pachyderm/src/server/pps/persist/server"
 
 	"github.com/cenkalti/backoff"
 	"go.pedge.io/lion/proto"
@@ -869,10 +870,13 @@ func (a *apiServer) AddShard(shard uint64) error {
 	}
 	a.shardCancelFuncs[shard] = cancel
 
-	client, err := persistClient.SubscribePipelineInfos(ctx, &persist.SubscribePipelineInfosRequest{
-		IncludeInitial: true,
-		Shard:          &persist.Shard{shard},
-	})
+	subscribe := func() (persist.API_SubscribePipelineInfosClient, error) {
+		return persistClient.SubscribePipelineInfos(ctx, &persist.SubscribePipelineInfosRequest{
+			IncludeInitial: true,
+			Shard:          &persist.Shard{shard},
+		})
+	}
+	client, err := subscribe()
 	if err != nil {
 		return err
 	}
@@ -880,7 +884,19 @@ func (a *apiServer) AddShard(shard uint64) error {
 	go func() {
 		for {
 			pipelineChange, err := client.Recv()
+			if err != nil && strings.Contains(err.Error(), persist_server.ErrSubscriberBehind.Error()) {
+				// We missed changes, subscribing again with IncludeInitial
+				// sends every pipeline as it is now, runPipeline skips the
+				// ones that are already running.
+				client, err = subscribe()
+				if err == nil {
+					continue
+				}
+			}
 			if err != nil {
+				if !isContextCancelled(err) {
+					protolion.Printf("error subscribing to pipelines of shard %d: %v", shard, err)
+				}
 				return
 			}
 
@@ -1268,6 +1284,7 @@ func newJobInfo(persistJobInfo *persist.JobInfo) (*ppsclient.JobInfo, error) {
 		CreatedAt:    persistJobInfo.CreatedAt,
 		OutputCommit: persistJobInfo.OutputCommit,
 		State:        persistJobInfo.State,
+		Restarts:     persistJobInfo.Restarts,
 	}, nil
 }
 